@@ -0,0 +1,65 @@
+package librariesio
+
+import (
+	"context"
+	"net/http"
+)
+
+// The interfaces below are implemented by the services of Client. Code which
+// depends on one of them rather than *Client can be tested with a fake, such
+// as librariesiotest.ProjectsMock, instead of an HTTP server:
+//
+//	func Latest(ctx context.Context, projects librariesio.ProjectsAPI, plat, name string) (string, error)
+//
+//	version, err := Latest(ctx, client.Projects, "pypi", "cookiecutter")
+
+// ProjectsAPI is the set of project endpoints implemented by
+// ProjectsService
+type ProjectsAPI interface {
+	Get(ctx context.Context, plat, name string) (*Project, *http.Response, error)
+	GetByPURL(ctx context.Context, purl string) (*Project, *http.Response, error)
+	Deps(ctx context.Context, plat, name, ver string) (*Project, *http.Response, error)
+	Dependents(ctx context.Context, plat, name string, opt *ListOptions) ([]*Project, *http.Response, error)
+	Contributors(ctx context.Context, plat, name string, opt *ListOptions) ([]*User, *http.Response, error)
+	SourceRank(ctx context.Context, plat, name string) (*SourceRank, *http.Response, error)
+}
+
+// UsersAPI is the set of GitHub user endpoints implemented by UsersService
+type UsersAPI interface {
+	Get(ctx context.Context, login string) (*User, *http.Response, error)
+	Projects(ctx context.Context, login string, opt *ListOptions) ([]*Project, *http.Response, error)
+	Repositories(ctx context.Context, login string, opt *ListOptions) ([]*Repository, *http.Response, error)
+}
+
+// RepositoriesAPI is the set of GitHub repository endpoints implemented by
+// RepositoriesService
+type RepositoriesAPI interface {
+	Get(ctx context.Context, owner, name string) (*Repository, *http.Response, error)
+	Deps(ctx context.Context, owner, name string) (*Repository, *http.Response, error)
+}
+
+// SearchAPI is the search endpoint implemented by SearchService
+type SearchAPI interface {
+	Projects(ctx context.Context, q string, opt *SearchOptions) ([]*Project, *http.Response, error)
+}
+
+// SubscriptionsAPI is the set of subscription endpoints implemented by
+// SubscriptionsService
+type SubscriptionsAPI interface {
+	List(ctx context.Context, opt *ListOptions) ([]*Subscription, *http.Response, error)
+}
+
+// PlatformsAPI is the platforms endpoint, which is implemented by Client
+// itself as it doesn't belong to a service
+type PlatformsAPI interface {
+	Platforms(ctx context.Context) ([]*Platform, *http.Response, error)
+}
+
+var (
+	_ PlatformsAPI     = (*Client)(nil)
+	_ ProjectsAPI      = (*ProjectsService)(nil)
+	_ UsersAPI         = (*UsersService)(nil)
+	_ RepositoriesAPI  = (*RepositoriesService)(nil)
+	_ SearchAPI        = (*SearchService)(nil)
+	_ SubscriptionsAPI = (*SubscriptionsService)(nil)
+)
@@ -0,0 +1,282 @@
+package librariesio
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// DefaultAuthHeader is the header HeaderAuth sends the API key in if no
+// other header is configured
+const DefaultAuthHeader = "X-Api-Key"
+
+// defaultCooldown is how long KeyPool skips a rate limited key if the
+// response has no Retry-After header
+const defaultCooldown = time.Minute
+
+// Authenticator adds credentials to requests. It is called by NewRequest and
+// again by Client.Do before every retry of a request.
+type Authenticator interface {
+	Authenticate(req *http.Request) error
+}
+
+// AuthenticatorFunc adapts a function to an Authenticator
+type AuthenticatorFunc func(req *http.Request) error
+
+// Authenticate calls f(req)
+func (f AuthenticatorFunc) Authenticate(req *http.Request) error {
+	return f(req)
+}
+
+// QueryAuth sends the API key as api_key query param. It is the default
+// Authenticator of a client.
+type QueryAuth struct {
+	Key string
+}
+
+// Authenticate sets the api_key query param of the request
+func (a *QueryAuth) Authenticate(req *http.Request) error {
+	setQueryKey(req, a.Key)
+	return nil
+}
+
+func (a *QueryAuth) keys() []string {
+	return []string{a.Key}
+}
+
+// HeaderAuth sends the API key in a request header, which keeps it out of the
+// URLs in proxy and server logs
+type HeaderAuth struct {
+	Key string
+
+	// Header is the name of the header, DefaultAuthHeader if empty
+	Header string
+}
+
+// Authenticate sets the header of the request
+func (a *HeaderAuth) Authenticate(req *http.Request) error {
+	header := a.Header
+	if header == "" {
+		header = DefaultAuthHeader
+	}
+	req.Header.Set(header, a.Key)
+	return nil
+}
+
+func (a *HeaderAuth) keys() []string {
+	return []string{a.Key}
+}
+
+// SetAuthenticator replaces how the client adds its API key to requests. If
+// the Authenticator also has an AfterResponse(*RequestInfo) method, like
+// KeyPool, it is called after every attempt before the AfterResponse hooks.
+func (c *Client) SetAuthenticator(a Authenticator) {
+	c.auth = a
+}
+
+// authenticate adds the credentials of the client to the request
+func (c *Client) authenticate(req *http.Request) error {
+	if c.auth == nil {
+		return nil
+	}
+	return c.auth.Authenticate(req)
+}
+
+// removeCredentials removes the credentials the Authenticator of the client
+// added to the request, i.e. the api_key query param and the header of a
+// HeaderAuth or KeyPool
+func (c *Client) removeCredentials(req *http.Request) {
+	switch a := c.auth.(type) {
+	case *HeaderAuth:
+		header := a.Header
+		if header == "" {
+			header = DefaultAuthHeader
+		}
+		req.Header.Del(header)
+	case *KeyPool:
+		if a.Header != "" {
+			req.Header.Del(a.Header)
+		}
+	}
+
+	if q := req.URL.Query(); q.Has("api_key") {
+		q.Del("api_key")
+		req.URL.RawQuery = q.Encode()
+	}
+}
+
+// authAfterResponse passes the info to the Authenticator of the request if it
+// wants to inspect responses
+func (c *Client) authAfterResponse(ctx context.Context, info *RequestInfo) {
+	if a, ok := c.authenticator(ctx).(interface {
+		AfterResponse(*RequestInfo)
+	}); ok {
+		a.AfterResponse(info)
+	}
+}
+
+// secrets returns the API keys known to the Authenticator of the client and
+// to an Authenticator set for requests with ctx by WithAPIKey or
+// WithAuthenticator
+func (c *Client) secrets(ctx context.Context) []string {
+	var keys []string
+	if a, ok := c.auth.(interface{ keys() []string }); ok {
+		keys = a.keys()
+	} else if c.apiKey != "" {
+		keys = []string{c.apiKey}
+	}
+	if a, ok := requestOptionsFrom(ctx).auth.(interface{ keys() []string }); ok {
+		keys = append(keys, a.keys()...)
+	}
+	return keys
+}
+
+// ErrNoAPIKey is returned by KeyPool if all of its keys were rejected
+var ErrNoAPIKey = errors.New("librariesio: all API keys were rejected")
+
+// poolKey is a key of a KeyPool and its state
+type poolKey struct {
+	key      string
+	rejected bool
+	until    time.Time
+}
+
+// KeyPool is an Authenticator which spreads requests across several API keys
+// in turn. A key which receives a 401 or 403 response is not used again, a
+// key which receives a 429 response is skipped until its Retry-After has
+// passed. The request is then retried with another key if one is available.
+//
+// A KeyPool is safe for concurrent use.
+type KeyPool struct {
+	// Header is the header the keys are sent in. The keys are sent as
+	// api_key query param if it is empty.
+	Header string
+
+	mu   sync.Mutex
+	pool []*poolKey
+	next int
+	now  func() time.Time
+}
+
+// NewKeyPool returns a KeyPool using the given keys, which are sent as
+// api_key query param
+func NewKeyPool(keys ...string) *KeyPool {
+	p := &KeyPool{now: time.Now}
+	for _, key := range keys {
+		p.pool = append(p.pool, &poolKey{key: key})
+	}
+	return p
+}
+
+// available reports whether the key can be used at the given time
+func (k *poolKey) available(now time.Time) bool {
+	return !k.rejected && !now.Before(k.until)
+}
+
+// pick returns the next available key. If all keys are rate limited, the one
+// which is available first is returned.
+func (p *KeyPool) pick() (*poolKey, error) {
+	now := p.now()
+	for i := range p.pool {
+		k := p.pool[(p.next+i)%len(p.pool)]
+		if k.available(now) {
+			p.next = (p.next + i + 1) % len(p.pool)
+			return k, nil
+		}
+	}
+
+	var first *poolKey
+	for _, k := range p.pool {
+		if !k.rejected && (first == nil || k.until.Before(first.until)) {
+			first = k
+		}
+	}
+	if first == nil {
+		return nil, ErrNoAPIKey
+	}
+	return first, nil
+}
+
+// Authenticate adds the next key of the pool to the request
+func (p *KeyPool) Authenticate(req *http.Request) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	k, err := p.pick()
+	if err != nil {
+		return err
+	}
+	if p.Header != "" {
+		req.Header.Set(p.Header, k.key)
+	} else {
+		setQueryKey(req, k.key)
+	}
+	return nil
+}
+
+// AfterResponse takes the key of the request out of rotation if the API
+// rejected it or rate limited it, and asks Client.Do to retry the request if
+// another key is available
+func (p *KeyPool) AfterResponse(info *RequestInfo) {
+	if info.Response == nil {
+		return
+	}
+	status := info.StatusCode
+	if status != http.StatusUnauthorized && status != http.StatusForbidden && status != http.StatusTooManyRequests {
+		return
+	}
+
+	var key string
+	if p.Header != "" {
+		key = info.Request.Header.Get(p.Header)
+	} else {
+		key = info.Request.URL.Query().Get("api_key")
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := p.now()
+	for _, k := range p.pool {
+		if k.key != key {
+			continue
+		}
+		if status == http.StatusTooManyRequests {
+			cooldown := defaultCooldown
+			if seconds, err := strconv.Atoi(info.Response.Header.Get("Retry-After")); err == nil && seconds > 0 {
+				cooldown = time.Duration(seconds) * time.Second
+			}
+			k.until = now.Add(cooldown)
+		} else {
+			k.rejected = true
+		}
+	}
+
+	for _, k := range p.pool {
+		if k.available(now) {
+			info.Retry = true
+			return
+		}
+	}
+}
+
+func (p *KeyPool) keys() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	keys := make([]string, len(p.pool))
+	for i, k := range p.pool {
+		keys[i] = k.key
+	}
+	return keys
+}
+
+// setQueryKey sets the api_key query param of the request
+func setQueryKey(req *http.Request, key string) {
+	q := req.URL.Query()
+	q.Set("api_key", key)
+	req.URL.RawQuery = q.Encode()
+}
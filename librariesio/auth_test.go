@@ -0,0 +1,155 @@
+package librariesio
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestHeaderAuth(t *testing.T) {
+	server, mux, url := startNewServer()
+	client := NewClient("")
+	client.BaseURL = url
+	client.SetAuthenticator(&HeaderAuth{Key: APIKey})
+	defer server.Close()
+
+	mux.HandleFunc("/pypi/cookiecutter", func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get(DefaultAuthHeader); got != APIKey {
+			t.Errorf("\nExpected header %v\nGot %v", APIKey, got)
+		}
+		if got := r.URL.Query().Get("api_key"); got != "" {
+			t.Errorf("expected no api_key query param, got %v", got)
+		}
+		fmt.Fprintf(w, `{"name":"cookiecutter"}`)
+	})
+
+	if _, _, err := client.Projects.Get(context.Background(), "pypi", "cookiecutter"); err != nil {
+		t.Fatalf("Projects.Get returned error: %v", err)
+	}
+}
+
+func TestAuthenticatorFunc(t *testing.T) {
+	client := NewClient(APIKey)
+	client.SetAuthenticator(AuthenticatorFunc(func(req *http.Request) error {
+		req.Header.Set("Authorization", "Bearer token")
+		return nil
+	}))
+
+	req, err := client.NewRequest("GET", "pypi/cookiecutter", nil)
+	if err != nil {
+		t.Fatalf("NewRequest returned error: %v", err)
+	}
+	if got, want := req.Header.Get("Authorization"), "Bearer token"; got != want {
+		t.Errorf("\nExpected %v\nGot %v", want, got)
+	}
+	if got := req.URL.Query().Get("api_key"); got != "" {
+		t.Errorf("expected no api_key query param, got %v", got)
+	}
+
+	client.SetAuthenticator(AuthenticatorFunc(func(req *http.Request) error {
+		return fmt.Errorf("no credentials")
+	}))
+	if _, err := client.NewRequest("GET", "pypi/cookiecutter", nil); err == nil {
+		t.Fatal("Expected error to be returned")
+	}
+}
+
+func TestKeyPool_rotation(t *testing.T) {
+	pool := NewKeyPool("a", "b", "c")
+
+	var got []string
+	for i := 0; i < 4; i++ {
+		req, _ := http.NewRequest("GET", "https://libraries.io/api/pypi/cookiecutter", nil)
+		if err := pool.Authenticate(req); err != nil {
+			t.Fatalf("Authenticate returned error: %v", err)
+		}
+		got = append(got, req.URL.Query().Get("api_key"))
+	}
+
+	if want := []string{"a", "b", "c", "a"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("\nExpected %v\nGot %v", want, got)
+	}
+}
+
+func TestKeyPool_switchesKeys(t *testing.T) {
+	server, mux, url := startNewServer()
+	client := NewClient("")
+	client.BaseURL = url
+	defer server.Close()
+
+	pool := NewKeyPool("rejected", "limited", "valid")
+	pool.Header = "X-Key"
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	pool.now = func() time.Time { return now }
+	client.SetAuthenticator(pool)
+
+	var keys []string
+	mux.HandleFunc("/pypi/cookiecutter", func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get("X-Key")
+		keys = append(keys, key)
+		switch key {
+		case "rejected":
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprintf(w, `{"error":"Invalid API key"}`)
+		case "limited":
+			w.Header().Set("Retry-After", "30")
+			w.WriteHeader(http.StatusTooManyRequests)
+			fmt.Fprintf(w, `{"error":"Too Many Requests"}`)
+		default:
+			fmt.Fprintf(w, `{"name":"cookiecutter"}`)
+		}
+	})
+
+	for i := 0; i < 2; i++ {
+		if _, _, err := client.Projects.Get(context.Background(), "pypi", "cookiecutter"); err != nil {
+			t.Fatalf("Projects.Get returned error: %v", err)
+		}
+	}
+	if want := []string{"rejected", "limited", "valid", "valid"}; !reflect.DeepEqual(keys, want) {
+		t.Errorf("\nExpected %v\nGot %v", want, keys)
+	}
+
+	// the rate limited key is used again once Retry-After has passed
+	now = now.Add(30 * time.Second)
+	keys = nil
+	if _, _, err := client.Projects.Get(context.Background(), "pypi", "cookiecutter"); err != nil {
+		t.Fatalf("Projects.Get returned error: %v", err)
+	}
+	if want := []string{"limited", "valid"}; !reflect.DeepEqual(keys, want) {
+		t.Errorf("\nExpected %v\nGot %v", want, keys)
+	}
+}
+
+func TestKeyPool_allRejected(t *testing.T) {
+	server, mux, url := startNewServer()
+	client := NewClient("")
+	client.BaseURL = url
+	defer server.Close()
+
+	client.SetAuthenticator(NewKeyPool("a", "b"))
+
+	requests := 0
+	mux.HandleFunc("/pypi/cookiecutter", func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusForbidden)
+		fmt.Fprintf(w, `{"error":"Invalid API key"}`)
+	})
+
+	_, resp, err := client.Projects.Get(context.Background(), "pypi", "cookiecutter")
+	if err == nil {
+		t.Fatal("Expected error to be returned")
+	}
+	if resp == nil || resp.StatusCode != http.StatusForbidden {
+		t.Errorf("expected the last response to be returned, got %v", resp)
+	}
+	if requests != 2 {
+		t.Errorf("expected 2 requests, got %v", requests)
+	}
+
+	if _, _, err := client.Projects.Get(context.Background(), "pypi", "cookiecutter"); err != ErrNoAPIKey {
+		t.Errorf("\nExpected %v\nGot %v", ErrNoAPIKey, err)
+	}
+}
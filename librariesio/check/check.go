@@ -0,0 +1,316 @@
+/*
+Package check gates continuous integration builds on the dependencies of a
+repository.
+
+Run discovers the manifest files below a directory, resolves their
+dependencies on libraries.io and reports a Finding for every dependency
+which breaks one of the Rules: direct dependencies which are outdated by a
+major release, deprecated or unmaintained projects, licenses which are not
+allowed by a license.Policy and projects with a low SourceRank.
+
+The Result can be written as a plain text summary, as JUnit XML and as
+SARIF for CI systems which display test results or code scanning alerts.
+*/
+package check
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/hackebrot/go-librariesio/librariesio"
+	"github.com/hackebrot/go-librariesio/librariesio/depgraph"
+	"github.com/hackebrot/go-librariesio/librariesio/health"
+	"github.com/hackebrot/go-librariesio/librariesio/license"
+	"github.com/hackebrot/go-librariesio/librariesio/manifest"
+)
+
+// Names of the rules as used in findings
+const (
+	RuleOutdated   = "outdated"
+	RuleDeprecated = "deprecated"
+	RuleLicense    = "license"
+	RuleSourceRank = "sourcerank"
+)
+
+// ruleDescriptions are short descriptions of the rules for reports
+var ruleDescriptions = map[string]string{
+	RuleOutdated:   "Direct dependency is behind its latest stable release",
+	RuleDeprecated: "Dependency is deprecated, unmaintained or removed",
+	RuleLicense:    "Dependency license is not allowed by the policy",
+	RuleSourceRank: "Dependency has a low SourceRank",
+}
+
+// skipDirs are not searched for manifest files
+var skipDirs = map[string]bool{
+	"node_modules": true,
+	"vendor":       true,
+}
+
+// Rules configure which dependencies fail the check. The zero value
+// disables all rules.
+type Rules struct {
+	// Outdated fails direct dependencies which are behind their latest stable
+	// release by at least the given drift, e.g. manifest.DriftMajor.
+	// DriftNone disables the rule.
+	Outdated manifest.Drift `json:"outdated" yaml:"outdated"`
+
+	// Deprecated fails dependencies which are deprecated, unmaintained or
+	// removed
+	Deprecated bool `json:"deprecated" yaml:"deprecated"`
+
+	// License fails dependencies with licenses which are not allowed by the
+	// policy. Nil disables the rule.
+	License *license.Policy `json:"license,omitempty" yaml:"license,omitempty"`
+
+	// MinSourceRank fails dependencies with a lower SourceRank. Zero
+	// disables the rule.
+	MinSourceRank int `json:"min_sourcerank,omitempty" yaml:"min_sourcerank,omitempty"`
+}
+
+// Options configure Run
+type Options struct {
+	Rules
+
+	// MaxDepth limits the resolution of transitive dependencies, see
+	// depgraph.Options
+	MaxDepth int
+}
+
+// Dependency is a project which was checked
+type Dependency struct {
+	librariesio.ProjectRef
+
+	Version string `json:"version,omitempty"`
+
+	// Manifest is the path of the manifest file declaring the dependency,
+	// or the root dependency leading to it, relative to the checked directory
+	Manifest string `json:"manifest"`
+
+	Transitive bool `json:"transitive"`
+
+	// Resolved is false if the project could not be looked up
+	Resolved bool `json:"resolved"`
+}
+
+// Finding is a dependency breaking a rule
+type Finding struct {
+	Rule string `json:"rule"`
+
+	librariesio.ProjectRef
+
+	Version  string `json:"version,omitempty"`
+	Manifest string `json:"manifest"`
+	Message  string `json:"message"`
+
+	// Path lists the projects from a direct dependency to a transitive one
+	Path []librariesio.ProjectRef `json:"path,omitempty"`
+}
+
+// Result holds the outcome of a check
+type Result struct {
+	Manifests    []string      `json:"manifests"`
+	Dependencies []*Dependency `json:"dependencies"`
+	Findings     []*Finding    `json:"findings"`
+}
+
+// Passed reports whether no dependency broke a rule
+func (r *Result) Passed() bool {
+	return len(r.Findings) == 0
+}
+
+// WriteText writes a human readable summary of the result
+func (r *Result) WriteText(w io.Writer) error {
+	fmt.Fprintf(w, "Checked %d dependencies from %d manifests\n", len(r.Dependencies), len(r.Manifests))
+
+	var unresolved []string
+	for _, dep := range r.Dependencies {
+		if !dep.Resolved && !dep.Transitive {
+			unresolved = append(unresolved, dep.Platform+"/"+dep.Name)
+		}
+	}
+	if len(unresolved) > 0 {
+		fmt.Fprintf(w, "Could not look up %d dependencies: %v\n", len(unresolved), strings.Join(unresolved, ", "))
+	}
+
+	if r.Passed() {
+		_, err := fmt.Fprintf(w, "No problems found\n")
+		return err
+	}
+
+	fmt.Fprintf(w, "\n%d problems found:\n", len(r.Findings))
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	for _, f := range r.Findings {
+		fmt.Fprintf(tw, "  %v\t%v\t%v\t%v\n", f.Rule, f.Platform+"/"+f.Name, f.Manifest, f.Message)
+	}
+	return tw.Flush()
+}
+
+// Discover returns the paths of all supported manifest files below dir.
+// Hidden directories, node_modules and vendor are skipped.
+func Discover(dir string) ([]string, error) {
+	var paths []string
+
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			name := info.Name()
+			if path != dir && (strings.HasPrefix(name, ".") || skipDirs[name]) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if manifest.Supported(info.Name()) {
+			paths = append(paths, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return paths, nil
+}
+
+// key identifies a project independent of the case of its platform
+func key(ref librariesio.ProjectRef) librariesio.ProjectRef {
+	return librariesio.ProjectRef{Platform: strings.ToLower(ref.Platform), Name: ref.Name}
+}
+
+// Run discovers the manifests below dir, resolves their dependencies and
+// checks them against the rules. Dependencies declared in several manifests
+// are attributed to the first one.
+//
+// An error is returned if no manifests are found, a manifest can't be parsed
+// or the context is cancelled.
+func Run(ctx context.Context, c librariesio.ProjectsAPI, dir string, opt *Options) (*Result, error) {
+	if opt == nil {
+		opt = &Options{}
+	}
+
+	paths, err := Discover(dir)
+	if err != nil {
+		return nil, err
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no manifest files found in %v", dir)
+	}
+
+	result := &Result{Findings: []*Finding{}}
+	declared := make(map[librariesio.ProjectRef]manifest.Dependency)
+	manifests := make(map[librariesio.ProjectRef]string)
+	var roots []manifest.Dependency
+
+	for _, path := range paths {
+		deps, err := manifest.ParseFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			rel = path
+		}
+		rel = filepath.ToSlash(rel)
+		result.Manifests = append(result.Manifests, rel)
+
+		for _, dep := range deps {
+			k := key(dep.ProjectRef)
+			if _, ok := declared[k]; !ok {
+				declared[k] = dep
+				manifests[k] = rel
+			}
+			roots = append(roots, dep)
+		}
+	}
+
+	g, err := depgraph.Resolve(ctx, c, roots, &depgraph.Options{MaxDepth: opt.MaxDepth})
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := make(map[librariesio.ProjectRef]*depgraph.Node)
+	manifestOf := func(node *depgraph.Node) string {
+		return manifests[key(node.Path()[0].ProjectRef)]
+	}
+	add := func(rule string, node *depgraph.Node, message string) {
+		f := &Finding{
+			Rule:       rule,
+			ProjectRef: node.ProjectRef,
+			Version:    node.ResolvedVersion(),
+			Manifest:   manifestOf(node),
+			Message:    message,
+		}
+		if node.Depth > 0 {
+			for _, n := range node.Path() {
+				f.Path = append(f.Path, n.ProjectRef)
+			}
+		}
+		result.Findings = append(result.Findings, f)
+	}
+
+	for _, node := range g.Nodes() {
+		nodes[key(node.ProjectRef)] = node
+		result.Dependencies = append(result.Dependencies, &Dependency{
+			ProjectRef: node.ProjectRef,
+			Version:    node.ResolvedVersion(),
+			Manifest:   manifestOf(node),
+			Transitive: node.Depth > 0,
+			Resolved:   node.Fetched(),
+		})
+	}
+
+	if opt.Outdated != manifest.DriftNone {
+		for _, node := range g.Roots {
+			if !node.Fetched() {
+				continue
+			}
+			o := manifest.Compare(declared[key(node.ProjectRef)], node.Project)
+			if o.Drift != manifest.DriftUnknown && o.Drift >= opt.Outdated {
+				add(RuleOutdated, node, fmt.Sprintf("%v is behind latest stable %v (%v)", o.Current, o.LatestStable, o.Drift))
+			}
+		}
+	}
+
+	if opt.Deprecated {
+		report := health.CheckGraph(g, &health.Options{MaxAge: -1})
+		for _, p := range report.Problems {
+			switch p.Reason {
+			case health.ReasonDeprecated, health.ReasonUnmaintained, health.ReasonRemoved:
+				if node, ok := nodes[key(p.ProjectRef)]; ok {
+					add(RuleDeprecated, node, p.Detail)
+				}
+			}
+		}
+	}
+
+	if opt.License != nil {
+		// Packages of the result are in the order of g.Nodes. Projects which
+		// were not fetched have no license information and are skipped.
+		licenses := opt.License.Check(g)
+		for i, node := range g.Nodes() {
+			if pkg := licenses.Packages[i]; !pkg.Allowed && node.Fetched() {
+				add(RuleLicense, node, pkg.Reason)
+			}
+		}
+	}
+
+	if opt.MinSourceRank > 0 {
+		for _, node := range g.Nodes() {
+			if !node.Fetched() || node.Project.Rank == nil {
+				continue
+			}
+			if rank := *node.Project.Rank; rank < opt.MinSourceRank {
+				add(RuleSourceRank, node, fmt.Sprintf("SourceRank %d is below %d", rank, opt.MinSourceRank))
+			}
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,173 @@
+package check
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/hackebrot/go-librariesio/librariesio"
+	"github.com/hackebrot/go-librariesio/librariesio/librariesiotest"
+	"github.com/hackebrot/go-librariesio/librariesio/license"
+	"github.com/hackebrot/go-librariesio/librariesio/manifest"
+)
+
+// writeFiles creates the given files in a temporary directory
+func writeFiles(t *testing.T, files map[string]string) string {
+	dir, err := ioutil.TempDir("", "check")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestDiscover(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"package.json":                    `{}`,
+		"api/go.mod":                      "module example.com/api\n",
+		"api/README.md":                   "",
+		"node_modules/chalk/package.json": `{}`,
+		".git/package.json":               `{}`,
+	})
+	defer os.RemoveAll(dir)
+
+	paths, err := Discover(dir)
+	if err != nil {
+		t.Fatalf("Discover returned unexpected error: %v", err)
+	}
+
+	want := []string{
+		filepath.Join(dir, "api", "go.mod"),
+		filepath.Join(dir, "package.json"),
+	}
+	if !reflect.DeepEqual(paths, want) {
+		t.Errorf("\nExpected %v\nGot %v", want, paths)
+	}
+}
+
+func TestRun(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"package.json": `{"dependencies": {"chalk": "^1.1.3", "request": "2.81.0", "tiny": "1.0.0"}}`,
+	})
+	defer os.RemoveAll(dir)
+
+	server, mux, client := librariesiotest.NewMux()
+	defer server.Close()
+
+	mux.HandleFunc("/npm/chalk/1.1.3/dependencies", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{
+			"name": "chalk",
+			"rank": 20,
+			"normalized_licenses": ["MIT"],
+			"latest_stable_release": {"number": "2.0.1"},
+			"dependencies": [{"name": "ansi-styles", "platform": "NPM"}]
+		}`)
+	})
+	mux.HandleFunc("/npm/ansi-styles/latest/dependencies", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"name": "ansi-styles", "rank": 18, "normalized_licenses": ["GPL-3.0"]}`)
+	})
+	mux.HandleFunc("/npm/request/2.81.0/dependencies", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{
+			"name": "request",
+			"rank": 25,
+			"status": "Deprecated",
+			"normalized_licenses": ["Apache-2.0"],
+			"latest_stable_release": {"number": "2.88.2"}
+		}`)
+	})
+	mux.HandleFunc("/npm/tiny/1.0.0/dependencies", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"name": "tiny", "rank": 3, "normalized_licenses": ["MIT"]}`)
+	})
+
+	opt := &Options{
+		Rules: Rules{
+			Outdated:      manifest.DriftMajor,
+			Deprecated:    true,
+			License:       &license.Policy{Allow: []string{"MIT", "Apache-2.0"}},
+			MinSourceRank: 5,
+		},
+		MaxDepth: 1,
+	}
+
+	result, err := Run(context.Background(), client.Projects, dir, opt)
+	if err != nil {
+		t.Fatalf("Run returned unexpected error: %v", err)
+	}
+
+	if want := []string{"package.json"}; !reflect.DeepEqual(result.Manifests, want) {
+		t.Errorf("\nExpected manifests %v\nGot %v", want, result.Manifests)
+	}
+	if got := len(result.Dependencies); got != 4 {
+		t.Errorf("expected 4 dependencies, got %v", got)
+	}
+
+	got := make(map[string]string)
+	for _, f := range result.Findings {
+		got[f.Rule] = f.Name
+		if f.Manifest != "package.json" {
+			t.Errorf("expected finding for %v in package.json, got %v", f.Name, f.Manifest)
+		}
+	}
+
+	want := map[string]string{
+		RuleOutdated:   "chalk",
+		RuleDeprecated: "request",
+		RuleLicense:    "ansi-styles",
+		RuleSourceRank: "tiny",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("\nExpected %v\nGot %v", want, got)
+	}
+	if len(result.Findings) != len(want) {
+		t.Errorf("expected %d findings, got %d", len(want), len(result.Findings))
+	}
+
+	if result.Passed() {
+		t.Errorf("expected the check to fail")
+	}
+}
+
+func TestRun_disabledRules(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"package.json": `{"dependencies": {"request": "2.81.0"}}`,
+	})
+	defer os.RemoveAll(dir)
+
+	server, mux, client := librariesiotest.NewMux()
+	defer server.Close()
+
+	mux.HandleFunc("/npm/request/2.81.0/dependencies", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"name": "request", "status": "Deprecated", "latest_stable_release": {"number": "3.0.0"}}`)
+	})
+
+	result, err := Run(context.Background(), client.Projects, dir, nil)
+	if err != nil {
+		t.Fatalf("Run returned unexpected error: %v", err)
+	}
+
+	if !result.Passed() {
+		t.Errorf("expected no findings without rules, got %v", result.Findings)
+	}
+}
+
+func TestRun_noManifests(t *testing.T) {
+	dir := writeFiles(t, map[string]string{"README.md": ""})
+	defer os.RemoveAll(dir)
+
+	if _, err := Run(context.Background(), librariesio.NewClient("1234").Projects, dir, nil); err == nil {
+		t.Errorf("expected an error for a directory without manifests")
+	}
+}
@@ -0,0 +1,224 @@
+package check
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"sort"
+	"strings"
+)
+
+// junitTestSuites is the root element of a JUnit XML report
+type junitTestSuites struct {
+	XMLName  xml.Name          `xml:"testsuites"`
+	Name     string            `xml:"name,attr"`
+	Tests    int               `xml:"tests,attr"`
+	Failures int               `xml:"failures,attr"`
+	Suites   []*junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string           `xml:"name,attr"`
+	Tests     int              `xml:"tests,attr"`
+	Failures  int              `xml:"failures,attr"`
+	TestCases []*junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	ClassName string          `xml:"classname,attr"`
+	Name      string          `xml:"name,attr"`
+	Failures  []*junitFailure `xml:"failure"`
+	Skipped   *junitSkipped   `xml:"skipped"`
+}
+
+type junitFailure struct {
+	Type    string `xml:"type,attr"`
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+type junitSkipped struct {
+	Message string `xml:"message,attr"`
+}
+
+// WriteJUnit writes the result as JUnit XML with a test suite for every
+// manifest and a test case for every dependency. Findings are reported as
+// failures, dependencies which could not be looked up as skipped.
+func (r *Result) WriteJUnit(w io.Writer) error {
+	report := &junitTestSuites{Name: "librariesio"}
+
+	suites := make(map[string]*junitTestSuite)
+	for _, m := range r.Manifests {
+		suite := &junitTestSuite{Name: m}
+		suites[m] = suite
+		report.Suites = append(report.Suites, suite)
+	}
+
+	cases := make(map[string]*junitTestCase)
+	for _, dep := range r.Dependencies {
+		tc := &junitTestCase{ClassName: dep.Manifest, Name: dep.Platform + "/" + dep.Name}
+		if dep.Version != "" {
+			tc.Name += "@" + dep.Version
+		}
+		if !dep.Resolved {
+			tc.Skipped = &junitSkipped{Message: "not resolved"}
+		}
+		cases[caseKey(dep.Platform, dep.Name, dep.Manifest)] = tc
+
+		if suite, ok := suites[dep.Manifest]; ok {
+			suite.TestCases = append(suite.TestCases, tc)
+			suite.Tests++
+			report.Tests++
+		}
+	}
+
+	for _, f := range r.Findings {
+		tc, ok := cases[caseKey(f.Platform, f.Name, f.Manifest)]
+		if !ok {
+			continue
+		}
+		text := f.Message
+		if len(f.Path) > 0 {
+			text += "\nrequired via " + formatPath(f)
+		}
+		tc.Failures = append(tc.Failures, &junitFailure{Type: f.Rule, Message: f.Message, Text: text})
+		tc.Skipped = nil
+	}
+
+	for _, suite := range report.Suites {
+		for _, tc := range suite.TestCases {
+			if len(tc.Failures) > 0 {
+				suite.Failures++
+				report.Failures++
+			}
+		}
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(report); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// caseKey identifies the test case of a dependency
+func caseKey(plat, name, manifest string) string {
+	return manifest + "\x00" + strings.ToLower(plat) + "\x00" + name
+}
+
+// formatPath formats the path of a finding, e.g. "npm/a > npm/b"
+func formatPath(f *Finding) string {
+	parts := make([]string, len(f.Path))
+	for i, ref := range f.Path {
+		parts[i] = ref.Platform + "/" + ref.Name
+	}
+	return strings.Join(parts, " > ")
+}
+
+const (
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	sarifVersion = "2.1.0"
+)
+
+type sarifLog struct {
+	Schema  string      `json:"$schema"`
+	Version string      `json:"version"`
+	Runs    []*sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    *sarifTool     `json:"tool"`
+	Results []*sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver *sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string       `json:"name"`
+	InformationURI string       `json:"informationUri"`
+	Rules          []*sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string        `json:"id"`
+	ShortDescription *sarifMessage `json:"shortDescription"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string           `json:"ruleId"`
+	Level     string           `json:"level"`
+	Message   *sarifMessage    `json:"message"`
+	Locations []*sarifLocation `json:"locations"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation *sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation *sarifArtifactLocation `json:"artifactLocation"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+// WriteSARIF writes the findings as a SARIF 2.1.0 log with the manifest
+// files as locations
+func (r *Result) WriteSARIF(w io.Writer) error {
+	driver := &sarifDriver{
+		Name:           "librariesio",
+		InformationURI: "https://github.com/hackebrot/go-librariesio",
+		Rules:          []*sarifRule{},
+	}
+
+	ids := make([]string, 0, len(ruleDescriptions))
+	for id := range ruleDescriptions {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		driver.Rules = append(driver.Rules, &sarifRule{
+			ID:               id,
+			ShortDescription: &sarifMessage{Text: ruleDescriptions[id]},
+		})
+	}
+
+	run := &sarifRun{Tool: &sarifTool{Driver: driver}, Results: []*sarifResult{}}
+	for _, f := range r.Findings {
+		text := f.Platform + "/" + f.Name
+		if f.Version != "" {
+			text += "@" + f.Version
+		}
+		text += ": " + f.Message
+		if len(f.Path) > 0 {
+			text += " (required via " + formatPath(f) + ")"
+		}
+
+		run.Results = append(run.Results, &sarifResult{
+			RuleID:  f.Rule,
+			Level:   "error",
+			Message: &sarifMessage{Text: text},
+			Locations: []*sarifLocation{{
+				PhysicalLocation: &sarifPhysicalLocation{
+					ArtifactLocation: &sarifArtifactLocation{URI: f.Manifest},
+				},
+			}},
+		})
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(&sarifLog{Schema: sarifSchema, Version: sarifVersion, Runs: []*sarifRun{run}})
+}
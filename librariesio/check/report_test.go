@@ -0,0 +1,103 @@
+package check
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"github.com/hackebrot/go-librariesio/librariesio"
+)
+
+var testResult = &Result{
+	Manifests: []string{"package.json"},
+	Dependencies: []*Dependency{
+		{ProjectRef: librariesio.ProjectRef{Platform: "npm", Name: "chalk"}, Version: "1.1.3", Manifest: "package.json", Resolved: true},
+		{ProjectRef: librariesio.ProjectRef{Platform: "npm", Name: "ansi-styles"}, Version: "3.0.0", Manifest: "package.json", Transitive: true, Resolved: true},
+		{ProjectRef: librariesio.ProjectRef{Platform: "npm", Name: "gone"}, Manifest: "package.json"},
+	},
+	Findings: []*Finding{
+		{
+			Rule:       RuleLicense,
+			ProjectRef: librariesio.ProjectRef{Platform: "npm", Name: "ansi-styles"},
+			Version:    "3.0.0",
+			Manifest:   "package.json",
+			Message:    "license not allowed: GPL-3.0",
+			Path: []librariesio.ProjectRef{
+				{Platform: "npm", Name: "chalk"},
+				{Platform: "npm", Name: "ansi-styles"},
+			},
+		},
+	},
+}
+
+func TestResult_WriteJUnit(t *testing.T) {
+	var buf bytes.Buffer
+	if err := testResult.WriteJUnit(&buf); err != nil {
+		t.Fatalf("WriteJUnit returned unexpected error: %v", err)
+	}
+
+	var report junitTestSuites
+	if err := xml.Unmarshal(buf.Bytes(), &report); err != nil {
+		t.Fatalf("invalid XML: %v\n%s", err, buf.Bytes())
+	}
+
+	if report.Tests != 3 || report.Failures != 1 {
+		t.Errorf("expected 3 tests and 1 failure, got %d and %d", report.Tests, report.Failures)
+	}
+
+	cases := report.Suites[0].TestCases
+	if name := cases[1].Name; name != "npm/ansi-styles@3.0.0" {
+		t.Errorf("unexpected test case name %v", name)
+	}
+	if f := cases[1].Failures; len(f) != 1 || f[0].Type != RuleLicense || !strings.Contains(f[0].Text, "npm/chalk > npm/ansi-styles") {
+		t.Errorf("unexpected failures %+v", f)
+	}
+	if cases[2].Skipped == nil {
+		t.Errorf("expected unresolved dependency to be skipped")
+	}
+}
+
+func TestResult_WriteSARIF(t *testing.T) {
+	var buf bytes.Buffer
+	if err := testResult.WriteSARIF(&buf); err != nil {
+		t.Fatalf("WriteSARIF returned unexpected error: %v", err)
+	}
+
+	var log sarifLog
+	if err := json.Unmarshal(buf.Bytes(), &log); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+
+	if log.Version != "2.1.0" || len(log.Runs) != 1 {
+		t.Fatalf("unexpected SARIF log %s", buf.Bytes())
+	}
+	if n := len(log.Runs[0].Tool.Driver.Rules); n != 4 {
+		t.Errorf("expected 4 rules, got %d", n)
+	}
+
+	results := log.Runs[0].Results
+	if len(results) != 1 {
+		t.Fatalf("expected 1 result, got %d", len(results))
+	}
+	if uri := results[0].Locations[0].PhysicalLocation.ArtifactLocation.URI; uri != "package.json" {
+		t.Errorf("unexpected location %v", uri)
+	}
+	if results[0].RuleID != RuleLicense {
+		t.Errorf("unexpected rule %v", results[0].RuleID)
+	}
+}
+
+func TestResult_WriteText(t *testing.T) {
+	var buf bytes.Buffer
+	if err := testResult.WriteText(&buf); err != nil {
+		t.Fatalf("WriteText returned unexpected error: %v", err)
+	}
+
+	for _, want := range []string{"Checked 3 dependencies from 1 manifests", "npm/gone", "1 problems found", "license not allowed: GPL-3.0"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("expected summary to contain %q\n%v", want, buf.String())
+		}
+	}
+}
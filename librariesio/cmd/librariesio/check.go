@@ -0,0 +1,107 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"io"
+	"os"
+
+	"github.com/hackebrot/go-librariesio/librariesio"
+	"github.com/hackebrot/go-librariesio/librariesio/check"
+	"github.com/hackebrot/go-librariesio/librariesio/license"
+	"github.com/hackebrot/go-librariesio/librariesio/manifest"
+)
+
+// driftFlag is a flag.Value for a manifest.Drift
+type driftFlag struct {
+	drift *manifest.Drift
+}
+
+func (f driftFlag) String() string {
+	if f.drift == nil {
+		return ""
+	}
+	return f.drift.String()
+}
+
+func (f driftFlag) Set(s string) error {
+	return f.drift.UnmarshalText([]byte(s))
+}
+
+var checkCommand = &command{
+	usage:       "[flags] [dir]",
+	description: "Check the dependencies of the manifests in dir (default .) and exit with 1 on problems",
+	nargs:       1,
+	optional:    true,
+	flags: func(fs *flag.FlagSet) runFunc {
+		opt := &check.Options{
+			Rules: check.Rules{
+				Outdated:   manifest.DriftMajor,
+				Deprecated: true,
+			},
+		}
+		fs.Var(driftFlag{&opt.Outdated}, "fail-on-drift", "fail direct dependencies outdated by at least a none, patch, minor or major release (none disables)")
+		fs.BoolVar(&opt.Deprecated, "deprecated", opt.Deprecated, "fail deprecated, unmaintained and removed dependencies")
+		policy := fs.String("license-policy", "", "fail dependencies with licenses not allowed by the JSON or YAML policy file")
+		fs.IntVar(&opt.MinSourceRank, "min-sourcerank", 0, "fail dependencies with a lower SourceRank (0 disables)")
+		fs.IntVar(&opt.MaxDepth, "depth", 0, "depth of transitive dependencies to check, -1 for all")
+		junit := fs.String("junit", "", "write a JUnit XML report to the file")
+		sarif := fs.String("sarif", "", "write a SARIF report to the file")
+
+		return func(ctx context.Context, c *librariesio.Client, args []string) (interface{}, error) {
+			dir := "."
+			if len(args) > 0 {
+				dir = args[0]
+			}
+
+			if *policy != "" {
+				p, err := license.LoadPolicy(*policy)
+				if err != nil {
+					return nil, err
+				}
+				opt.License = p
+			}
+
+			result, err := check.Run(ctx, c.Projects, dir, opt)
+			if err != nil {
+				return nil, err
+			}
+
+			if *junit != "" {
+				if err := writeFile(*junit, result.WriteJUnit); err != nil {
+					return nil, err
+				}
+			}
+			if *sarif != "" {
+				if err := writeFile(*sarif, result.WriteSARIF); err != nil {
+					return nil, err
+				}
+			}
+
+			if !result.Passed() {
+				return result, errFailed
+			}
+			return result, nil
+		}
+	},
+	rows: func(result interface{}) interface{} {
+		return result.(*check.Result).Findings
+	},
+	summary: func(w io.Writer, result interface{}) {
+		result.(*check.Result).WriteText(w)
+	},
+}
+
+// writeFile creates the file at path and writes to it with write
+func writeFile(path string, write func(w io.Writer) error) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	if err := write(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
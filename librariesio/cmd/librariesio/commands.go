@@ -0,0 +1,191 @@
+package main
+
+import (
+	"context"
+	"flag"
+
+	"github.com/hackebrot/go-librariesio/librariesio"
+)
+
+// runFunc runs a command with its positional arguments and returns the
+// result to print
+type runFunc func(ctx context.Context, c *librariesio.Client, args []string) (interface{}, error)
+
+// listFlags registers the pagination flags of commands returning lists
+func listFlags(fs *flag.FlagSet) *librariesio.ListOptions {
+	opt := &librariesio.ListOptions{}
+	fs.IntVar(&opt.Page, "page", 0, "page of the results to return")
+	fs.IntVar(&opt.PerPage, "per-page", 0, "number of results per page (max 100)")
+	return opt
+}
+
+var commands = map[string]*command{
+	"check":    checkCommand,
+	"diff":     diffCommand,
+	"exporter": exporterCommand,
+	"sync":     syncCommand,
+	"watch":    watchCommand,
+	"project": {
+		usage:       "<platform> <name>",
+		description: "Show information about a project",
+		nargs:       2,
+		flags: func(fs *flag.FlagSet) runFunc {
+			return func(ctx context.Context, c *librariesio.Client, args []string) (interface{}, error) {
+				project, _, err := c.Projects.Get(ctx, args[0], args[1])
+				return project, err
+			}
+		},
+	},
+	"deps": {
+		usage:       "[-version v] <platform> <name>",
+		description: "Show the dependencies of a project",
+		nargs:       2,
+		flags: func(fs *flag.FlagSet) runFunc {
+			version := fs.String("version", "latest", "version of the project")
+			return func(ctx context.Context, c *librariesio.Client, args []string) (interface{}, error) {
+				project, _, err := c.Projects.Deps(ctx, args[0], args[1], *version)
+				return project, err
+			}
+		},
+		rows: func(result interface{}) interface{} {
+			return result.(*librariesio.Project).Dependencies
+		},
+	},
+	"search": {
+		usage:       "[flags] <query>",
+		description: "Search for projects",
+		nargs:       1,
+		flags: func(fs *flag.FlagSet) runFunc {
+			opt := &librariesio.SearchOptions{}
+			fs.StringVar(&opt.Sort, "sort", "", "sort by rank, stars, dependents_count, ...")
+			fs.StringVar(&opt.Platforms, "platforms", "", "comma separated list of platforms")
+			fs.StringVar(&opt.Languages, "languages", "", "comma separated list of languages")
+			fs.StringVar(&opt.Licenses, "licenses", "", "comma separated list of licenses")
+			fs.StringVar(&opt.Keywords, "keywords", "", "comma separated list of keywords")
+			fs.IntVar(&opt.Page, "page", 0, "page of the results to return")
+			fs.IntVar(&opt.PerPage, "per-page", 0, "number of results per page (max 100)")
+			return func(ctx context.Context, c *librariesio.Client, args []string) (interface{}, error) {
+				projects, _, err := c.SearchService.Projects(ctx, args[0], opt)
+				return projects, err
+			}
+		},
+	},
+	"user": {
+		usage:       "<login>",
+		description: "Show information about a GitHub user",
+		nargs:       1,
+		flags: func(fs *flag.FlagSet) runFunc {
+			return func(ctx context.Context, c *librariesio.Client, args []string) (interface{}, error) {
+				user, _, err := c.Users.Get(ctx, args[0])
+				return user, err
+			}
+		},
+	},
+	"user-projects": {
+		usage:       "[-page n] [-per-page n] <login>",
+		description: "List the projects of a GitHub user",
+		nargs:       1,
+		flags: func(fs *flag.FlagSet) runFunc {
+			opt := listFlags(fs)
+			return func(ctx context.Context, c *librariesio.Client, args []string) (interface{}, error) {
+				projects, _, err := c.Users.Projects(ctx, args[0], opt)
+				return projects, err
+			}
+		},
+	},
+	"user-repos": {
+		usage:       "[-page n] [-per-page n] <login>",
+		description: "List the repositories of a GitHub user",
+		nargs:       1,
+		flags: func(fs *flag.FlagSet) runFunc {
+			opt := listFlags(fs)
+			return func(ctx context.Context, c *librariesio.Client, args []string) (interface{}, error) {
+				repos, _, err := c.Users.Repositories(ctx, args[0], opt)
+				return repos, err
+			}
+		},
+	},
+	"dependents": {
+		usage:       "[-page n] [-per-page n] <platform> <name>",
+		description: "List the projects depending on a project",
+		nargs:       2,
+		flags: func(fs *flag.FlagSet) runFunc {
+			opt := listFlags(fs)
+			return func(ctx context.Context, c *librariesio.Client, args []string) (interface{}, error) {
+				projects, _, err := c.Projects.Dependents(ctx, args[0], args[1], opt)
+				return projects, err
+			}
+		},
+	},
+	"contributors": {
+		usage:       "[-page n] [-per-page n] <platform> <name>",
+		description: "List the contributors to a project",
+		nargs:       2,
+		flags: func(fs *flag.FlagSet) runFunc {
+			opt := listFlags(fs)
+			return func(ctx context.Context, c *librariesio.Client, args []string) (interface{}, error) {
+				users, _, err := c.Projects.Contributors(ctx, args[0], args[1], opt)
+				return users, err
+			}
+		},
+	},
+	"repo": {
+		usage:       "<owner> <name>",
+		description: "Show information about a GitHub repository",
+		nargs:       2,
+		flags: func(fs *flag.FlagSet) runFunc {
+			return func(ctx context.Context, c *librariesio.Client, args []string) (interface{}, error) {
+				repo, _, err := c.Repositories.Get(ctx, args[0], args[1])
+				return repo, err
+			}
+		},
+	},
+	"repo-deps": {
+		usage:       "<owner> <name>",
+		description: "Show the dependencies of a GitHub repository",
+		nargs:       2,
+		flags: func(fs *flag.FlagSet) runFunc {
+			return func(ctx context.Context, c *librariesio.Client, args []string) (interface{}, error) {
+				repo, _, err := c.Repositories.Deps(ctx, args[0], args[1])
+				return repo, err
+			}
+		},
+		rows: func(result interface{}) interface{} {
+			return result.(*librariesio.Repository).Dependencies
+		},
+	},
+	"sourcerank": {
+		usage:       "<platform> <name>",
+		description: "Show the SourceRank breakdown of a project",
+		nargs:       2,
+		flags: func(fs *flag.FlagSet) runFunc {
+			return func(ctx context.Context, c *librariesio.Client, args []string) (interface{}, error) {
+				rank, _, err := c.Projects.SourceRank(ctx, args[0], args[1])
+				return rank, err
+			}
+		},
+	},
+	"platforms": {
+		usage:       "",
+		description: "List the supported package managers",
+		nargs:       0,
+		flags: func(fs *flag.FlagSet) runFunc {
+			return func(ctx context.Context, c *librariesio.Client, args []string) (interface{}, error) {
+				platforms, _, err := c.Platforms(ctx)
+				return platforms, err
+			}
+		},
+	},
+	"subscriptions": {
+		usage:       "[-page n] [-per-page n]",
+		description: "List your project subscriptions",
+		nargs:       0,
+		flags: func(fs *flag.FlagSet) runFunc {
+			opt := listFlags(fs)
+			return func(ctx context.Context, c *librariesio.Client, args []string) (interface{}, error) {
+				subscriptions, _, err := c.SubscriptionsService.List(ctx, opt)
+				return subscriptions, err
+			}
+		},
+	},
+}
@@ -0,0 +1,398 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hackebrot/go-librariesio/librariesio"
+	"github.com/hackebrot/go-librariesio/librariesio/store"
+	"github.com/hackebrot/go-librariesio/librariesio/transport"
+	"gopkg.in/yaml.v2"
+)
+
+// defaultProfile is used if neither the config file nor the -profile flag
+// select a profile
+const defaultProfile = "default"
+
+// config is the configuration file of the CLI, e.g.
+//
+//	default_profile: work
+//	profiles:
+//	  work:
+//	    api_key_file: ~/.secrets/librariesio
+//	    auth_header: X-Api-Key
+//	    proxy: http://proxy.example.com:3128
+//	    cache_dir: ~/.cache/librariesio
+//	    rate_limit: 60
+//	    store: ~/.local/share/librariesio/store.db
+//	    output: json
+type config struct {
+	DefaultProfile string              `yaml:"default_profile"`
+	Profiles       map[string]*profile `yaml:"profiles"`
+}
+
+// profile holds the settings for talking to the API. Empty values are unset
+// and fall back to the defaults.
+type profile struct {
+	APIKey     string `yaml:"api_key"`
+	APIKeyFile string `yaml:"api_key_file"`
+	BaseURL    string `yaml:"base_url"`
+	Proxy      string `yaml:"proxy"`
+
+	// APIKeys are used in turn instead of APIKey, switching away from keys
+	// which are rejected or rate limited
+	APIKeys []string `yaml:"api_keys"`
+
+	// AuthHeader sends the API keys in the header instead of the api_key
+	// query param
+	AuthHeader string `yaml:"auth_header"`
+
+	CacheDir    string        `yaml:"cache_dir"`
+	CacheMaxAge time.Duration `yaml:"cache_max_age"`
+
+	// RateLimit is the maximum number of requests per minute
+	RateLimit int `yaml:"rate_limit"`
+
+	// Store is the path of the database mirroring the API, see the sync
+	// command. Offline serves all requests from the store. It is a pointer
+	// so that offline: true can be overridden with false.
+	Store   string `yaml:"store"`
+	Offline *bool  `yaml:"offline"`
+
+	// OfflineDir serves all requests from JSON files mirroring the API
+	// paths, see transport.Offline
+	OfflineDir string `yaml:"offline_dir"`
+
+	Output string `yaml:"output"`
+}
+
+// merge overrides the settings of p with those which are set in other. An
+// API key, key file or keys in other replace all keys of p.
+func (p *profile) merge(other *profile) {
+	if other.APIKey != "" || other.APIKeyFile != "" || len(other.APIKeys) > 0 {
+		p.APIKey, p.APIKeyFile, p.APIKeys = other.APIKey, other.APIKeyFile, other.APIKeys
+	}
+	if other.AuthHeader != "" {
+		p.AuthHeader = other.AuthHeader
+	}
+	if other.BaseURL != "" {
+		p.BaseURL = other.BaseURL
+	}
+	if other.Proxy != "" {
+		p.Proxy = other.Proxy
+	}
+	if other.CacheDir != "" {
+		p.CacheDir = other.CacheDir
+	}
+	if other.CacheMaxAge != 0 {
+		p.CacheMaxAge = other.CacheMaxAge
+	}
+	if other.RateLimit != 0 {
+		p.RateLimit = other.RateLimit
+	}
+	if other.Store != "" {
+		p.Store = other.Store
+	}
+	if other.Offline != nil {
+		p.Offline = other.Offline
+	}
+	if other.OfflineDir != "" {
+		p.OfflineDir = other.OfflineDir
+	}
+	if other.Output != "" {
+		p.Output = other.Output
+	}
+}
+
+// offline reports whether all requests are served from the store
+func (p *profile) offline() bool {
+	return p.Offline != nil && *p.Offline
+}
+
+// optionalBoolFlag is a flag.Value for a bool which stays nil unless the
+// flag is set
+type optionalBoolFlag struct {
+	value **bool
+}
+
+func (f optionalBoolFlag) String() string {
+	if f.value == nil || *f.value == nil {
+		return "false"
+	}
+	return strconv.FormatBool(**f.value)
+}
+
+func (f optionalBoolFlag) Set(s string) error {
+	v, err := strconv.ParseBool(s)
+	if err != nil {
+		return err
+	}
+	*f.value = &v
+	return nil
+}
+
+func (f optionalBoolFlag) IsBoolFlag() bool {
+	return true
+}
+
+// profileFlags are the global flags which override the selected profile
+type profileFlags struct {
+	config      string
+	profileName string
+	profile
+}
+
+// register adds the flags to fs
+func (f *profileFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&f.config, "config", "", "path of the config file (default ~/.config/librariesio/config.yaml)")
+	fs.StringVar(&f.profile.Output, "output", "", "output format: "+strings.Join(outputFormats, ", ")+" (default table)")
+	fs.StringVar(&f.profile.APIKeyFile, "api-key-file", "", "read the API key from the file")
+	fs.StringVar(&f.profile.AuthHeader, "auth-header", "", "send the API key in the header instead of the query string")
+	fs.StringVar(&f.profile.BaseURL, "base-url", "", "base URL of the libraries.io API")
+	fs.StringVar(&f.profile.Proxy, "proxy", "", "URL of the HTTP proxy")
+	fs.StringVar(&f.profile.CacheDir, "cache-dir", "", "cache API responses in the directory")
+	fs.DurationVar(&f.profile.CacheMaxAge, "cache-max-age", 0, "maximum age of cached responses (default 1h)")
+	fs.IntVar(&f.profile.RateLimit, "rate-limit", 0, "maximum number of requests per minute")
+	fs.StringVar(&f.profile.Store, "store", "", "path of the local store mirroring the API")
+	fs.Var(optionalBoolFlag{&f.profile.Offline}, "offline", "serve all requests from the -store")
+	fs.StringVar(&f.profile.OfflineDir, "offline-dir", "", "serve all requests from JSON files in the directory, e.g. pypi/cookiecutter.json")
+	fs.StringVar(&f.profileName, "profile", "", "name of the profile in the config file")
+}
+
+// envProfile returns the settings from LIBRARIESIO_* environment variables
+func envProfile() (*profile, error) {
+	p := &profile{
+		APIKey:     strings.TrimSpace(os.Getenv("LIBRARIESIO_API_KEY")),
+		APIKeyFile: os.Getenv("LIBRARIESIO_API_KEY_FILE"),
+		BaseURL:    os.Getenv("LIBRARIESIO_BASE_URL"),
+		Proxy:      os.Getenv("LIBRARIESIO_PROXY"),
+		CacheDir:   os.Getenv("LIBRARIESIO_CACHE_DIR"),
+		Store:      os.Getenv("LIBRARIESIO_STORE"),
+		OfflineDir: os.Getenv("LIBRARIESIO_OFFLINE_DIR"),
+		AuthHeader: os.Getenv("LIBRARIESIO_AUTH_HEADER"),
+		Output:     os.Getenv("LIBRARIESIO_OUTPUT"),
+	}
+
+	for _, key := range strings.Split(os.Getenv("LIBRARIESIO_API_KEYS"), ",") {
+		if key = strings.TrimSpace(key); key != "" {
+			p.APIKeys = append(p.APIKeys, key)
+		}
+	}
+
+	if v := os.Getenv("LIBRARIESIO_CACHE_MAX_AGE"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid LIBRARIESIO_CACHE_MAX_AGE: %v", err)
+		}
+		p.CacheMaxAge = d
+	}
+	if v := os.Getenv("LIBRARIESIO_OFFLINE"); v != "" {
+		offline, err := strconv.ParseBool(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid LIBRARIESIO_OFFLINE: %v", err)
+		}
+		p.Offline = &offline
+	}
+	if v := os.Getenv("LIBRARIESIO_RATE_LIMIT"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid LIBRARIESIO_RATE_LIMIT: %v", err)
+		}
+		p.RateLimit = n
+	}
+
+	return p, nil
+}
+
+// defaultConfigPath returns the path of the config file in the user's
+// config directory
+func defaultConfigPath() string {
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		dir = filepath.Join(os.Getenv("HOME"), ".config")
+	}
+	return filepath.Join(dir, "librariesio", "config.yaml")
+}
+
+// loadConfig reads the config file at path. A missing file is only an
+// error if the path was given explicitly.
+func loadConfig(path string, explicit bool) (*config, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) && !explicit {
+			return &config{}, nil
+		}
+		return nil, err
+	}
+
+	c := &config{}
+	if err := yaml.UnmarshalStrict(data, c); err != nil {
+		return nil, fmt.Errorf("error parsing %v: %v", path, err)
+	}
+	return c, nil
+}
+
+// resolveProfile merges the selected profile of the config file, the
+// environment variables and the flags, in increasing order of precedence
+func resolveProfile(flags *profileFlags) (*profile, error) {
+	path, explicit := flags.config, flags.config != ""
+	if !explicit {
+		path, explicit = os.Getenv("LIBRARIESIO_CONFIG"), os.Getenv("LIBRARIESIO_CONFIG") != ""
+	}
+	if !explicit {
+		path = defaultConfigPath()
+	}
+
+	c, err := loadConfig(path, explicit)
+	if err != nil {
+		return nil, err
+	}
+
+	name, explicit := flags.profileName, flags.profileName != ""
+	if !explicit {
+		name, explicit = os.Getenv("LIBRARIESIO_PROFILE"), os.Getenv("LIBRARIESIO_PROFILE") != ""
+	}
+	if !explicit {
+		name = c.DefaultProfile
+		explicit = name != ""
+	}
+	if name == "" {
+		name = defaultProfile
+	}
+
+	p := &profile{}
+	if selected, ok := c.Profiles[name]; ok {
+		p.merge(selected)
+	} else if explicit {
+		return nil, fmt.Errorf("profile %q not found in %v", name, path)
+	}
+
+	env, err := envProfile()
+	if err != nil {
+		return nil, err
+	}
+	p.merge(env)
+	p.merge(&flags.profile)
+
+	return p, nil
+}
+
+// apiKey returns the API key of the profile, reading it from the key file
+// if necessary
+func (p *profile) apiKey() (string, error) {
+	if p.APIKey != "" {
+		return p.APIKey, nil
+	}
+	if p.APIKeyFile == "" {
+		return "", fmt.Errorf("no API key configured, set LIBRARIESIO_API_KEY or api_key in the config file")
+	}
+
+	data, err := ioutil.ReadFile(expandHome(p.APIKeyFile))
+	if err != nil {
+		return "", fmt.Errorf("error reading API key: %v", err)
+	}
+
+	key := strings.TrimSpace(string(data))
+	if key == "" {
+		return "", fmt.Errorf("API key file %v is empty", p.APIKeyFile)
+	}
+	return key, nil
+}
+
+// openStore opens the store of the profile. It returns nil if no store is
+// configured.
+func (p *profile) openStore() (*store.Store, error) {
+	if p.Store == "" {
+		if p.offline() {
+			return nil, fmt.Errorf("offline mode requires a store, set -store or store in the config file")
+		}
+		return nil, nil
+	}
+	return store.Open(expandHome(p.Store))
+}
+
+// newClient returns a client configured with the settings of the profile.
+// Requests are served from s if it is not nil, see store.Transport.
+func (p *profile) newClient(s *store.Store) (*librariesio.Client, error) {
+	var c *librariesio.Client
+	if p.offline() || p.OfflineDir != "" {
+		// no API key is needed to serve requests locally
+		c = librariesio.NewClient("")
+	} else if len(p.APIKeys) > 0 {
+		pool := librariesio.NewKeyPool(p.APIKeys...)
+		pool.Header = p.AuthHeader
+		c = librariesio.NewClient("")
+		c.SetAuthenticator(pool)
+	} else {
+		key, err := p.apiKey()
+		if err != nil {
+			return nil, err
+		}
+		c = librariesio.NewClient(key)
+		if p.AuthHeader != "" {
+			c.SetAuthenticator(&librariesio.HeaderAuth{Key: key, Header: p.AuthHeader})
+		}
+	}
+
+	if p.BaseURL != "" {
+		u, err := url.Parse(p.BaseURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid base URL: %v", err)
+		}
+		if !strings.HasSuffix(u.Path, "/") {
+			u.Path += "/"
+		}
+		c.BaseURL = u
+	}
+
+	base := &http.Transport{Proxy: http.ProxyFromEnvironment}
+	if p.Proxy != "" {
+		u, err := url.Parse(p.Proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy URL: %v", err)
+		}
+		base.Proxy = http.ProxyURL(u)
+	}
+
+	var rt http.RoundTripper = base
+	if p.OfflineDir != "" {
+		rt = &transport.Offline{Dir: expandHome(p.OfflineDir), BaseURL: c.BaseURL}
+	} else if p.RateLimit > 0 {
+		rt = transport.NewRateLimit(p.RateLimit, rt)
+	}
+	if p.CacheDir != "" {
+		rt = &transport.Cache{
+			Dir:        expandHome(p.CacheDir),
+			MaxAge:     p.CacheMaxAge,
+			AuthHeader: p.AuthHeader,
+			Transport:  rt,
+		}
+	}
+	if s != nil {
+		rt = &store.Transport{
+			Store:     s,
+			BaseURL:   c.BaseURL,
+			Offline:   p.offline(),
+			Transport: rt,
+		}
+	}
+	c.SetTransport(rt)
+
+	return c, nil
+}
+
+// expandHome replaces a leading ~ of the path with the home directory
+func expandHome(path string) string {
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		return filepath.Join(os.Getenv("HOME"), path[1:])
+	}
+	return path
+}
@@ -0,0 +1,209 @@
+package main
+
+import (
+	"flag"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// envVars are the environment variables read by resolveProfile
+var envVars = []string{
+	"LIBRARIESIO_CONFIG", "LIBRARIESIO_PROFILE",
+	"LIBRARIESIO_API_KEY", "LIBRARIESIO_API_KEY_FILE", "LIBRARIESIO_API_KEYS",
+	"LIBRARIESIO_BASE_URL", "LIBRARIESIO_PROXY", "LIBRARIESIO_AUTH_HEADER",
+	"LIBRARIESIO_CACHE_DIR", "LIBRARIESIO_CACHE_MAX_AGE", "LIBRARIESIO_RATE_LIMIT",
+	"LIBRARIESIO_STORE", "LIBRARIESIO_OFFLINE", "LIBRARIESIO_OFFLINE_DIR",
+	"LIBRARIESIO_OUTPUT",
+}
+
+// resolve writes the config file, sets the environment variables and
+// resolves the profile for the command line args
+func resolve(t *testing.T, config string, env map[string]string, args ...string) (*profile, error) {
+	t.Helper()
+
+	for _, key := range envVars {
+		t.Setenv(key, env[key])
+	}
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := ioutil.WriteFile(path, []byte(config), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	flags := &profileFlags{}
+	fs := flag.NewFlagSet("librariesio", flag.ContinueOnError)
+	flags.register(fs)
+	if err := fs.Parse(append([]string{"-config", path}, args...)); err != nil {
+		t.Fatal(err)
+	}
+	return resolveProfile(flags)
+}
+
+func TestResolveProfile_precedence(t *testing.T) {
+	config := `
+profiles:
+  default:
+    base_url: http://config.example.com/api/
+    proxy: http://config.example.com:3128
+    output: json
+`
+	env := map[string]string{
+		"LIBRARIESIO_PROXY":  "http://env.example.com:3128",
+		"LIBRARIESIO_OUTPUT": "yaml",
+	}
+
+	p, err := resolve(t, config, env, "-output", "csv")
+	if err != nil {
+		t.Fatalf("resolveProfile returned unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{"base_url", p.BaseURL, "http://config.example.com/api/"},
+		{"proxy", p.Proxy, "http://env.example.com:3128"},
+		{"output", p.Output, "csv"},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%v:\nExpected %v\nGot %v", tt.name, tt.want, tt.got)
+		}
+	}
+}
+
+func TestResolveProfile_profile(t *testing.T) {
+	config := `
+default_profile: work
+profiles:
+  work:
+    output: json
+  home:
+    output: yaml
+`
+
+	p, err := resolve(t, config, nil)
+	if err != nil {
+		t.Fatalf("resolveProfile returned unexpected error: %v", err)
+	}
+	if p.Output != "json" {
+		t.Errorf("\nExpected %v\nGot %v", "json", p.Output)
+	}
+
+	p, err = resolve(t, config, map[string]string{"LIBRARIESIO_PROFILE": "home"})
+	if err != nil {
+		t.Fatalf("resolveProfile returned unexpected error: %v", err)
+	}
+	if p.Output != "yaml" {
+		t.Errorf("\nExpected %v\nGot %v", "yaml", p.Output)
+	}
+
+	if _, err := resolve(t, config, nil, "-profile", "missing"); err == nil {
+		t.Error("Expected error to be returned for a missing profile")
+	}
+}
+
+func TestResolveProfile_offline(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string
+		env    string
+		args   []string
+		want   bool
+	}{
+		{"unset", "", "", nil, false},
+		{"config", "offline: true", "", nil, true},
+		{"env", "", "true", nil, true},
+		{"flag", "", "", []string{"-offline"}, true},
+		{"env overrides config", "offline: true", "false", nil, false},
+		{"flag overrides config", "offline: true", "", []string{"-offline=false"}, false},
+		{"flag overrides env", "", "true", []string{"-offline=false"}, false},
+	}
+
+	for _, tt := range tests {
+		config := "profiles:\n  default:\n    store: store.db\n"
+		if tt.config != "" {
+			config += "    " + tt.config + "\n"
+		}
+
+		p, err := resolve(t, config, map[string]string{"LIBRARIESIO_OFFLINE": tt.env}, tt.args...)
+		if err != nil {
+			t.Fatalf("%v: resolveProfile returned unexpected error: %v", tt.name, err)
+		}
+		if got := p.offline(); got != tt.want {
+			t.Errorf("%v:\nExpected %v\nGot %v", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestProfile_apiKey(t *testing.T) {
+	dir := t.TempDir()
+	keyFile := filepath.Join(dir, "key")
+	if err := ioutil.WriteFile(keyFile, []byte("  1234\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	emptyFile := filepath.Join(dir, "empty")
+	if err := ioutil.WriteFile(emptyFile, []byte("\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	missingFile := filepath.Join(dir, "missing")
+
+	tests := []struct {
+		name string
+		p    *profile
+		want string
+		err  string
+	}{
+		{"key", &profile{APIKey: "5678", APIKeyFile: keyFile}, "5678", ""},
+		{"key file", &profile{APIKeyFile: keyFile}, "1234", ""},
+		{"empty key file", &profile{APIKeyFile: emptyFile}, "", "API key file " + emptyFile + " is empty"},
+		{"missing key file", &profile{APIKeyFile: missingFile}, "", "error reading API key"},
+		{"no key", &profile{}, "", "no API key configured"},
+	}
+
+	for _, tt := range tests {
+		key, err := tt.p.apiKey()
+		if tt.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("%v: expected error %q, got %v", tt.name, tt.err, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%v: apiKey returned unexpected error: %v", tt.name, err)
+			continue
+		}
+		if key != tt.want {
+			t.Errorf("%v:\nExpected %v\nGot %v", tt.name, tt.want, key)
+		}
+	}
+}
+
+func TestResolveProfile_apiKeyFile(t *testing.T) {
+	keyFile := filepath.Join(t.TempDir(), "key")
+	if err := ioutil.WriteFile(keyFile, []byte("1234\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	config := "profiles:\n  default:\n    api_key: 5678\n"
+
+	// a key file of the flags replaces the key of the config file
+	p, err := resolve(t, config, nil, "-api-key-file", keyFile)
+	if err != nil {
+		t.Fatalf("resolveProfile returned unexpected error: %v", err)
+	}
+	if key, err := p.apiKey(); err != nil || key != "1234" {
+		t.Errorf("\nExpected %v\nGot %v (%v)", "1234", key, err)
+	}
+
+	// a missing key file is reported when the client is created
+	p, err = resolve(t, config, map[string]string{"LIBRARIESIO_API_KEY_FILE": keyFile + ".missing"})
+	if err != nil {
+		t.Fatalf("resolveProfile returned unexpected error: %v", err)
+	}
+	if _, err := p.newClient(nil); err == nil || !strings.Contains(err.Error(), "error reading API key") {
+		t.Errorf("expected error reading the API key, got %v", err)
+	}
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/hackebrot/go-librariesio/librariesio"
+	"github.com/hackebrot/go-librariesio/librariesio/store"
+)
+
+// projectChange is a change of a stored project
+type projectChange struct {
+	librariesio.ProjectRef
+	*librariesio.Change
+}
+
+// changeKinds are the kinds of changes accepted by -fail-on
+var changeKinds = []librariesio.ChangeKind{
+	librariesio.ChangeNewVersion,
+	librariesio.ChangeRemovedVersion,
+	librariesio.ChangeLicense,
+	librariesio.ChangeStatus,
+	librariesio.ChangeRepository,
+	librariesio.ChangeHomepage,
+}
+
+// changeKindsFlag is a flag.Value for a comma separated list of change kinds
+type changeKindsFlag struct {
+	kinds map[librariesio.ChangeKind]bool
+}
+
+func (f changeKindsFlag) String() string {
+	var s []string
+	for _, kind := range changeKinds {
+		if f.kinds[kind] {
+			s = append(s, string(kind))
+		}
+	}
+	return strings.Join(s, ",")
+}
+
+func (f changeKindsFlag) Set(s string) error {
+	for _, kind := range strings.Split(s, ",") {
+		kind = strings.TrimSpace(kind)
+		if kind == "" {
+			continue
+		}
+		valid := false
+		for _, k := range changeKinds {
+			valid = valid || string(k) == kind
+		}
+		if !valid {
+			return fmt.Errorf("unknown change kind %q", kind)
+		}
+		f.kinds[librariesio.ChangeKind(kind)] = true
+	}
+	return nil
+}
+
+var diffCommand = &command{
+	usage:       "[flags]",
+	description: "Show how the projects in the -store changed since an earlier snapshot",
+	nargs:       0,
+	flags: func(fs *flag.FlagSet) runFunc {
+		var refs []librariesio.ProjectRef
+		fs.Var(packagesFlag{&refs}, "package", "compare the package given as platform/name instead of all stored projects, can be repeated")
+		since := fs.Duration("since", 7*24*time.Hour, "compare with the snapshot of this long ago")
+		fail := make(map[librariesio.ChangeKind]bool)
+		kinds := make([]string, len(changeKinds))
+		for i, kind := range changeKinds {
+			kinds[i] = string(kind)
+		}
+		fs.Var(changeKindsFlag{fail}, "fail-on", "exit with 1 on changes of the comma separated kinds: "+strings.Join(kinds, ", "))
+
+		return func(ctx context.Context, c *librariesio.Client, args []string) (interface{}, error) {
+			s, _ := storeFrom(ctx)
+			if s == nil {
+				return nil, fmt.Errorf("no store configured, set -store or store in the config file")
+			}
+
+			keys := make([]store.Key, 0, len(refs))
+			for _, ref := range refs {
+				keys = append(keys, store.ProjectKey(ref.Platform, ref.Name))
+			}
+			if len(refs) == 0 {
+				entries, err := s.Entries(store.KindProject)
+				if err != nil {
+					return nil, err
+				}
+				for _, e := range entries {
+					keys = append(keys, e.Key)
+				}
+			}
+
+			at := time.Now().Add(-*since)
+			changes := []*projectChange{}
+			failed := false
+			for _, key := range keys {
+				current := &librariesio.Project{}
+				if _, err := s.Get(key, current); err != nil {
+					return nil, fmt.Errorf("error reading %v: %v", key, err)
+				}
+
+				// Projects which were first stored after the snapshot have
+				// nothing to compare with
+				old := &librariesio.Project{}
+				if _, err := s.GetAt(key, at, old); err == store.ErrNotFound {
+					continue
+				} else if err != nil {
+					return nil, err
+				}
+
+				ref := librariesio.ProjectRef{Platform: key.Platform, Name: key.Name}
+				for _, change := range librariesio.Diff(old, current) {
+					changes = append(changes, &projectChange{ProjectRef: ref, Change: change})
+					failed = failed || fail[change.Kind]
+				}
+			}
+
+			if failed {
+				return changes, errFailed
+			}
+			return changes, nil
+		}
+	},
+	summary: func(w io.Writer, result interface{}) {
+		fmt.Fprintf(w, "%d changes found\n", len(result.([]*projectChange)))
+	},
+}
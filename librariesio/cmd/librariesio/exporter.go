@@ -0,0 +1,132 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/hackebrot/go-librariesio/librariesio"
+	"github.com/hackebrot/go-librariesio/librariesio/exporter"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"gopkg.in/yaml.v2"
+)
+
+// packagesFlag is a flag.Value collecting packages given as platform/name
+type packagesFlag struct {
+	refs *[]librariesio.ProjectRef
+}
+
+func (f packagesFlag) String() string {
+	if f.refs == nil {
+		return ""
+	}
+	var s []string
+	for _, ref := range *f.refs {
+		s = append(s, ref.Platform+"/"+ref.Name)
+	}
+	return strings.Join(s, ",")
+}
+
+func (f packagesFlag) Set(s string) error {
+	ref, err := parsePackage(s)
+	if err != nil {
+		return err
+	}
+	*f.refs = append(*f.refs, ref)
+	return nil
+}
+
+// parsePackage parses a package given as platform/name. The name may
+// contain slashes, e.g. go/github.com/hackebrot/go-repr.
+func parsePackage(s string) (librariesio.ProjectRef, error) {
+	parts := strings.SplitN(s, "/", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return librariesio.ProjectRef{}, fmt.Errorf("invalid package %q, expected platform/name", s)
+	}
+	return librariesio.ProjectRef{Platform: parts[0], Name: parts[1]}, nil
+}
+
+// loadPackages reads a YAML file listing packages, e.g.
+//
+//	packages:
+//	  - platform: pypi
+//	    name: cookiecutter
+func loadPackages(path string) ([]librariesio.ProjectRef, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var file struct {
+		Packages []librariesio.ProjectRef `yaml:"packages"`
+	}
+	if err := yaml.UnmarshalStrict(data, &file); err != nil {
+		return nil, fmt.Errorf("error parsing %v: %v", path, err)
+	}
+	return file.Packages, nil
+}
+
+var exporterCommand = &command{
+	usage:       "[flags]",
+	description: "Poll packages and serve their metadata as Prometheus metrics until interrupted",
+	nargs:       0,
+	longRunning: true,
+	flags: func(fs *flag.FlagSet) runFunc {
+		var refs []librariesio.ProjectRef
+		fs.Var(packagesFlag{&refs}, "package", "package to poll as platform/name, can be repeated")
+		packages := fs.String("packages", "", "YAML file listing the packages to poll")
+		listen := fs.String("listen", ":9742", "address to serve /metrics on")
+		opt := &exporter.Options{}
+		fs.DurationVar(&opt.Interval, "interval", exporter.DefaultInterval, "time between two polls of a package")
+		fs.IntVar(&opt.RequestsPerMinute, "requests-per-minute", exporter.DefaultRequestsPerMinute, "maximum number of API requests per minute")
+
+		return func(ctx context.Context, c *librariesio.Client, args []string) (interface{}, error) {
+			if *packages != "" {
+				loaded, err := loadPackages(*packages)
+				if err != nil {
+					return nil, err
+				}
+				refs = append(refs, loaded...)
+			}
+			if len(refs) == 0 {
+				return nil, fmt.Errorf("no packages given, use -package or -packages")
+			}
+
+			e := exporter.New(c.Projects, refs, opt)
+
+			registry := prometheus.NewRegistry()
+			registry.MustRegister(e)
+
+			mux := http.NewServeMux()
+			mux.Handle("/metrics", promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
+
+			ln, err := net.Listen("tcp", *listen)
+			if err != nil {
+				return nil, err
+			}
+			server := &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+			errc := make(chan error, 1)
+			go func() {
+				errc <- server.Serve(ln)
+			}()
+
+			go e.Run(ctx)
+
+			select {
+			case err := <-errc:
+				return nil, err
+			case <-ctx.Done():
+			}
+
+			shutdown, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			return nil, server.Shutdown(shutdown)
+		}
+	},
+}
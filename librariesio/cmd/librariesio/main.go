@@ -2,47 +2,237 @@ package main
 
 import (
 	"context"
+	"errors"
+	"flag"
 	"fmt"
+	"io"
+	"log/slog"
 	"os"
+	"os/signal"
+	"sort"
+	"syscall"
+	"text/tabwriter"
 	"time"
 
-	"strings"
-
 	"github.com/hackebrot/go-librariesio/librariesio"
 )
 
-func loadFromEnv(keys ...string) (map[string]string, error) {
-	env := make(map[string]string)
+// command is a subcommand of the CLI
+type command struct {
+	usage       string
+	description string
 
-	for _, key := range keys {
-		v := os.Getenv(key)
-		if v == "" {
-			return nil, fmt.Errorf("environment variable %q is required", key)
-		}
-		env[key] = v
+	// flags registers the flags of the command and returns the function
+	// which runs it with the remaining positional arguments
+	flags func(fs *flag.FlagSet) runFunc
+
+	// nargs is the number of positional arguments, the last of which may
+	// be omitted if optional is set
+	nargs    int
+	optional bool
+
+	// rows optionally selects the list printed by the table and csv
+	// output formats from the result
+	rows func(result interface{}) interface{}
+
+	// summary optionally writes a summary of the result to stderr
+	summary func(w io.Writer, result interface{})
+
+	// longRunning commands run until they are interrupted. Their result is
+	// not printed.
+	longRunning bool
+}
+
+// errFailed is returned by commands whose result was computed but which
+// should exit with a non-zero code, e.g. check with findings
+var errFailed = errors.New("failed")
+
+// outputKey is the context key of the outputValue
+type outputKey struct{}
+
+// outputValue holds the streams of the CLI for long running commands, which
+// write while they run instead of returning a result
+type outputValue struct {
+	stdout, stderr io.Writer
+}
+
+// outputFrom returns the stdout and stderr of the CLI
+func outputFrom(ctx context.Context) (io.Writer, io.Writer) {
+	v, _ := ctx.Value(outputKey{}).(outputValue)
+	return v.stdout, v.stderr
+}
+
+func usage(w io.Writer, fs *flag.FlagSet) {
+	fmt.Fprintf(w, "Usage: librariesio [global flags] <command> [flags] [args]\n\n")
+	fmt.Fprintf(w, "Commands:\n")
+
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
 	}
+	sort.Strings(names)
 
-	return env, nil
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	for _, name := range names {
+		fmt.Fprintf(tw, "  %v %v\t%v\n", name, commands[name].usage, commands[name].description)
+	}
+	tw.Flush()
+
+	fmt.Fprintf(w, "\nFlags:\n")
+	fs.SetOutput(w)
+	fs.PrintDefaults()
+	fmt.Fprintf(w, "\nSettings are read from the profile in the config file, LIBRARIESIO_*\n")
+	fmt.Fprintf(w, "environment variables such as LIBRARIESIO_API_KEY and the flags above,\n")
+	fmt.Fprintf(w, "in increasing order of precedence.\n")
 }
 
 func main() {
-	env, err := loadFromEnv("LIBRARIESIO_API_KEY")
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run executes the CLI and returns the exit code
+func run(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("librariesio", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	timeout := fs.Duration("timeout", time.Second*10, "timeout for each API request")
+	tmpl := fs.String("template", "", "Go template rendered for the result or every item of a list, implies -output template")
+	logLevel := fs.String("log-level", "", "log requests to stderr at the level: debug, info, warn or error")
+	noCache := fs.Bool("no-cache", false, "fetch fresh responses instead of using the cache")
+	dumpBodies := fs.Bool("dump-bodies", false, "log request and response bodies, requires -log-level debug")
+	flags := &profileFlags{}
+	flags.register(fs)
+	fs.Usage = func() { usage(stderr, fs) }
+
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
 
+	prof, err := resolveProfile(flags)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "error: %v\n", err)
-		os.Exit(1)
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return 1
 	}
 
-	c := librariesio.NewClient(strings.TrimSpace(env["LIBRARIESIO_API_KEY"]))
+	output := prof.Output
+	if *tmpl != "" && !isFlagSet(fs, "output") {
+		output = "template"
+	}
+	if output == "" {
+		output = "table"
+	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
-	defer cancel()
+	p, err := newPrinter(output, *tmpl)
+	if err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return 2
+	}
+
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return 2
+	}
+
+	name := fs.Arg(0)
+	cmd, ok := commands[name]
+	if !ok {
+		fmt.Fprintf(stderr, "error: unknown command %q\n\n", name)
+		fs.Usage()
+		return 2
+	}
+
+	cmdFlags := flag.NewFlagSet(name, flag.ContinueOnError)
+	cmdFlags.SetOutput(stderr)
+	cmdFlags.Usage = func() {
+		fmt.Fprintf(stderr, "Usage: librariesio %v %v\n\n%v\n", name, cmd.usage, cmd.description)
+		cmdFlags.PrintDefaults()
+	}
+	runCmd := cmd.flags(cmdFlags)
+
+	if err := cmdFlags.Parse(fs.Args()[1:]); err != nil {
+		return 2
+	}
+	if n := cmdFlags.NArg(); n != cmd.nargs && !(cmd.optional && n == cmd.nargs-1) {
+		cmdFlags.Usage()
+		return 2
+	}
 
-	project, _, err := c.Project(ctx, "pypi", "cookiecutter")
+	s, err := prof.openStore()
+	if err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return 1
+	}
+	if s != nil {
+		defer s.Close()
+	}
 
+	c, err := prof.newClient(s)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "%v\n", err)
-		os.Exit(1)
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return 1
+	}
+
+	if *logLevel != "" {
+		var level slog.Level
+		if err := level.UnmarshalText([]byte(*logLevel)); err != nil {
+			fmt.Fprintf(stderr, "error: invalid log level %q\n", *logLevel)
+			return 2
+		}
+		logger := slog.New(slog.NewTextHandler(stderr, &slog.HandlerOptions{Level: level}))
+		c.SetLogger(logger, &librariesio.LogOptions{Level: slog.LevelInfo, DumpBodies: *dumpBodies})
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+	ctx = librariesio.WithRequestOptions(ctx, librariesio.WithTimeout(*timeout))
+	if cmd.longRunning {
+		ctx = context.WithValue(ctx, outputKey{}, outputValue{stdout: stdout, stderr: stderr})
+	}
+
+	if *noCache {
+		ctx = librariesio.WithRequestOptions(ctx, librariesio.SkipCache())
+	}
+	if s != nil {
+		ctx = context.WithValue(ctx, storeKey{}, storeValue{store: s, offline: prof.offline()})
 	}
-	fmt.Fprintf(os.Stdout, "%s\n", *project.Name)
+
+	result, err := runCmd(ctx, c, cmdFlags.Args())
+	if err != nil && err != errFailed {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return 1
+	}
+	failed := err == errFailed
+
+	if cmd.longRunning {
+		return 0
+	}
+
+	printed := result
+	if cmd.rows != nil && (output == "table" || output == "csv") {
+		printed = cmd.rows(result)
+	}
+
+	if err := p.print(stdout, printed); err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return 1
+	}
+
+	if cmd.summary != nil {
+		cmd.summary(stderr, result)
+	}
+
+	if failed {
+		return 1
+	}
+	return 0
+}
+
+// isFlagSet reports whether the flag was passed on the command line
+func isFlagSet(fs *flag.FlagSet, name string) bool {
+	set := false
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	return set
 }
@@ -0,0 +1,408 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"text/tabwriter"
+	"text/template"
+	"time"
+
+	"github.com/hackebrot/go-librariesio/librariesio"
+	"github.com/hackebrot/go-librariesio/librariesio/store"
+	"gopkg.in/yaml.v2"
+)
+
+// outputFormats lists the values of the -output flag
+var outputFormats = []string{"table", "json", "yaml", "csv", "template"}
+
+// tableColumns are the columns printed for lists of API resources in the
+// table and csv formats. Nested fields are separated by dots. Types which
+// are not listed use all of their scalar fields.
+var tableColumns = map[reflect.Type][]string{
+	reflect.TypeOf(librariesio.Project{}):              {"platform", "name", "latest_release_number", "stars", "rank", "language"},
+	reflect.TypeOf(librariesio.ProjectDependency{}):    {"platform", "name", "requirements", "latest_stable", "outdated", "deprecated"},
+	reflect.TypeOf(librariesio.Repository{}):           {"full_name", "language", "stargazers_count", "forks_count", "license", "status"},
+	reflect.TypeOf(librariesio.RepositoryDependency{}): {"platform", "name", "requirements", "kind", "filepath", "outdated"},
+	reflect.TypeOf(librariesio.User{}):                 {"login", "name", "company", "location", "followers"},
+	reflect.TypeOf(librariesio.Platform{}):             {"name", "project_count", "default_language", "homepage"},
+	reflect.TypeOf(librariesio.Subscription{}):         {"project.platform", "project.name", "include_prerelease", "created_at"},
+	reflect.TypeOf(store.SyncFailure{}):                {"key.kind", "key.platform", "key.owner", "key.name", "key.version", "error"},
+}
+
+// templateFuncs are available in -template strings. Use deref to print
+// unset pointer fields of the API resources as empty strings.
+var templateFuncs = template.FuncMap{
+	// deref returns the value of a pointer or "" for nil
+	"deref": func(v interface{}) interface{} {
+		rv := reflect.ValueOf(v)
+		for rv.IsValid() && rv.Kind() == reflect.Ptr {
+			if rv.IsNil() {
+				return ""
+			}
+			rv = rv.Elem()
+		}
+		if !rv.IsValid() {
+			return ""
+		}
+		return rv.Interface()
+	},
+	// join concatenates a list such as Project.Keywords
+	"join": func(v interface{}, sep string) string {
+		rv := reflect.ValueOf(v)
+		if rv.Kind() != reflect.Slice {
+			return formatValue(rv)
+		}
+		parts := make([]string, rv.Len())
+		for i := range parts {
+			parts[i] = formatValue(rv.Index(i))
+		}
+		return strings.Join(parts, sep)
+	},
+}
+
+// printer writes results in the format selected with the -output flag
+type printer struct {
+	format   string
+	template *template.Template
+}
+
+// newPrinter returns a printer for the given format. tmpl is only used for
+// the template format.
+func newPrinter(format, tmpl string) (*printer, error) {
+	p := &printer{format: format}
+
+	switch format {
+	case "table", "json", "yaml", "csv":
+	case "template":
+		if tmpl == "" {
+			return nil, fmt.Errorf("-template is required for the template output")
+		}
+		t, err := template.New("output").Funcs(templateFuncs).Parse(tmpl)
+		if err != nil {
+			return nil, fmt.Errorf("invalid template: %v", err)
+		}
+		p.template = t
+	default:
+		return nil, fmt.Errorf("unknown output format %q, expected one of %v", format, strings.Join(outputFormats, ", "))
+	}
+
+	return p, nil
+}
+
+// print writes v to w
+func (p *printer) print(w io.Writer, v interface{}) error {
+	switch p.format {
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	case "yaml":
+		return printYAML(w, v)
+	case "csv":
+		return printCSV(w, v)
+	case "template":
+		return p.printTemplate(w, v)
+	default:
+		return printTable(w, v)
+	}
+}
+
+// printYAML writes v as YAML using the JSON field names
+func printYAML(w io.Writer, v interface{}) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+
+	var data interface{}
+	if err := dec.Decode(&data); err != nil {
+		return err
+	}
+
+	out, err := yaml.Marshal(yamlNumbers(data))
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(out)
+	return err
+}
+
+// yamlNumbers replaces json.Number values, which YAML would quote as
+// strings, with integers and floats
+func yamlNumbers(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			v[key] = yamlNumbers(value)
+		}
+	case []interface{}:
+		for i, value := range v {
+			v[i] = yamlNumbers(value)
+		}
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		if f, err := v.Float64(); err == nil {
+			return f
+		}
+	}
+	return v
+}
+
+// printTemplate renders the template for v, or for every item if v is a list
+func (p *printer) printTemplate(w io.Writer, v interface{}) error {
+	for _, item := range items(v) {
+		var buf bytes.Buffer
+		if err := p.template.Execute(&buf, item.Interface()); err != nil {
+			return err
+		}
+		if !bytes.HasSuffix(buf.Bytes(), []byte("\n")) {
+			buf.WriteByte('\n')
+		}
+		if _, err := buf.WriteTo(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// printCSV writes a header and a row for every item of v
+func printCSV(w io.Writer, v interface{}) error {
+	rows := items(v)
+	cw := csv.NewWriter(w)
+
+	if len(rows) > 0 {
+		cols := columns(indirectType(rows[0].Type()))
+		if err := cw.Write(cols); err != nil {
+			return err
+		}
+		for _, row := range rows {
+			if err := cw.Write(record(row, cols)); err != nil {
+				return err
+			}
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// printTable prints a list as a table with a row for every item and a
+// single resource as a table of its fields and values
+func printTable(w io.Writer, v interface{}) error {
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+
+	rv := reflect.ValueOf(v)
+	if !rv.IsValid() {
+		return nil
+	}
+	if rv.Kind() != reflect.Slice {
+		for _, col := range scalarFields(indirectType(rv.Type())) {
+			value, ok := field(rv, col)
+			if !ok {
+				continue
+			}
+			if s := formatValue(value); s != "" {
+				fmt.Fprintf(tw, "%v\t%v\n", strings.ToUpper(col), s)
+			}
+		}
+		return tw.Flush()
+	}
+
+	rows := items(v)
+	if len(rows) == 0 {
+		return nil
+	}
+
+	cols := columns(indirectType(rows[0].Type()))
+	header := make([]string, len(cols))
+	for i, col := range cols {
+		header[i] = strings.ToUpper(col)
+	}
+	fmt.Fprintln(tw, strings.Join(header, "\t"))
+
+	for _, row := range rows {
+		fmt.Fprintln(tw, strings.Join(record(row, cols), "\t"))
+	}
+
+	return tw.Flush()
+}
+
+// items returns the elements of v if it is a slice and v itself otherwise.
+// A nil interface has no items.
+func items(v interface{}) []reflect.Value {
+	rv := reflect.ValueOf(v)
+	if !rv.IsValid() {
+		return nil
+	}
+	if rv.Kind() != reflect.Slice {
+		return []reflect.Value{rv}
+	}
+
+	values := make([]reflect.Value, rv.Len())
+	for i := range values {
+		values[i] = rv.Index(i)
+	}
+	return values
+}
+
+// record formats the given columns of v
+func record(v reflect.Value, cols []string) []string {
+	values := make([]string, len(cols))
+	for i, col := range cols {
+		if value, ok := field(v, col); ok {
+			values[i] = formatValue(value)
+		}
+	}
+	return values
+}
+
+// columns returns the columns printed for a type
+func columns(t reflect.Type) []string {
+	if cols, ok := tableColumns[t]; ok {
+		return cols
+	}
+	return scalarFields(t)
+}
+
+// scalarFields returns the JSON names of all fields of a struct type which
+// can be printed in a single cell, i.e. strings, numbers, booleans, times
+// and lists thereof
+func scalarFields(t reflect.Type) []string {
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var names []string
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" {
+			continue
+		}
+		if f.Anonymous {
+			names = append(names, scalarFields(indirectType(f.Type))...)
+			continue
+		}
+
+		ft := indirectType(f.Type)
+		if ft.Kind() == reflect.Slice {
+			ft = indirectType(ft.Elem())
+		}
+		if !isScalar(ft) {
+			continue
+		}
+
+		if name := jsonName(f); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// field returns the value of the field with the given dotted JSON path. It
+// returns false if the field does not exist or a pointer on the path is nil.
+func field(v reflect.Value, path string) (reflect.Value, bool) {
+	for _, name := range strings.Split(path, ".") {
+		v = indirect(v)
+		if !v.IsValid() || v.Kind() != reflect.Struct {
+			return reflect.Value{}, false
+		}
+
+		found := false
+		for i := 0; i < v.NumField(); i++ {
+			f := v.Type().Field(i)
+			if f.Anonymous {
+				if value, ok := field(v.Field(i), name); ok {
+					v, found = value, true
+					break
+				}
+				continue
+			}
+			if jsonName(f) == name {
+				v, found = v.Field(i), true
+				break
+			}
+		}
+		if !found {
+			return reflect.Value{}, false
+		}
+	}
+	return v, true
+}
+
+// formatValue formats a scalar value, nil pointers are formatted as ""
+func formatValue(v reflect.Value) string {
+	v = indirect(v)
+	if !v.IsValid() {
+		return ""
+	}
+
+	if t, ok := v.Interface().(time.Time); ok {
+		return t.Format(time.RFC3339)
+	}
+
+	if v.Kind() == reflect.Slice {
+		parts := make([]string, 0, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			parts = append(parts, formatValue(v.Index(i)))
+		}
+		return strings.Join(parts, ", ")
+	}
+
+	return fmt.Sprint(v.Interface())
+}
+
+// indirect dereferences pointers and returns the zero Value for nil
+func indirect(v reflect.Value) reflect.Value {
+	for v.IsValid() && (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+	return v
+}
+
+// indirectType returns the type pointers point to
+func indirectType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
+
+// isScalar reports whether values of the type fit into a single cell
+func isScalar(t reflect.Type) bool {
+	if t == reflect.TypeOf(time.Time{}) {
+		return true
+	}
+	switch t.Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// jsonName returns the name of the field in the JSON encoding
+func jsonName(f reflect.StructField) string {
+	tag := f.Tag.Get("json")
+	if tag == "-" {
+		return ""
+	}
+	if name := strings.Split(tag, ",")[0]; name != "" {
+		return name
+	}
+	return f.Name
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/hackebrot/go-librariesio/librariesio"
+)
+
+func TestPrinter(t *testing.T) {
+	platform, name, stars := "pypi", "cookiecutter", 5
+	project := &librariesio.Project{Platform: &platform, Name: &name, Stars: &stars}
+
+	tests := []struct {
+		name     string
+		format   string
+		template string
+		v        interface{}
+		want     string
+	}{
+		{
+			name:   "table resource",
+			format: "table",
+			v:      project,
+			want:   "NAME      cookiecutter\nPLATFORM  pypi\nSTARS     5\n",
+		},
+		{
+			name:   "table list",
+			format: "table",
+			v:      []*librariesio.Project{project},
+			want:   "PLATFORM  NAME          LATEST_RELEASE_NUMBER  STARS  RANK  LANGUAGE\npypi      cookiecutter                         5            \n",
+		},
+		{
+			name:   "table empty list",
+			format: "table",
+			v:      []*librariesio.Project{},
+			want:   "",
+		},
+		{
+			name:   "table nil",
+			format: "table",
+			v:      nil,
+			want:   "",
+		},
+		{
+			name:   "json resource",
+			format: "json",
+			v:      project,
+			want:   "{\n  \"name\": \"cookiecutter\",\n  \"platform\": \"pypi\",\n  \"stars\": 5\n}\n",
+		},
+		{
+			name:   "json empty list",
+			format: "json",
+			v:      []*librariesio.Project{},
+			want:   "[]\n",
+		},
+		{
+			name:   "json nil",
+			format: "json",
+			v:      nil,
+			want:   "null\n",
+		},
+		{
+			name:   "yaml resource",
+			format: "yaml",
+			v:      project,
+			want:   "name: cookiecutter\nplatform: pypi\nstars: 5\n",
+		},
+		{
+			name:   "yaml empty list",
+			format: "yaml",
+			v:      []*librariesio.Project{},
+			want:   "[]\n",
+		},
+		{
+			name:   "csv list",
+			format: "csv",
+			v:      []*librariesio.Project{project},
+			want:   "platform,name,latest_release_number,stars,rank,language\npypi,cookiecutter,,5,,\n",
+		},
+		{
+			name:   "csv empty list",
+			format: "csv",
+			v:      []*librariesio.Project{},
+			want:   "",
+		},
+		{
+			name:   "csv nil",
+			format: "csv",
+			v:      nil,
+			want:   "",
+		},
+		{
+			name:     "template list",
+			format:   "template",
+			template: "{{deref .Name}} {{deref .LatestReleaseNumber}}",
+			v:        []*librariesio.Project{project, {Name: &name}},
+			want:     "cookiecutter \ncookiecutter \n",
+		},
+		{
+			name:     "template empty list",
+			format:   "template",
+			template: "{{.Name}}",
+			v:        []*librariesio.Project{},
+			want:     "",
+		},
+		{
+			name:     "template nil",
+			format:   "template",
+			template: "{{.}}",
+			v:        nil,
+			want:     "",
+		},
+	}
+
+	for _, tt := range tests {
+		p, err := newPrinter(tt.format, tt.template)
+		if err != nil {
+			t.Fatalf("%v: newPrinter returned unexpected error: %v", tt.name, err)
+		}
+
+		var buf bytes.Buffer
+		if err := p.print(&buf, tt.v); err != nil {
+			t.Errorf("%v: print returned unexpected error: %v", tt.name, err)
+			continue
+		}
+		if got := buf.String(); got != tt.want {
+			t.Errorf("%v:\nExpected %q\nGot %q", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestNewPrinter_invalid(t *testing.T) {
+	tests := []struct {
+		format   string
+		template string
+	}{
+		{"xml", ""},
+		{"template", ""},
+		{"template", "{{.Name"},
+	}
+
+	for _, tt := range tests {
+		if _, err := newPrinter(tt.format, tt.template); err == nil {
+			t.Errorf("%v %q: expected error to be returned", tt.format, tt.template)
+		}
+	}
+}
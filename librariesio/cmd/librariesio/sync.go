@@ -0,0 +1,120 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/hackebrot/go-librariesio/librariesio"
+	"github.com/hackebrot/go-librariesio/librariesio/store"
+)
+
+// storeKey is the context key of the storeValue
+type storeKey struct{}
+
+// storeValue is the store opened for the -store flag and whether the client
+// is offline
+type storeValue struct {
+	store   *store.Store
+	offline bool
+}
+
+// storeFrom returns the store opened for the -store flag, which is nil if no
+// store is configured, and whether the client is offline
+func storeFrom(ctx context.Context) (*store.Store, bool) {
+	v, _ := ctx.Value(storeKey{}).(storeValue)
+	return v.store, v.offline
+}
+
+// reposFlag is a flag.Value collecting GitHub repositories given as
+// owner/name
+type reposFlag struct {
+	keys *[]store.Key
+}
+
+func (f reposFlag) String() string {
+	if f.keys == nil {
+		return ""
+	}
+	var s []string
+	for _, key := range *f.keys {
+		s = append(s, key.String())
+	}
+	return strings.Join(s, ",")
+}
+
+func (f reposFlag) Set(s string) error {
+	parts := strings.Split(s, "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return fmt.Errorf("invalid repository %q, expected owner/name", s)
+	}
+	*f.keys = append(*f.keys, store.RepositoryKey(parts[0], parts[1]))
+	return nil
+}
+
+var syncCommand = &command{
+	usage:       "[flags]",
+	description: "Add packages and repositories to the -store and refresh its stale records",
+	nargs:       0,
+	flags: func(fs *flag.FlagSet) runFunc {
+		opt := &store.SyncOptions{}
+		var refs []librariesio.ProjectRef
+		var repos []store.Key
+		fs.Var(packagesFlag{&refs}, "package", "add the package given as platform/name, can be repeated")
+		packages := fs.String("packages", "", "add the packages listed in the YAML file")
+		fs.Var(reposFlag{&repos}, "repo", "add the GitHub repository given as owner/name, can be repeated")
+		deps := fs.Bool("deps", false, "also add the dependencies of the latest release of the packages and of the repositories")
+		fs.DurationVar(&opt.MaxAge, "max-age", store.DefaultMaxAge, "refresh records fetched longer ago, 0 refreshes all")
+
+		return func(ctx context.Context, c *librariesio.Client, args []string) (interface{}, error) {
+			s, offline := storeFrom(ctx)
+			if s == nil {
+				return nil, fmt.Errorf("no store configured, set -store or store in the config file")
+			}
+			if offline {
+				return nil, fmt.Errorf("can't sync the store offline")
+			}
+			if opt.MaxAge == 0 {
+				opt.MaxAge = -1
+			}
+
+			if *packages != "" {
+				loaded, err := loadPackages(*packages)
+				if err != nil {
+					return nil, err
+				}
+				refs = append(refs, loaded...)
+			}
+			for _, ref := range refs {
+				opt.Add = append(opt.Add, store.ProjectKey(ref.Platform, ref.Name))
+				if *deps {
+					opt.Add = append(opt.Add, store.DependenciesKey(ref.Platform, ref.Name, "latest"))
+				}
+			}
+			for _, key := range repos {
+				opt.Add = append(opt.Add, key)
+				if *deps {
+					opt.Add = append(opt.Add, store.RepositoryDependenciesKey(key.Owner, key.Name))
+				}
+			}
+
+			result, err := store.Sync(ctx, c, s, opt)
+			if err != nil {
+				return nil, err
+			}
+			if len(result.Failed) > 0 {
+				return result, errFailed
+			}
+			return result, nil
+		}
+	},
+	rows: func(result interface{}) interface{} {
+		return result.(*store.SyncResult).Failed
+	},
+	summary: func(w io.Writer, result interface{}) {
+		r := result.(*store.SyncResult)
+		fmt.Fprintf(w, "Fetched %d records, %d were fresh, %d failed\n", len(r.Fetched), len(r.Fresh), len(r.Failed))
+	},
+}
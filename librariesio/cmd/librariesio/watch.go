@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/hackebrot/go-librariesio/librariesio"
+	"github.com/hackebrot/go-librariesio/librariesio/watcher"
+)
+
+// defaultStatePath returns the path of the state file of watch in the user's
+// state directory
+func defaultStatePath() string {
+	dir := os.Getenv("XDG_STATE_HOME")
+	if dir == "" {
+		dir = filepath.Join(os.Getenv("HOME"), ".local", "state")
+	}
+	return filepath.Join(dir, "librariesio", "watch.json")
+}
+
+var watchCommand = &command{
+	usage:       "[flags]",
+	description: "Poll packages and report their new releases until interrupted",
+	nargs:       0,
+	longRunning: true,
+	flags: func(fs *flag.FlagSet) runFunc {
+		var refs []librariesio.ProjectRef
+		fs.Var(packagesFlag{&refs}, "package", "package to watch as platform/name, can be repeated")
+		packages := fs.String("packages", "", "YAML file listing the packages to watch")
+		opt := &watcher.Options{}
+		fs.DurationVar(&opt.Interval, "interval", watcher.DefaultInterval, "time between two polls")
+		fs.StringVar(&opt.StatePath, "state", defaultStatePath(), "file the versions seen are kept in")
+		webhook := fs.String("webhook", "", "also post the releases as JSON to this URL")
+		slack := fs.String("slack", "", "also post the releases to this Slack incoming webhook URL")
+		jsonLines := fs.Bool("json", false, "print the releases as JSON lines")
+		once := fs.Bool("once", false, "poll once and exit")
+
+		return func(ctx context.Context, c *librariesio.Client, args []string) (interface{}, error) {
+			if *packages != "" {
+				loaded, err := loadPackages(*packages)
+				if err != nil {
+					return nil, err
+				}
+				refs = append(refs, loaded...)
+			}
+			if len(refs) == 0 {
+				return nil, fmt.Errorf("no packages given, use -package or -packages")
+			}
+
+			stdout, stderr := outputFrom(ctx)
+			opt.StatePath = expandHome(opt.StatePath)
+			opt.Notifiers = []watcher.Notifier{&watcher.Writer{W: stdout, JSON: *jsonLines}}
+			if *webhook != "" {
+				opt.Notifiers = append(opt.Notifiers, &watcher.Webhook{URL: *webhook})
+			}
+			if *slack != "" {
+				opt.Notifiers = append(opt.Notifiers, &watcher.Slack{URL: *slack})
+			}
+			opt.OnError = func(err error) {
+				fmt.Fprintf(stderr, "error: %v\n", err)
+			}
+
+			w := watcher.New(c.Projects, refs, opt)
+			if *once {
+				_, err := w.Poll(ctx)
+				return nil, err
+			}
+			return nil, w.Run(ctx)
+		}
+	},
+}
@@ -0,0 +1,199 @@
+/*
+Package depgraph resolves the dependency graph of projects on libraries.io
+by following Projects.Deps from a set of root dependencies.
+*/
+package depgraph
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/hackebrot/go-librariesio/librariesio"
+	"github.com/hackebrot/go-librariesio/librariesio/manifest"
+)
+
+// Node is a project in the dependency graph
+type Node struct {
+	librariesio.ProjectRef
+
+	// Version is the version the project was resolved at, "latest" unless
+	// the root dependency pinned a version
+	Version string
+
+	// Depth is 0 for root nodes and increases along the dependencies
+	Depth int
+
+	// Parent is the node through which this node was first reached, nil for
+	// root nodes
+	Parent *Node
+
+	// Project holds the project and its dependencies as returned by
+	// Projects.Deps. It is nil if the node was not fetched because of
+	// Options.MaxDepth or if the lookup failed, see Err.
+	Project *librariesio.Project
+
+	Err error
+
+	// Deps are the dependencies of the project
+	Deps []*Edge
+}
+
+// Edge connects a node to one of its dependencies
+type Edge struct {
+	// Dependency is the entry in the dependency list of the parent project,
+	// which holds the requirements and whether the dependency is deprecated
+	Dependency *librariesio.ProjectDependency
+
+	Node *Node
+}
+
+// Fetched reports whether the project of the node was looked up successfully
+func (n *Node) Fetched() bool {
+	return n.Project != nil
+}
+
+// ResolvedVersion returns the version number of the node, looking up the
+// latest release number of the project if the node was resolved at "latest"
+func (n *Node) ResolvedVersion() string {
+	if n.Version != "latest" {
+		return n.Version
+	}
+	if n.Project != nil && n.Project.LatestReleaseNumber != nil {
+		return *n.Project.LatestReleaseNumber
+	}
+	return ""
+}
+
+// Path returns the nodes from the root of the graph to n
+func (n *Node) Path() []*Node {
+	var path []*Node
+	for node := n; node != nil; node = node.Parent {
+		path = append([]*Node{node}, path...)
+	}
+	return path
+}
+
+// Graph is the resolved dependency graph of a set of root projects. Every
+// project is contained once, regardless of how many projects depend on it.
+type Graph struct {
+	Roots []*Node
+
+	// nodes in the order they were discovered
+	nodes []*Node
+}
+
+// Nodes returns all nodes of the graph in breadth-first order
+func (g *Graph) Nodes() []*Node {
+	return g.nodes
+}
+
+// Options control the resolution of a Graph
+type Options struct {
+	// MaxDepth is the depth up to which projects are fetched. Nodes deeper
+	// in the graph are included without their Project and dependencies.
+	// Zero only fetches the roots, a negative value removes the limit.
+	MaxDepth int
+}
+
+// Resolve builds the dependency graph for the given root dependencies.
+//
+// Roots are fetched at the version returned by Dependency.Version and fall
+// back to the latest release if that version is unknown to libraries.io.
+// Transitive dependencies are fetched at their latest release.
+//
+// Failing lookups are recorded in the Err field of the node. An error is only
+// returned if the context is cancelled.
+func Resolve(ctx context.Context, c librariesio.ProjectsAPI, roots []manifest.Dependency, opt *Options) (*Graph, error) {
+	if opt == nil {
+		opt = &Options{}
+	}
+
+	g := &Graph{}
+	seen := make(map[librariesio.ProjectRef]*Node)
+
+	add := func(ref librariesio.ProjectRef, version string, parent *Node) (*Node, bool) {
+		key := librariesio.ProjectRef{Platform: strings.ToLower(ref.Platform), Name: ref.Name}
+		if node, ok := seen[key]; ok {
+			return node, false
+		}
+
+		node := &Node{ProjectRef: ref, Version: version, Parent: parent}
+		if parent != nil {
+			node.Depth = parent.Depth + 1
+		}
+		seen[key] = node
+		g.nodes = append(g.nodes, node)
+		return node, true
+	}
+
+	var queue []*Node
+	for _, root := range roots {
+		version := root.Version()
+		if version == "" {
+			version = "latest"
+		}
+		if node, ok := add(root.ProjectRef, version, nil); ok {
+			g.Roots = append(g.Roots, node)
+			queue = append(queue, node)
+		}
+	}
+
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+
+		if opt.MaxDepth >= 0 && node.Depth > opt.MaxDepth {
+			continue
+		}
+
+		project, err := fetch(ctx, c, node)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			node.Err = err
+			continue
+		}
+		node.Project = project
+
+		for _, dep := range project.Dependencies {
+			if dep.Name == nil {
+				continue
+			}
+
+			ref := librariesio.ProjectRef{Platform: node.Platform, Name: *dep.Name}
+			if dep.Platform != nil && *dep.Platform != "" {
+				ref.Platform = strings.ToLower(*dep.Platform)
+			}
+
+			child, ok := add(ref, "latest", node)
+			if ok {
+				queue = append(queue, child)
+			}
+			node.Deps = append(node.Deps, &Edge{Dependency: dep, Node: child})
+		}
+	}
+
+	return g, nil
+}
+
+// fetch looks up the project of the node at its version and falls back to
+// the latest release if libraries.io doesn't know the version
+func fetch(ctx context.Context, c librariesio.ProjectsAPI, node *Node) (*librariesio.Project, error) {
+	project, _, err := c.Deps(ctx, node.Platform, node.Name, node.Version)
+	if err == nil || node.Version == "latest" {
+		return project, err
+	}
+
+	if errResp, ok := err.(*librariesio.ErrorResponse); !ok || errResp.Response.StatusCode != http.StatusNotFound {
+		return nil, err
+	}
+
+	project, _, err = c.Deps(ctx, node.Platform, node.Name, "latest")
+	if err != nil {
+		return nil, err
+	}
+	node.Version = "latest"
+	return project, nil
+}
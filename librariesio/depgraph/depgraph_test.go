@@ -0,0 +1,127 @@
+package depgraph
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/hackebrot/go-librariesio/librariesio"
+	"github.com/hackebrot/go-librariesio/librariesio/librariesiotest"
+	"github.com/hackebrot/go-librariesio/librariesio/manifest"
+)
+
+func root(plat, name, requirement string) manifest.Dependency {
+	return manifest.Dependency{
+		ProjectRef:  librariesio.ProjectRef{Platform: plat, Name: name},
+		Requirement: requirement,
+	}
+}
+
+func TestResolve(t *testing.T) {
+	server, mux, client := librariesiotest.NewMux()
+	defer server.Close()
+
+	mux.HandleFunc("/npm/ava/0.19.0/dependencies", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{
+			"name": "ava",
+			"dependencies": [
+				{"name": "chalk", "platform": "NPM", "requirements": "^1.1.3"},
+				{"name": "mocha", "platform": "NPM", "deprecated": true}
+			]
+		}`)
+	})
+	mux.HandleFunc("/npm/chalk/latest/dependencies", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{
+			"name": "chalk",
+			"latest_release_number": "2.0.0",
+			"dependencies": [{"name": "ava", "platform": "NPM"}]
+		}`)
+	})
+	mux.HandleFunc("/npm/mocha/latest/dependencies", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"error":"Not Found"}`, http.StatusNotFound)
+	})
+
+	g, err := Resolve(context.Background(), client.Projects, []manifest.Dependency{root("npm", "ava", "^0.19.0")}, &Options{MaxDepth: -1})
+	if err != nil {
+		t.Fatalf("Resolve returned unexpected error: %v", err)
+	}
+
+	if got, want := len(g.Roots), 1; got != want {
+		t.Fatalf("Expected %d roots, got %d", want, got)
+	}
+
+	nodes := g.Nodes()
+	if got, want := len(nodes), 3; got != want {
+		t.Fatalf("Expected %d nodes, got %d", want, got)
+	}
+
+	ava, chalk, mocha := nodes[0], nodes[1], nodes[2]
+
+	if ava.Version != "0.19.0" || !ava.Fetched() || len(ava.Deps) != 2 {
+		t.Errorf("unexpected root node %+v", ava)
+	}
+
+	if chalk.Platform != "npm" || chalk.Depth != 1 || chalk.ResolvedVersion() != "2.0.0" {
+		t.Errorf("unexpected node %+v", chalk)
+	}
+
+	// The cycle back to ava must not add another node
+	if len(chalk.Deps) != 1 || chalk.Deps[0].Node != ava {
+		t.Errorf("expected chalk to depend on the root node, got %+v", chalk.Deps)
+	}
+
+	if mocha.Fetched() || mocha.Err == nil {
+		t.Errorf("expected lookup error for %+v", mocha)
+	}
+
+	if path := mocha.Path(); len(path) != 2 || path[0] != ava || path[1] != mocha {
+		t.Errorf("unexpected path %+v", path)
+	}
+}
+
+func TestResolve_maxDepth(t *testing.T) {
+	server, mux, client := librariesiotest.NewMux()
+	defer server.Close()
+
+	mux.HandleFunc("/pypi/cookiecutter/latest/dependencies", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"name": "cookiecutter", "dependencies": [{"name": "poyo"}]}`)
+	})
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request %v", r.URL.Path)
+	})
+
+	g, err := Resolve(context.Background(), client.Projects, []manifest.Dependency{root("pypi", "cookiecutter", "")}, nil)
+	if err != nil {
+		t.Fatalf("Resolve returned unexpected error: %v", err)
+	}
+
+	nodes := g.Nodes()
+	if got, want := len(nodes), 2; got != want {
+		t.Fatalf("Expected %d nodes, got %d", want, got)
+	}
+	if poyo := nodes[1]; poyo.Fetched() || poyo.Err != nil || poyo.Platform != "pypi" {
+		t.Errorf("expected poyo not to be fetched, got %+v", poyo)
+	}
+}
+
+func TestResolve_fallbackToLatest(t *testing.T) {
+	server, mux, client := librariesiotest.NewMux()
+	defer server.Close()
+
+	mux.HandleFunc("/pypi/poyo/0.4/dependencies", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"error":"Not Found"}`, http.StatusNotFound)
+	})
+	mux.HandleFunc("/pypi/poyo/latest/dependencies", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"name": "poyo", "latest_release_number": "0.4.1"}`)
+	})
+
+	g, err := Resolve(context.Background(), client.Projects, []manifest.Dependency{root("pypi", "poyo", ">=0.4")}, nil)
+	if err != nil {
+		t.Fatalf("Resolve returned unexpected error: %v", err)
+	}
+
+	if poyo := g.Roots[0]; !poyo.Fetched() || poyo.Version != "latest" || poyo.ResolvedVersion() != "0.4.1" {
+		t.Errorf("unexpected node %+v", poyo)
+	}
+}
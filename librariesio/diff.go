@@ -0,0 +1,114 @@
+package librariesio
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ChangeKind is the type of a Change
+type ChangeKind string
+
+// Kinds of changes found by Diff
+const (
+	ChangeNewVersion     ChangeKind = "new_version"
+	ChangeRemovedVersion ChangeKind = "removed_version"
+	ChangeLicense        ChangeKind = "license"
+	ChangeStatus         ChangeKind = "status"
+	ChangeRepository     ChangeKind = "repository"
+	ChangeHomepage       ChangeKind = "homepage"
+)
+
+// Change is a difference between two snapshots of a project
+type Change struct {
+	Kind ChangeKind `json:"kind"`
+
+	// Old and New are the values before and after the change. Old is empty
+	// for new versions and New for removed versions. Licenses are sorted and
+	// separated by commas.
+	Old string `json:"old,omitempty"`
+	New string `json:"new,omitempty"`
+}
+
+// String describes the change
+func (c *Change) String() string {
+	switch c.Kind {
+	case ChangeNewVersion:
+		return fmt.Sprintf("new version %v", c.New)
+	case ChangeRemovedVersion:
+		return fmt.Sprintf("removed version %v", c.Old)
+	}
+	return fmt.Sprintf("%v changed from %q to %q", c.Kind, c.Old, c.New)
+}
+
+// Diff returns the changes from the old to the new snapshot of a project:
+// new and removed versions in the order of the snapshots, followed by
+// changes of the license, status, repository URL and homepage. A nil
+// project has no versions and no values.
+func Diff(old, new *Project) []*Change {
+	if old == nil {
+		old = &Project{}
+	}
+	if new == nil {
+		new = &Project{}
+	}
+
+	changes := []*Change{}
+
+	oldVersions := versionSet(old.Versions)
+	newVersions := versionSet(new.Versions)
+	for _, r := range new.Versions {
+		if r != nil && r.Number != nil && !oldVersions[*r.Number] {
+			changes = append(changes, &Change{Kind: ChangeNewVersion, New: *r.Number})
+		}
+	}
+	for _, r := range old.Versions {
+		if r != nil && r.Number != nil && !newVersions[*r.Number] {
+			changes = append(changes, &Change{Kind: ChangeRemovedVersion, Old: *r.Number})
+		}
+	}
+
+	add := func(kind ChangeKind, old, new string) {
+		if old != new {
+			changes = append(changes, &Change{Kind: kind, Old: old, New: new})
+		}
+	}
+	add(ChangeLicense, licenses(old), licenses(new))
+	add(ChangeStatus, stringValue(old.Status), stringValue(new.Status))
+	add(ChangeRepository, stringValue(old.RepositoryURL), stringValue(new.RepositoryURL))
+	add(ChangeHomepage, stringValue(old.Homepage), stringValue(new.Homepage))
+
+	return changes
+}
+
+// versionSet returns the numbers of the releases
+func versionSet(releases []*Release) map[string]bool {
+	set := make(map[string]bool)
+	for _, r := range releases {
+		if r != nil && r.Number != nil {
+			set[*r.Number] = true
+		}
+	}
+	return set
+}
+
+// licenses returns the sorted normalized licenses of the project separated
+// by commas
+func licenses(p *Project) string {
+	var l []string
+	for _, license := range p.NormalizedLicenses {
+		if license != nil {
+			l = append(l, *license)
+		}
+	}
+	sort.Strings(l)
+	return strings.Join(l, ", ")
+}
+
+// stringValue returns the value of s or "" for nil
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,92 @@
+package librariesio
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/hackebrot/go-repr/repr"
+)
+
+func TestDiff(t *testing.T) {
+	old := &Project{
+		Name:               String("cookiecutter"),
+		NormalizedLicenses: []*string{String("BSD-3-Clause")},
+		RepositoryURL:      String("https://github.com/audreyr/cookiecutter"),
+		Versions: []*Release{
+			{Number: String("1.4.0")},
+			{Number: String("1.5.0")},
+		},
+	}
+	new := &Project{
+		Name:               String("cookiecutter"),
+		NormalizedLicenses: []*string{String("GPL-3.0"), String("BSD-3-Clause")},
+		RepositoryURL:      String("https://github.com/cookiecutter/cookiecutter"),
+		Status:             String("Deprecated"),
+		Versions: []*Release{
+			{Number: String("1.5.0")},
+			{Number: String("1.5.1")},
+			{Number: String("1.6.0")},
+		},
+	}
+
+	want := []*Change{
+		{Kind: ChangeNewVersion, New: "1.5.1"},
+		{Kind: ChangeNewVersion, New: "1.6.0"},
+		{Kind: ChangeRemovedVersion, Old: "1.4.0"},
+		{Kind: ChangeLicense, Old: "BSD-3-Clause", New: "BSD-3-Clause, GPL-3.0"},
+		{Kind: ChangeStatus, Old: "", New: "Deprecated"},
+		{Kind: ChangeRepository, Old: "https://github.com/audreyr/cookiecutter", New: "https://github.com/cookiecutter/cookiecutter"},
+	}
+
+	if got := Diff(old, new); !reflect.DeepEqual(got, want) {
+		t.Errorf("\nExpected %v\nGot %v", repr.Repr(want), repr.Repr(got))
+	}
+	if got := Diff(new, new); len(got) != 0 {
+		t.Errorf("expected no changes, got %v", repr.Repr(got))
+	}
+}
+
+func TestDiff_nil(t *testing.T) {
+	new := &Project{Versions: []*Release{{Number: String("1.0.0")}}}
+
+	want := []*Change{{Kind: ChangeNewVersion, New: "1.0.0"}}
+	if got := Diff(nil, new); !reflect.DeepEqual(got, want) {
+		t.Errorf("\nExpected %v\nGot %v", repr.Repr(want), repr.Repr(got))
+	}
+}
+
+func TestDiff_nullVersion(t *testing.T) {
+	var old, new Project
+	if err := json.Unmarshal([]byte(`{"versions": [null, {"number": "1.0.0"}]}`), &old); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := json.Unmarshal([]byte(`{"versions": [{"number": "1.1.0"}, null]}`), &new); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []*Change{
+		{Kind: ChangeNewVersion, New: "1.1.0"},
+		{Kind: ChangeRemovedVersion, Old: "1.0.0"},
+	}
+	if got := Diff(&old, &new); !reflect.DeepEqual(got, want) {
+		t.Errorf("\nExpected %v\nGot %v", repr.Repr(want), repr.Repr(got))
+	}
+}
+
+func TestChange_String(t *testing.T) {
+	testCases := []struct {
+		change *Change
+		want   string
+	}{
+		{&Change{Kind: ChangeNewVersion, New: "1.6.0"}, "new version 1.6.0"},
+		{&Change{Kind: ChangeRemovedVersion, Old: "1.4.0"}, "removed version 1.4.0"},
+		{&Change{Kind: ChangeLicense, Old: "MIT", New: "GPL-3.0"}, `license changed from "MIT" to "GPL-3.0"`},
+	}
+
+	for _, tc := range testCases {
+		if got := tc.change.String(); got != tc.want {
+			t.Errorf("\nExpected %v\nGot %v", tc.want, got)
+		}
+	}
+}
@@ -0,0 +1,348 @@
+/*
+Package exporter exposes metadata of packages on libraries.io as Prometheus
+metrics.
+
+An Exporter polls the configured packages in the background and caches the
+results, so scrapes never wait for or trigger requests to the API:
+
+	e := exporter.New(client.Projects, refs, &exporter.Options{Interval: time.Hour})
+	prometheus.MustRegister(e)
+	go e.Run(ctx)
+
+The packages are polled one after another, spread evenly over the interval.
+If the interval is too short for the number of packages and the allowed
+requests per minute, the polls are spaced out further. A rate limit response
+of the API pauses polling until the limit resets.
+*/
+package exporter
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/hackebrot/go-librariesio/librariesio"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// DefaultInterval is the default time between two polls of a package
+	DefaultInterval = time.Hour
+
+	// DefaultRequestsPerMinute is the rate limit of the libraries.io API
+	DefaultRequestsPerMinute = 60
+
+	// requestsPerPoll is the number of requests made to poll a package
+	requestsPerPoll = 2
+
+	// rateLimitPause is waited for after the rate limit was hit if the
+	// response has no Retry-After header
+	rateLimitPause = time.Minute
+)
+
+// Options configure an Exporter
+type Options struct {
+	// Interval is the time between two polls of the same package,
+	// DefaultInterval if zero
+	Interval time.Duration
+
+	// RequestsPerMinute is the number of requests the exporter may make,
+	// DefaultRequestsPerMinute if zero
+	RequestsPerMinute int
+
+	// Timeout limits the requests of a poll, zero means no timeout
+	Timeout time.Duration
+}
+
+// snapshot is the latest data of a package
+type snapshot struct {
+	project    *librariesio.Project
+	sourceRank *librariesio.SourceRank
+	updated    time.Time
+	errors     int
+}
+
+// Exporter is a prometheus.Collector for the metadata of packages
+type Exporter struct {
+	client   librariesio.ProjectsAPI
+	packages []librariesio.ProjectRef
+	opt      Options
+
+	// now returns the current time, it is replaced in tests
+	now func() time.Time
+
+	mu        sync.Mutex
+	snapshots map[librariesio.ProjectRef]*snapshot
+}
+
+// New returns an Exporter for the packages. Packages given more than once
+// are only polled and exported once. Call Run to start polling.
+func New(c librariesio.ProjectsAPI, packages []librariesio.ProjectRef, opt *Options) *Exporter {
+	var unique []librariesio.ProjectRef
+	seen := make(map[librariesio.ProjectRef]bool)
+	for _, ref := range packages {
+		if !seen[ref] {
+			seen[ref] = true
+			unique = append(unique, ref)
+		}
+	}
+
+	e := &Exporter{
+		client:    c,
+		packages:  unique,
+		now:       time.Now,
+		snapshots: make(map[librariesio.ProjectRef]*snapshot),
+	}
+	if opt != nil {
+		e.opt = *opt
+	}
+	if e.opt.Interval <= 0 {
+		e.opt.Interval = DefaultInterval
+	}
+	if e.opt.RequestsPerMinute <= 0 {
+		e.opt.RequestsPerMinute = DefaultRequestsPerMinute
+	}
+	return e
+}
+
+// spacing returns the time between two polls
+func (e *Exporter) spacing() time.Duration {
+	spacing := e.opt.Interval / time.Duration(len(e.packages))
+	if quota := requestsPerPoll * time.Minute / time.Duration(e.opt.RequestsPerMinute); spacing < quota {
+		spacing = quota
+	}
+	return spacing
+}
+
+// Run polls the packages until the context is cancelled
+func (e *Exporter) Run(ctx context.Context) error {
+	if len(e.packages) == 0 {
+		<-ctx.Done()
+		return nil
+	}
+
+	for i := 0; ; i = (i + 1) % len(e.packages) {
+		delay := e.spacing()
+		if pause := e.Poll(ctx, e.packages[i]); pause > delay {
+			delay = pause
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil
+		case <-timer.C:
+		}
+	}
+}
+
+// Poll fetches the project and SourceRank of the package and updates the
+// cached metrics. It returns how long to pause polling if the rate limit
+// of the API was hit.
+func (e *Exporter) Poll(ctx context.Context, ref librariesio.ProjectRef) time.Duration {
+	if e.opt.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, e.opt.Timeout)
+		defer cancel()
+	}
+
+	project, resp, err := e.client.Get(ctx, ref.Platform, ref.Name)
+	if err != nil {
+		e.update(ref, func(s *snapshot) { s.errors++ })
+		return pause(resp, err)
+	}
+
+	sourceRank, resp, err := e.client.SourceRank(ctx, ref.Platform, ref.Name)
+	e.update(ref, func(s *snapshot) {
+		s.project = project
+		s.updated = e.now()
+		if err != nil {
+			s.errors++
+			return
+		}
+		s.sourceRank = sourceRank
+	})
+	return pause(resp, err)
+}
+
+// update changes the snapshot of the package
+func (e *Exporter) update(ref librariesio.ProjectRef, f func(s *snapshot)) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	s, ok := e.snapshots[ref]
+	if !ok {
+		s = &snapshot{}
+		e.snapshots[ref] = s
+	}
+	f(s)
+}
+
+// pause returns how long to wait after the response before the next request
+func pause(resp *http.Response, err error) time.Duration {
+	if resp == nil {
+		return 0
+	}
+	if errResp, ok := err.(*librariesio.ErrorResponse); ok && errResp.Response.StatusCode == http.StatusTooManyRequests {
+		if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds > 0 {
+			return time.Duration(seconds) * time.Second
+		}
+		return rateLimitPause
+	}
+	if resp.Header.Get("X-RateLimit-Remaining") == "0" {
+		return rateLimitPause
+	}
+	return 0
+}
+
+var (
+	labels = []string{"platform", "name"}
+
+	starsDesc = prometheus.NewDesc(
+		"librariesio_project_stars",
+		"Number of stars of the repository of the project.",
+		labels, nil,
+	)
+	forksDesc = prometheus.NewDesc(
+		"librariesio_project_forks",
+		"Number of forks of the repository of the project.",
+		labels, nil,
+	)
+	rankDesc = prometheus.NewDesc(
+		"librariesio_project_rank",
+		"SourceRank of the project.",
+		labels, nil,
+	)
+	dependentsDesc = prometheus.NewDesc(
+		"librariesio_project_dependents_count",
+		"Number of packages depending on the project.",
+		labels, nil,
+	)
+	dependentReposDesc = prometheus.NewDesc(
+		"librariesio_project_dependent_repos_count",
+		"Number of repositories depending on the project.",
+		labels, nil,
+	)
+	daysSinceReleaseDesc = prometheus.NewDesc(
+		"librariesio_project_days_since_last_release",
+		"Days since the latest release of the project was published.",
+		labels, nil,
+	)
+	versionDesc = prometheus.NewDesc(
+		"librariesio_project_latest_version_info",
+		"Latest release of the project, the value is always 1.",
+		append(labels, "version", "stable_version"), nil,
+	)
+	sourceRankDesc = prometheus.NewDesc(
+		"librariesio_project_sourcerank_component",
+		"Score of a component of the SourceRank of the project.",
+		append(labels, "component"), nil,
+	)
+	updatedDesc = prometheus.NewDesc(
+		"librariesio_project_last_update_timestamp_seconds",
+		"Time the metrics of the project were last fetched.",
+		labels, nil,
+	)
+	errorsDesc = prometheus.NewDesc(
+		"librariesio_project_poll_errors_total",
+		"Number of failed requests for the project.",
+		labels, nil,
+	)
+)
+
+// Describe implements prometheus.Collector
+func (e *Exporter) Describe(ch chan<- *prometheus.Desc) {
+	for _, desc := range []*prometheus.Desc{
+		starsDesc, forksDesc, rankDesc, dependentsDesc, dependentReposDesc,
+		daysSinceReleaseDesc, versionDesc, sourceRankDesc, updatedDesc, errorsDesc,
+	} {
+		ch <- desc
+	}
+}
+
+// Collect implements prometheus.Collector. It only reports the cached data
+// of the packages which were polled.
+func (e *Exporter) Collect(ch chan<- prometheus.Metric) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	now := e.now()
+	for _, ref := range e.packages {
+		s, ok := e.snapshots[ref]
+		if !ok {
+			continue
+		}
+		values := []string{ref.Platform, ref.Name}
+
+		ch <- prometheus.MustNewConstMetric(errorsDesc, prometheus.CounterValue, float64(s.errors), values...)
+
+		p := s.project
+		if p == nil {
+			continue
+		}
+
+		ch <- prometheus.MustNewConstMetric(updatedDesc, prometheus.GaugeValue, float64(s.updated.Unix()), values...)
+
+		gauges := map[*prometheus.Desc]*int{
+			starsDesc:          p.Stars,
+			forksDesc:          p.Forks,
+			rankDesc:           p.Rank,
+			dependentsDesc:     p.DependentsCount,
+			dependentReposDesc: p.DependentReposCount,
+		}
+		for desc, value := range gauges {
+			if value != nil {
+				ch <- prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, float64(*value), values...)
+			}
+		}
+
+		if p.LatestReleasePublishedAt != nil {
+			days := now.Sub(*p.LatestReleasePublishedAt).Hours() / 24
+			ch <- prometheus.MustNewConstMetric(daysSinceReleaseDesc, prometheus.GaugeValue, days, values...)
+		}
+
+		if p.LatestReleaseNumber != nil {
+			stable := ""
+			if p.LatestStableRelease != nil && p.LatestStableRelease.Number != nil {
+				stable = *p.LatestStableRelease.Number
+			}
+			ch <- prometheus.MustNewConstMetric(versionDesc, prometheus.GaugeValue, 1, append(values, *p.LatestReleaseNumber, stable)...)
+		}
+
+		if s.sourceRank != nil {
+			for component, value := range sourceRankComponents(s.sourceRank) {
+				if value != nil {
+					ch <- prometheus.MustNewConstMetric(sourceRankDesc, prometheus.GaugeValue, float64(*value), append(values, component)...)
+				}
+			}
+		}
+	}
+}
+
+// sourceRankComponents returns the components of the SourceRank by name
+func sourceRankComponents(r *librariesio.SourceRank) map[string]*int {
+	return map[string]*int{
+		"all_prereleases":           r.AllPrereleases,
+		"any_outdated_dependencies": r.AnyOutdatedDependencies,
+		"basic_info_present":        r.BasicInfoPresent,
+		"contributors":              r.Contributors,
+		"dependent_projects":        r.DependentProjects,
+		"dependent_repositories":    r.DependentRepositories,
+		"follows_semver":            r.FollowsSemver,
+		"is_deprecated":             r.IsDeprecated,
+		"is_removed":                r.IsRemoved,
+		"is_unmaintained":           r.IsUnmaintained,
+		"license_present":           r.LicensePresent,
+		"not_brand_new":             r.NotBrandNew,
+		"one_point_oh":              r.OnePointOh,
+		"readme_present":            r.ReadmePresent,
+		"recent_release":            r.RecentRelease,
+		"repository_present":        r.RepositoryPresent,
+		"stars":                     r.Stars,
+		"subscribers":               r.Subscribers,
+		"versions_present":          r.VersionsPresent,
+	}
+}
@@ -0,0 +1,161 @@
+package exporter
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hackebrot/go-librariesio/librariesio"
+	"github.com/hackebrot/go-librariesio/librariesio/librariesiotest"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+var now = time.Date(2017, time.June, 1, 0, 0, 0, 0, time.UTC)
+
+func newTestServer() *librariesiotest.Server {
+	return librariesiotest.NewServer(&librariesiotest.Fixtures{
+		Projects: []*librariesiotest.ProjectFixture{
+			{
+				Project: &librariesio.Project{
+					Platform:                 librariesio.String("Pypi"),
+					Name:                     librariesio.String("cookiecutter"),
+					Stars:                    librariesio.Int(6000),
+					Forks:                    librariesio.Int(900),
+					Rank:                     librariesio.Int(24),
+					DependentsCount:          librariesio.Int(120),
+					LatestReleaseNumber:      librariesio.String("1.6.0rc1"),
+					LatestReleasePublishedAt: librariesio.Time(now.AddDate(0, 0, -10)),
+					LatestStableRelease:      &librariesio.Release{Number: librariesio.String("1.5.1")},
+				},
+				SourceRank: &librariesio.SourceRank{Stars: librariesio.Int(7)},
+			},
+		},
+	})
+}
+
+func TestExporter(t *testing.T) {
+	server := newTestServer()
+	defer server.Close()
+
+	refs := []librariesio.ProjectRef{
+		{Platform: "pypi", Name: "cookiecutter"},
+		{Platform: "pypi", Name: "missing"},
+		{Platform: "pypi", Name: "unpolled"},
+	}
+	e := New(server.NewClient().Projects, refs, nil)
+	e.now = func() time.Time { return now }
+
+	for _, ref := range refs[:2] {
+		if pause := e.Poll(context.Background(), ref); pause != 0 {
+			t.Errorf("expected no pause, got %v", pause)
+		}
+	}
+
+	expected := `
+# HELP librariesio_project_days_since_last_release Days since the latest release of the project was published.
+# TYPE librariesio_project_days_since_last_release gauge
+librariesio_project_days_since_last_release{name="cookiecutter",platform="pypi"} 10
+# HELP librariesio_project_dependents_count Number of packages depending on the project.
+# TYPE librariesio_project_dependents_count gauge
+librariesio_project_dependents_count{name="cookiecutter",platform="pypi"} 120
+# HELP librariesio_project_latest_version_info Latest release of the project, the value is always 1.
+# TYPE librariesio_project_latest_version_info gauge
+librariesio_project_latest_version_info{name="cookiecutter",platform="pypi",stable_version="1.5.1",version="1.6.0rc1"} 1
+# HELP librariesio_project_poll_errors_total Number of failed requests for the project.
+# TYPE librariesio_project_poll_errors_total counter
+librariesio_project_poll_errors_total{name="cookiecutter",platform="pypi"} 0
+librariesio_project_poll_errors_total{name="missing",platform="pypi"} 1
+# HELP librariesio_project_sourcerank_component Score of a component of the SourceRank of the project.
+# TYPE librariesio_project_sourcerank_component gauge
+librariesio_project_sourcerank_component{component="stars",name="cookiecutter",platform="pypi"} 7
+# HELP librariesio_project_stars Number of stars of the repository of the project.
+# TYPE librariesio_project_stars gauge
+librariesio_project_stars{name="cookiecutter",platform="pypi"} 6000
+`
+	names := []string{
+		"librariesio_project_days_since_last_release",
+		"librariesio_project_dependents_count",
+		"librariesio_project_latest_version_info",
+		"librariesio_project_poll_errors_total",
+		"librariesio_project_sourcerank_component",
+		"librariesio_project_stars",
+	}
+	if err := testutil.CollectAndCompare(e, strings.NewReader(expected), names...); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestExporter_rateLimit(t *testing.T) {
+	server := newTestServer()
+	defer server.Close()
+	server.SetRateLimit(0)
+
+	ref := librariesio.ProjectRef{Platform: "pypi", Name: "cookiecutter"}
+	e := New(server.NewClient().Projects, []librariesio.ProjectRef{ref}, nil)
+
+	if got, want := e.Poll(context.Background(), ref), 60*time.Second; got != want {
+		t.Errorf("\nExpected %v\nGot %v", want, got)
+	}
+}
+
+func TestExporter_spacing(t *testing.T) {
+	refs := make([]librariesio.ProjectRef, 10)
+	for i := range refs {
+		refs[i] = librariesio.ProjectRef{Platform: "pypi", Name: fmt.Sprintf("package-%d", i)}
+	}
+
+	tests := []struct {
+		opt  *Options
+		want time.Duration
+	}{
+		{nil, 6 * time.Minute},
+		{&Options{Interval: time.Minute}, 6 * time.Second},
+		{&Options{Interval: 10 * time.Second}, 2 * time.Second},
+		{&Options{Interval: 10 * time.Second, RequestsPerMinute: 600}, time.Second},
+	}
+
+	for _, tt := range tests {
+		if got := New(nil, refs, tt.opt).spacing(); got != tt.want {
+			t.Errorf("%+v: expected %v, got %v", tt.opt, tt.want, got)
+		}
+	}
+}
+
+func TestExporter_run(t *testing.T) {
+	server := newTestServer()
+	defer server.Close()
+
+	ref := librariesio.ProjectRef{Platform: "pypi", Name: "cookiecutter"}
+	e := New(server.NewClient().Projects, []librariesio.ProjectRef{ref}, &Options{RequestsPerMinute: 6000})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if err := e.Run(ctx); err != nil {
+		t.Fatalf("Run returned unexpected error: %v", err)
+	}
+
+	if n := testutil.CollectAndCount(e, "librariesio_project_stars"); n != 1 {
+		t.Errorf("expected the package to be polled, got %d metrics", n)
+	}
+}
+
+func TestExporter_duplicatePackages(t *testing.T) {
+	server := newTestServer()
+	defer server.Close()
+
+	ref := librariesio.ProjectRef{Platform: "pypi", Name: "cookiecutter"}
+	e := New(server.NewClient().Projects, []librariesio.ProjectRef{ref, ref}, nil)
+	e.Poll(context.Background(), ref)
+
+	if got, want := len(e.packages), 1; got != want {
+		t.Errorf("\nExpected %v\nGot %v", want, got)
+	}
+	if _, err := testutil.CollectAndLint(e); err != nil {
+		t.Errorf("Collect returned unexpected error: %v", err)
+	}
+	if got, want := testutil.CollectAndCount(e, "librariesio_project_stars"), 1; got != want {
+		t.Errorf("\nExpected %v\nGot %v", want, got)
+	}
+}
@@ -74,17 +74,32 @@ type Repository struct {
 	SubscribersCount         *int       `json:"subscribers_count,omitempty"`
 	UUID                     *string    `json:"uuid,omitempty"`
 	UpdatedAt                *time.Time `json:"updated_at,omitempty"`
+
+	// Dependencies are only set by Repositories.Deps
+	Dependencies []*RepositoryDependency `json:"dependencies,omitempty"`
 }
 
-// User returns information for a given user or organization
+// RepositoryDependency represents a dependency declared in a manifest file
+// of a repository
+type RepositoryDependency struct {
+	ProjectDependency
+
+	Filepath *string `json:"filepath,omitempty"`
+	Kind     *string `json:"kind,omitempty"`
+}
+
+// UsersService handles the GitHub user endpoints of the API
+type UsersService service
+
+// Get returns information for a given user or organization
 //
 // GET https://libraries.io/api/github/:login
 //
 // login is a user or organization on GitHub
-func (c *Client) User(ctx context.Context, login string) (*User, *http.Response, error) {
+func (s *UsersService) Get(ctx context.Context, login string) (*User, *http.Response, error) {
 	urlStr := fmt.Sprintf("github/%v", login)
 
-	request, err := c.NewRequest("GET", urlStr, nil)
+	request, err := s.client.NewRequest("GET", urlStr, nil)
 
 	if err != nil {
 		return nil, nil, err
@@ -92,7 +107,7 @@ func (c *Client) User(ctx context.Context, login string) (*User, *http.Response,
 
 	user := new(User)
 
-	response, err := c.Do(ctx, request, user)
+	response, err := s.client.Do(ctx, request, user)
 	if err != nil {
 		return nil, response, err
 	}
@@ -100,23 +115,24 @@ func (c *Client) User(ctx context.Context, login string) (*User, *http.Response,
 	return user, response, nil
 }
 
-// UserProjects returns projects referencing the given GitHub user
+// Projects returns projects referencing the given GitHub user
 //
 // GET https://libraries.io/api/github/:login/projects
 //
 // login is a user or organization on GitHub
-func (c *Client) UserProjects(ctx context.Context, login string) ([]*Project, *http.Response, error) {
+func (s *UsersService) Projects(ctx context.Context, login string, opt *ListOptions) ([]*Project, *http.Response, error) {
 	urlStr := fmt.Sprintf("github/%v/projects", login)
 
-	request, err := c.NewRequest("GET", urlStr, nil)
+	request, err := s.client.NewRequest("GET", urlStr, nil)
 
 	if err != nil {
 		return nil, nil, err
 	}
+	addListOptions(request, opt)
 
 	var projects []*Project
 
-	response, err := c.Do(ctx, request, &projects)
+	response, err := s.client.Do(ctx, request, &projects)
 	if err != nil {
 		return nil, response, err
 	}
@@ -124,25 +140,99 @@ func (c *Client) UserProjects(ctx context.Context, login string) ([]*Project, *h
 	return projects, response, nil
 }
 
-// UserRepositories returns repositories owned by the given GitHub user
+// Repositories returns repositories owned by the given GitHub user
 //
 // GET https://libraries.io/api/github/:login/repositories
 //
 // login is a user or organization on GitHub
-func (c *Client) UserRepositories(ctx context.Context, login string) ([]*Repository, *http.Response, error) {
+func (s *UsersService) Repositories(ctx context.Context, login string, opt *ListOptions) ([]*Repository, *http.Response, error) {
 	urlStr := fmt.Sprintf("github/%v/repositories", login)
 
-	request, err := c.NewRequest("GET", urlStr, nil)
+	request, err := s.client.NewRequest("GET", urlStr, nil)
 
 	if err != nil {
 		return nil, nil, err
 	}
+	addListOptions(request, opt)
 	var repos []*Repository
 
-	response, err := c.Do(ctx, request, &repos)
+	response, err := s.client.Do(ctx, request, &repos)
 	if err != nil {
 		return nil, response, err
 	}
 
 	return repos, response, nil
 }
+
+// RepositoriesService handles the GitHub repository endpoints of the API
+type RepositoriesService service
+
+// Get returns information about a repository
+//
+// GET https://libraries.io/api/github/:owner/:name
+//
+// owner is the user or organization owning the repository on GitHub
+// name is the name of the repository
+func (s *RepositoriesService) Get(ctx context.Context, owner, name string) (*Repository, *http.Response, error) {
+	urlStr := fmt.Sprintf("github/%v/%v", owner, name)
+
+	request, err := s.client.NewRequest("GET", urlStr, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	repo := new(Repository)
+
+	response, err := s.client.Do(ctx, request, repo)
+	if err != nil {
+		return nil, response, err
+	}
+
+	return repo, response, nil
+}
+
+// Deps returns information about a repository and the dependencies
+// declared in its manifest files
+//
+// GET https://libraries.io/api/github/:owner/:name/dependencies
+//
+// owner is the user or organization owning the repository on GitHub
+// name is the name of the repository
+func (s *RepositoriesService) Deps(ctx context.Context, owner, name string) (*Repository, *http.Response, error) {
+	urlStr := fmt.Sprintf("github/%v/%v/dependencies", owner, name)
+
+	request, err := s.client.NewRequest("GET", urlStr, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	repo := new(Repository)
+
+	response, err := s.client.Do(ctx, request, repo)
+	if err != nil {
+		return nil, response, err
+	}
+
+	return repo, response, nil
+}
+
+// User returns information for a given user or organization
+//
+// Deprecated: Use Client.Users.Get instead.
+func (c *Client) User(ctx context.Context, login string) (*User, *http.Response, error) {
+	return c.Users.Get(ctx, login)
+}
+
+// UserProjects returns projects referencing the given GitHub user
+//
+// Deprecated: Use Client.Users.Projects instead.
+func (c *Client) UserProjects(ctx context.Context, login string) ([]*Project, *http.Response, error) {
+	return c.Users.Projects(ctx, login, nil)
+}
+
+// UserRepositories returns repositories owned by the given GitHub user
+//
+// Deprecated: Use Client.Users.Repositories instead.
+func (c *Client) UserRepositories(ctx context.Context, login string) ([]*Repository, *http.Response, error) {
+	return c.Users.Repositories(ctx, login, nil)
+}
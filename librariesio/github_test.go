@@ -175,3 +175,108 @@ func TestUserRepositories(t *testing.T) {
 		t.Errorf("\nExpected %v\nGot %v", repr.Repr(want), repr.Repr(repos))
 	}
 }
+
+func TestRepositoriesGet(t *testing.T) {
+	server, mux, url := startNewServer()
+	client := NewClient(APIKey)
+	client.BaseURL = url
+	defer server.Close()
+
+	mux.HandleFunc("/github/hackebrot/go-repr", func(w http.ResponseWriter, r *http.Request) {
+		if method := "GET"; method != r.Method {
+			t.Errorf("expected HTTP %v request, got %v", method, r.Method)
+		}
+		fmt.Fprintf(w, `{"full_name":"hackebrot/go-repr","language":"Go"}`)
+	})
+
+	repo, _, err := client.Repositories.Get(context.Background(), "hackebrot", "go-repr")
+	if err != nil {
+		t.Fatalf("Repositories.Get returned unexpected error: %v", err)
+	}
+
+	want := &Repository{FullName: String("hackebrot/go-repr"), Language: String("Go")}
+
+	if !reflect.DeepEqual(repo, want) {
+		t.Errorf("\nExpected %v\nGot %v", repr.Repr(want), repr.Repr(repo))
+	}
+}
+
+func TestRepositoriesDeps(t *testing.T) {
+	server, mux, url := startNewServer()
+	client := NewClient(APIKey)
+	client.BaseURL = url
+	defer server.Close()
+
+	mux.HandleFunc("/github/hackebrot/go-librariesio/dependencies", func(w http.ResponseWriter, r *http.Request) {
+		if method := "GET"; method != r.Method {
+			t.Errorf("expected HTTP %v request, got %v", method, r.Method)
+		}
+		fmt.Fprintf(w, `{
+			"full_name": "hackebrot/go-librariesio",
+			"dependencies": [
+				{
+					"project_name": "github.com/hackebrot/go-repr",
+					"name": "github.com/hackebrot/go-repr",
+					"platform": "Go",
+					"requirements": "*",
+					"outdated": false,
+					"filepath": "Gopkg.toml",
+					"kind": "runtime"
+				}
+			]
+		}`)
+	})
+
+	repo, _, err := client.Repositories.Deps(context.Background(), "hackebrot", "go-librariesio")
+	if err != nil {
+		t.Fatalf("Repositories.Deps returned unexpected error: %v", err)
+	}
+
+	want := &Repository{
+		FullName: String("hackebrot/go-librariesio"),
+		Dependencies: []*RepositoryDependency{
+			{
+				ProjectDependency: ProjectDependency{
+					ProjectName:  String("github.com/hackebrot/go-repr"),
+					Name:         String("github.com/hackebrot/go-repr"),
+					Platform:     String("Go"),
+					Requirements: String("*"),
+					Outdated:     Bool(false),
+				},
+				Filepath: String("Gopkg.toml"),
+				Kind:     String("runtime"),
+			},
+		},
+	}
+
+	if !reflect.DeepEqual(repo, want) {
+		t.Errorf("\nExpected %v\nGot %v", repr.Repr(want), repr.Repr(repo))
+	}
+}
+
+func TestUsersProjects_listOptions(t *testing.T) {
+	server, mux, url := startNewServer()
+	client := NewClient(APIKey)
+	client.BaseURL = url
+	defer server.Close()
+
+	mux.HandleFunc("/github/hackebrot/projects", func(w http.ResponseWriter, r *http.Request) {
+		if page := r.URL.Query().Get("page"); page != "2" {
+			t.Errorf("expected page 2, got %v", page)
+		}
+		if perPage := r.URL.Query().Get("per_page"); perPage != "50" {
+			t.Errorf("expected per_page 50, got %v", perPage)
+		}
+		fmt.Fprintf(w, `[{"name":"cookiecutter"}]`)
+	})
+
+	projects, _, err := client.Users.Projects(context.Background(), "hackebrot", &ListOptions{Page: 2, PerPage: 50})
+	if err != nil {
+		t.Fatalf("Users.Projects returned unexpected error: %v", err)
+	}
+
+	want := []*Project{{Name: String("cookiecutter")}}
+	if !reflect.DeepEqual(projects, want) {
+		t.Errorf("\nExpected %v\nGot %v", repr.Repr(want), repr.Repr(projects))
+	}
+}
@@ -0,0 +1,212 @@
+/*
+Package health finds deprecated, unmaintained, removed and long unreleased
+projects among a set of dependencies, e.g. to fail a CI build before an
+abandoned library is adopted.
+*/
+package health
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/hackebrot/go-librariesio/librariesio"
+	"github.com/hackebrot/go-librariesio/librariesio/depgraph"
+)
+
+// DefaultMaxAge is the time since the latest release after which a project is
+// considered stale, unless Options.MaxAge is set
+const DefaultMaxAge = 2 * 365 * 24 * time.Hour
+
+// Reason describes why a project is reported as a Problem
+type Reason string
+
+// Reasons for reporting a project
+const (
+	ReasonDeprecated   Reason = "deprecated"
+	ReasonUnmaintained Reason = "unmaintained"
+	ReasonRemoved      Reason = "removed"
+	ReasonNotFound     Reason = "not_found"
+	ReasonStale        Reason = "stale"
+
+	// ReasonError is reported if the project could not be looked up
+	ReasonError Reason = "error"
+)
+
+// statusReasons maps the Project.Status values on libraries.io to Reasons
+var statusReasons = map[string]Reason{
+	"deprecated":   ReasonDeprecated,
+	"unmaintained": ReasonUnmaintained,
+	"removed":      ReasonRemoved,
+}
+
+// Problem is a finding for a single project
+type Problem struct {
+	librariesio.ProjectRef
+
+	Reason Reason `json:"reason"`
+
+	// Detail is a human readable explanation of the Reason
+	Detail string `json:"detail"`
+
+	// Path lists the projects from a root dependency to the project, it is
+	// only set by CheckGraph
+	Path []librariesio.ProjectRef `json:"path,omitempty"`
+
+	// Err is the error of a failed lookup, it is only set for ReasonError
+	Err error `json:"-"`
+}
+
+// String returns a human readable description of the Problem
+func (p *Problem) String() string {
+	return fmt.Sprintf("%v/%v: %v", p.Platform, p.Name, p.Detail)
+}
+
+// Report holds all problems that were found
+type Report struct {
+	Problems []*Problem `json:"problems"`
+}
+
+// Healthy reports whether no problems were found
+func (r *Report) Healthy() bool {
+	return len(r.Problems) == 0
+}
+
+// Options configure the checks
+type Options struct {
+	// MaxAge is the time since the latest release after which a project is
+	// reported as stale. Zero uses DefaultMaxAge, negative disables the check.
+	MaxAge time.Duration
+
+	// Now returns the current time and defaults to time.Now
+	Now func() time.Time
+}
+
+func (o *Options) maxAge() time.Duration {
+	if o == nil || o.MaxAge == 0 {
+		return DefaultMaxAge
+	}
+	return o.MaxAge
+}
+
+func (o *Options) now() time.Time {
+	if o == nil || o.Now == nil {
+		return time.Now()
+	}
+	return o.Now()
+}
+
+// Check looks up the given projects via Projects.Get and reports those
+// which are deprecated, unmaintained, removed, not found or stale.
+//
+// Other failing lookups are reported as problems with ReasonError, so a
+// single error doesn't hide the results for the remaining projects. An error
+// is only returned if the context is cancelled.
+func Check(ctx context.Context, c librariesio.ProjectsAPI, refs []librariesio.ProjectRef, opt *Options) (*Report, error) {
+	report := &Report{}
+
+	for _, ref := range refs {
+		project, _, err := c.Get(ctx, ref.Platform, ref.Name)
+		if err != nil {
+			if errResp, ok := err.(*librariesio.ErrorResponse); ok && errResp.Response.StatusCode == http.StatusNotFound {
+				report.Problems = append(report.Problems, &Problem{
+					ProjectRef: ref,
+					Reason:     ReasonNotFound,
+					Detail:     "project not found on libraries.io",
+				})
+				continue
+			}
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			report.Problems = append(report.Problems, &Problem{
+				ProjectRef: ref,
+				Reason:     ReasonError,
+				Detail:     fmt.Sprintf("lookup failed: %v", err),
+				Err:        err,
+			})
+			continue
+		}
+
+		report.Problems = append(report.Problems, checkProject(ref, project, opt)...)
+	}
+
+	return report, nil
+}
+
+// CheckGraph reports problems for all fetched projects of the graph, as well
+// as dependencies which are flagged as deprecated by the projects depending
+// on them. Every problem holds the path from a root dependency.
+func CheckGraph(g *depgraph.Graph, opt *Options) *Report {
+	report := &Report{}
+	reported := make(map[*depgraph.Node]map[Reason]bool)
+
+	add := func(node *depgraph.Node, problems ...*Problem) {
+		for _, p := range problems {
+			if reported[node] == nil {
+				reported[node] = make(map[Reason]bool)
+			}
+			if reported[node][p.Reason] {
+				continue
+			}
+			reported[node][p.Reason] = true
+
+			for _, n := range node.Path() {
+				p.Path = append(p.Path, n.ProjectRef)
+			}
+			report.Problems = append(report.Problems, p)
+		}
+	}
+
+	for _, node := range g.Nodes() {
+		if node.Fetched() {
+			add(node, checkProject(node.ProjectRef, node.Project, opt)...)
+		}
+
+		for _, edge := range node.Deps {
+			if edge.Dependency.Deprecated != nil && *edge.Dependency.Deprecated {
+				add(edge.Node, &Problem{
+					ProjectRef: edge.Node.ProjectRef,
+					Reason:     ReasonDeprecated,
+					Detail:     fmt.Sprintf("deprecated, required by %v", node.Name),
+				})
+			}
+		}
+	}
+
+	return report
+}
+
+// checkProject returns the problems of a single project
+func checkProject(ref librariesio.ProjectRef, project *librariesio.Project, opt *Options) []*Problem {
+	var problems []*Problem
+
+	if project.Status != nil {
+		if reason, ok := statusReasons[strings.ToLower(*project.Status)]; ok {
+			problems = append(problems, &Problem{
+				ProjectRef: ref,
+				Reason:     reason,
+				Detail:     fmt.Sprintf("status is %v", *project.Status),
+			})
+		}
+	}
+
+	maxAge := opt.maxAge()
+	if published := project.LatestReleasePublishedAt; maxAge > 0 && published != nil {
+		if age := opt.now().Sub(*published); age > maxAge {
+			problems = append(problems, &Problem{
+				ProjectRef: ref,
+				Reason:     ReasonStale,
+				Detail: fmt.Sprintf(
+					"no release since %v (%d days)",
+					published.Format("2006-01-02"),
+					int(age.Hours()/24),
+				),
+			})
+		}
+	}
+
+	return problems
+}
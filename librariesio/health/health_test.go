@@ -0,0 +1,179 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/hackebrot/go-librariesio/librariesio"
+	"github.com/hackebrot/go-librariesio/librariesio/depgraph"
+	"github.com/hackebrot/go-librariesio/librariesio/librariesiotest"
+	"github.com/hackebrot/go-librariesio/librariesio/manifest"
+)
+
+var now = time.Date(2017, time.June, 1, 0, 0, 0, 0, time.UTC)
+
+func reasons(report *Report) map[string][]Reason {
+	m := make(map[string][]Reason)
+	for _, p := range report.Problems {
+		m[p.Name] = append(m[p.Name], p.Reason)
+	}
+	return m
+}
+
+func TestCheck(t *testing.T) {
+	server, mux, client := librariesiotest.NewMux()
+	defer server.Close()
+
+	mux.HandleFunc("/npm/request", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"name": "request", "status": "Deprecated", "latest_release_published_at": "2014-02-11T00:00:00Z"}`)
+	})
+	mux.HandleFunc("/npm/chalk", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"name": "chalk", "latest_release_published_at": "2017-05-01T00:00:00Z"}`)
+	})
+	mux.HandleFunc("/npm/left-pad", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"name": "left-pad", "status": "Unmaintained"}`)
+	})
+	mux.HandleFunc("/npm/gone", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"error":"Not Found"}`, http.StatusNotFound)
+	})
+
+	refs := []librariesio.ProjectRef{
+		{Platform: "npm", Name: "request"},
+		{Platform: "npm", Name: "chalk"},
+		{Platform: "npm", Name: "left-pad"},
+		{Platform: "npm", Name: "gone"},
+	}
+
+	report, err := Check(context.Background(), client.Projects, refs, &Options{Now: func() time.Time { return now }})
+	if err != nil {
+		t.Fatalf("Check returned unexpected error: %v", err)
+	}
+
+	want := map[string][]Reason{
+		"request":  {ReasonDeprecated, ReasonStale},
+		"left-pad": {ReasonUnmaintained},
+		"gone":     {ReasonNotFound},
+	}
+	if got := reasons(report); !reflect.DeepEqual(got, want) {
+		t.Errorf("\nExpected %v\nGot %v", want, got)
+	}
+
+	if report.Healthy() {
+		t.Error("Expected report not to be healthy")
+	}
+}
+
+func TestCheck_maxAgeDisabled(t *testing.T) {
+	server, mux, client := librariesiotest.NewMux()
+	defer server.Close()
+
+	mux.HandleFunc("/npm/left-pad", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"name": "left-pad", "latest_release_published_at": "2001-01-01T00:00:00Z"}`)
+	})
+
+	refs := []librariesio.ProjectRef{{Platform: "npm", Name: "left-pad"}}
+	report, err := Check(context.Background(), client.Projects, refs, &Options{MaxAge: -1})
+	if err != nil {
+		t.Fatalf("Check returned unexpected error: %v", err)
+	}
+	if !report.Healthy() {
+		t.Errorf("Expected report to be healthy, got %v", report.Problems)
+	}
+}
+
+func TestCheck_error(t *testing.T) {
+	server, mux, client := librariesiotest.NewMux()
+	defer server.Close()
+
+	mux.HandleFunc("/npm/chalk", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"error":"Nope"}`, http.StatusInternalServerError)
+	})
+	mux.HandleFunc("/npm/left-pad", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"name": "left-pad", "status": "Unmaintained"}`)
+	})
+
+	refs := []librariesio.ProjectRef{
+		{Platform: "npm", Name: "chalk"},
+		{Platform: "npm", Name: "left-pad"},
+	}
+	report, err := Check(context.Background(), client.Projects, refs, nil)
+	if err != nil {
+		t.Fatalf("Check returned unexpected error: %v", err)
+	}
+
+	want := map[string][]Reason{
+		"chalk":    {ReasonError},
+		"left-pad": {ReasonUnmaintained},
+	}
+	if got := reasons(report); !reflect.DeepEqual(got, want) {
+		t.Errorf("\nExpected %v\nGot %v", want, got)
+	}
+	if _, ok := report.Problems[0].Err.(*librariesio.ErrorResponse); !ok {
+		t.Errorf("Expected *ErrorResponse, got %v", report.Problems[0].Err)
+	}
+}
+
+func TestCheck_cancelled(t *testing.T) {
+	server, _, client := librariesiotest.NewMux()
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	refs := []librariesio.ProjectRef{{Platform: "npm", Name: "chalk"}}
+	if _, err := Check(ctx, client.Projects, refs, nil); err != context.Canceled {
+		t.Errorf("\nExpected %v\nGot %v", context.Canceled, err)
+	}
+}
+
+func TestCheckGraph(t *testing.T) {
+	server, mux, client := librariesiotest.NewMux()
+	defer server.Close()
+
+	mux.HandleFunc("/npm/ava/latest/dependencies", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{
+			"name": "ava",
+			"latest_release_published_at": "2017-05-01T00:00:00Z",
+			"dependencies": [
+				{"name": "request", "deprecated": true},
+				{"name": "left-pad"}
+			]
+		}`)
+	})
+	mux.HandleFunc("/npm/request/latest/dependencies", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"name": "request", "status": "Deprecated"}`)
+	})
+	mux.HandleFunc("/npm/left-pad/latest/dependencies", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"name": "left-pad", "status": "Removed"}`)
+	})
+
+	roots := []manifest.Dependency{{ProjectRef: librariesio.ProjectRef{Platform: "npm", Name: "ava"}}}
+	g, err := depgraph.Resolve(context.Background(), client.Projects, roots, &depgraph.Options{MaxDepth: 1})
+	if err != nil {
+		t.Fatalf("Resolve returned unexpected error: %v", err)
+	}
+
+	report := CheckGraph(g, &Options{Now: func() time.Time { return now }})
+
+	want := map[string][]Reason{
+		"request":  {ReasonDeprecated},
+		"left-pad": {ReasonRemoved},
+	}
+	if got := reasons(report); !reflect.DeepEqual(got, want) {
+		t.Errorf("\nExpected %v\nGot %v", want, got)
+	}
+
+	wantPath := []librariesio.ProjectRef{
+		{Platform: "npm", Name: "ava"},
+		{Platform: "npm", Name: "left-pad"},
+	}
+	for _, p := range report.Problems {
+		if p.Name == "left-pad" && !reflect.DeepEqual(p.Path, wantPath) {
+			t.Errorf("\nExpected path %v\nGot %v", wantPath, p.Path)
+		}
+	}
+}
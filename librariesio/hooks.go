@@ -0,0 +1,125 @@
+package librariesio
+
+import (
+	"bytes"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// maxAttempts limits how often Client.Do sends a request which hooks ask to
+// retry
+const maxAttempts = 5
+
+// RequestInfo describes an attempt to send a request in Client.Do. It is
+// passed to the hooks of the client.
+type RequestInfo struct {
+	// Request is the request to send. BeforeRequest hooks may modify it,
+	// e.g. to add tracing headers.
+	Request *http.Request
+
+	// URL is a copy of the request URL with the api_key redacted
+	URL *url.URL
+
+	// Endpoint is the path template of the API endpoint relative to the
+	// BaseURL of the client, e.g. ":platform/:name/dependents". It is empty
+	// for URLs which don't match a known endpoint.
+	Endpoint string
+
+	// Platform is the platform of the project for project endpoints
+	Platform string
+
+	// Attempt is the number of the attempt, starting at 1
+	Attempt int
+
+	// Response, StatusCode and Duration are set for AfterResponse hooks.
+	// Response is nil if the request failed with Err.
+	Response   *http.Response
+	StatusCode int
+	Duration   time.Duration
+	Err        error
+
+	// Retry can be set by AfterResponse hooks to send the request again,
+	// e.g. after switching to another API key. The request is sent at most
+	// five times.
+	Retry bool
+
+	// responseBody holds a copy of the response body if it is logged
+	responseBody *bytes.Buffer
+}
+
+// BeforeRequestHook is called before a request is sent. Returning an error
+// aborts the request with the error.
+type BeforeRequestHook func(info *RequestInfo) error
+
+// AfterResponseHook is called after the response for a request was received
+// or sending it failed. The response body must not be read by the hook.
+type AfterResponseHook func(info *RequestInfo)
+
+// AfterDoHook is called when Client.Do returns, with the info of the last
+// attempt and the error returned by Do. Unlike info.Err, err includes error
+// responses of the API and errors decoding the response body.
+type AfterDoHook func(info *RequestInfo, err error)
+
+// BeforeRequest adds a hook which is called by Client.Do before every
+// attempt to send a request. Hooks are called in the order they were added.
+func (c *Client) BeforeRequest(hook BeforeRequestHook) {
+	c.beforeRequest = append(c.beforeRequest, hook)
+}
+
+// AfterResponse adds a hook which is called by Client.Do after every attempt
+// to send a request. Hooks are called in the order they were added.
+func (c *Client) AfterResponse(hook AfterResponseHook) {
+	c.afterResponse = append(c.afterResponse, hook)
+}
+
+// AfterDo adds a hook which is called once when Client.Do returns, after all
+// attempts to send the request. Hooks are called in the order they were
+// added.
+func (c *Client) AfterDo(hook AfterDoHook) {
+	c.afterDo = append(c.afterDo, hook)
+}
+
+// endpoint returns the path template and the platform of the API endpoint
+// for the URL
+func endpoint(base, u *url.URL) (string, string) {
+	path := u.EscapedPath()
+	if base != nil {
+		path = strings.TrimPrefix(path, base.EscapedPath())
+	}
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+
+	switch n := len(segments); {
+	case n == 1 && (segments[0] == "platforms" || segments[0] == "search" || segments[0] == "subscriptions"):
+		return segments[0], ""
+	case segments[0] == "github":
+		switch {
+		case n == 2:
+			return "github/:login", ""
+		case n == 3 && (segments[2] == "projects" || segments[2] == "repositories"):
+			return "github/:login/" + segments[2], ""
+		case n == 3:
+			return "github/:owner/:name", ""
+		case n == 4 && segments[3] == "dependencies":
+			return "github/:owner/:name/dependencies", ""
+		}
+	case segments[0] == "subscriptions" && n == 3:
+		return "subscriptions/:platform/:name", unescape(segments[1])
+	case n == 2:
+		return ":platform/:name", unescape(segments[0])
+	case n == 3 && (segments[2] == "dependents" || segments[2] == "contributors" || segments[2] == "sourcerank"):
+		return ":platform/:name/" + segments[2], unescape(segments[0])
+	case n == 4 && segments[3] == "dependencies":
+		return ":platform/:name/:version/dependencies", unescape(segments[0])
+	}
+	return "", ""
+}
+
+// unescape returns the unescaped path segment
+func unescape(segment string) string {
+	if s, err := url.PathUnescape(segment); err == nil {
+		return s
+	}
+	return segment
+}
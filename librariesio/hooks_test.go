@@ -0,0 +1,247 @@
+package librariesio
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestHooks(t *testing.T) {
+	server, mux, url := startNewServer()
+	client := NewClient(APIKey)
+	client.BaseURL = url
+	defer server.Close()
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("X-Trace-Id"); got != "abc" {
+			t.Errorf("expected tracing header, got %q", got)
+		}
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprintf(w, `{"error":"Not Found"}`)
+	})
+
+	var calls []string
+	client.BeforeRequest(func(info *RequestInfo) error {
+		calls = append(calls, "before")
+		info.Request.Header.Set("X-Trace-Id", "abc")
+		return nil
+	})
+	client.AfterResponse(func(info *RequestInfo) {
+		calls = append(calls, "after")
+		if info.StatusCode != http.StatusNotFound {
+			t.Errorf("expected status %v, got %v", http.StatusNotFound, info.StatusCode)
+		}
+		if info.Attempt != 1 {
+			t.Errorf("expected attempt 1, got %v", info.Attempt)
+		}
+		if strings.Contains(info.URL.String(), APIKey) {
+			t.Errorf("expected redacted URL, got %v", info.URL)
+		}
+		if info.Request.URL.Query().Get("api_key") != APIKey {
+			t.Errorf("expected the request URL to be unchanged, got %v", info.Request.URL)
+		}
+		if info.Duration <= 0 {
+			t.Errorf("expected a duration, got %v", info.Duration)
+		}
+	})
+
+	if _, _, err := client.Projects.Get(context.Background(), "pypi", "cookiecutter"); err == nil {
+		t.Fatal("Expected error to be returned")
+	}
+
+	if want := []string{"before", "after"}; !reflect.DeepEqual(calls, want) {
+		t.Errorf("\nExpected %v\nGot %v", want, calls)
+	}
+}
+
+func TestHooks_retry(t *testing.T) {
+	server, mux, url := startNewServer()
+	client := NewClient(APIKey)
+	client.BaseURL = url
+	defer server.Close()
+
+	requests := 0
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Query().Get("api_key") != "5678" {
+			w.WriteHeader(http.StatusTooManyRequests)
+			fmt.Fprintf(w, `{"error":"Too Many Requests"}`)
+			return
+		}
+		fmt.Fprintf(w, `{"name":"cookiecutter"}`)
+	})
+
+	key := APIKey
+	client.BeforeRequest(func(info *RequestInfo) error {
+		q := info.Request.URL.Query()
+		q.Set("api_key", key)
+		info.Request.URL.RawQuery = q.Encode()
+		return nil
+	})
+	var attempts []int
+	client.AfterResponse(func(info *RequestInfo) {
+		attempts = append(attempts, info.Attempt)
+		if info.StatusCode == http.StatusTooManyRequests {
+			key = "5678"
+			info.Retry = true
+		}
+	})
+
+	project, _, err := client.Projects.Get(context.Background(), "pypi", "cookiecutter")
+	if err != nil {
+		t.Fatalf("Projects.Get returned unexpected error: %v", err)
+	}
+	if *project.Name != "cookiecutter" {
+		t.Errorf("unexpected project %v", *project.Name)
+	}
+	if want := []int{1, 2}; !reflect.DeepEqual(attempts, want) {
+		t.Errorf("\nExpected %v\nGot %v", want, attempts)
+	}
+	if requests != 2 {
+		t.Errorf("expected 2 requests, got %v", requests)
+	}
+}
+
+func TestHooks_requestUnchanged(t *testing.T) {
+	server, mux, url := startNewServer()
+	client := NewClient("")
+	client.BaseURL = url
+	client.SetAuthenticator(NewKeyPool("1234", "5678"))
+	defer server.Close()
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("api_key") != "5678" {
+			w.WriteHeader(http.StatusTooManyRequests)
+			fmt.Fprintf(w, `{"error":"Too Many Requests"}`)
+			return
+		}
+		fmt.Fprintf(w, `{"name":"cookiecutter"}`)
+	})
+	client.BeforeRequest(func(info *RequestInfo) error {
+		info.Request.Header.Set("X-Trace-Id", "abc")
+		return nil
+	})
+
+	req, err := client.NewRequest("GET", "pypi/cookiecutter", nil)
+	if err != nil {
+		t.Fatalf("NewRequest returned unexpected error: %v", err)
+	}
+	wantURL, wantHeader := req.URL.String(), req.Header.Clone()
+
+	if _, err := client.Do(context.Background(), req, nil); err != nil {
+		t.Fatalf("Do returned unexpected error: %v", err)
+	}
+	if got := req.URL.String(); got != wantURL {
+		t.Errorf("\nExpected %v\nGot %v", wantURL, got)
+	}
+	if !reflect.DeepEqual(req.Header, wantHeader) {
+		t.Errorf("\nExpected %v\nGot %v", wantHeader, req.Header)
+	}
+}
+
+func TestHooks_maxAttempts(t *testing.T) {
+	server, mux, url := startNewServer()
+	client := NewClient(APIKey)
+	client.BaseURL = url
+	defer server.Close()
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+
+	attempts := 0
+	client.AfterResponse(func(info *RequestInfo) {
+		attempts = info.Attempt
+		info.Retry = true
+	})
+
+	if _, _, err := client.Projects.Get(context.Background(), "pypi", "cookiecutter"); err == nil {
+		t.Fatal("Expected error to be returned")
+	}
+	if attempts != maxAttempts {
+		t.Errorf("expected %v attempts, got %v", maxAttempts, attempts)
+	}
+}
+
+func TestHooks_beforeRequestError(t *testing.T) {
+	client := NewClient(APIKey)
+	client.BeforeRequest(func(info *RequestInfo) error {
+		return fmt.Errorf("no tracing context")
+	})
+
+	_, _, err := client.Projects.Get(context.Background(), "pypi", "cookiecutter")
+	if err == nil || err.Error() != "no tracing context" {
+		t.Errorf("expected the error of the hook, got %v", err)
+	}
+}
+
+func TestHooks_afterDo(t *testing.T) {
+	server, mux, url := startNewServer()
+	client := NewClient(APIKey)
+	client.BaseURL = url
+	defer server.Close()
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"name":`)
+	})
+
+	calls := 0
+	client.AfterDo(func(info *RequestInfo, err error) {
+		calls++
+		if err == nil {
+			t.Error("expected the decode error")
+		}
+		if info.Err != nil {
+			t.Errorf("expected no transport error, got %v", info.Err)
+		}
+		if info.Endpoint != ":platform/:name" || info.Platform != "pypi" {
+			t.Errorf("unexpected endpoint %q and platform %q", info.Endpoint, info.Platform)
+		}
+	})
+
+	if _, _, err := client.Projects.Get(context.Background(), "pypi", "cookiecutter"); err == nil {
+		t.Fatal("Expected error to be returned")
+	}
+	if calls != 1 {
+		t.Errorf("expected 1 call, got %v", calls)
+	}
+}
+
+func TestEndpoint(t *testing.T) {
+	base, _ := url.Parse("https://libraries.io/api/")
+
+	tests := []struct {
+		path     string
+		endpoint string
+		platform string
+	}{
+		{"platforms", "platforms", ""},
+		{"search", "search", ""},
+		{"subscriptions", "subscriptions", ""},
+		{"subscriptions/npm/chalk", "subscriptions/:platform/:name", "npm"},
+		{"github/hackebrot", "github/:login", ""},
+		{"github/hackebrot/projects", "github/:login/projects", ""},
+		{"github/hackebrot/go-repr", "github/:owner/:name", ""},
+		{"github/hackebrot/go-repr/dependencies", "github/:owner/:name/dependencies", ""},
+		{"pypi/cookiecutter", ":platform/:name", "pypi"},
+		{"go/github.com%2Fhackebrot%2Fgo-repr", ":platform/:name", "go"},
+		{"pypi/cookiecutter/sourcerank", ":platform/:name/sourcerank", "pypi"},
+		{"pypi/cookiecutter/1.5.1/dependencies", ":platform/:name/:version/dependencies", "pypi"},
+		{"pypi/cookiecutter/1.5.1/unknown", "", ""},
+	}
+
+	for _, tt := range tests {
+		u, err := base.Parse(tt.path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		endpoint, platform := endpoint(base, u)
+		if endpoint != tt.endpoint || platform != tt.platform {
+			t.Errorf("%v: expected %q and %q, got %q and %q", tt.path, tt.endpoint, tt.platform, endpoint, platform)
+		}
+	}
+}
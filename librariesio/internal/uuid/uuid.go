@@ -0,0 +1,18 @@
+// Package uuid generates the random UUIDs used to identify SBOM documents
+package uuid
+
+import (
+	"crypto/rand"
+	"fmt"
+)
+
+// New returns a random version 4 UUID
+func New() (string, error) {
+	var u [16]byte
+	if _, err := rand.Read(u[:]); err != nil {
+		return "", err
+	}
+	u[6] = (u[6] & 0x0f) | 0x40
+	u[8] = (u[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", u[0:4], u[4:6], u[6:8], u[8:10], u[10:]), nil
+}
@@ -7,8 +7,11 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
+	"log/slog"
 	"net/http"
 	"net/url"
+	"strconv"
+	"time"
 )
 
 const (
@@ -22,10 +25,33 @@ const (
 // Client for communicating with the libraries.io API
 type Client struct {
 	apiKey    string
-	transport *http.Transport
+	auth      Authenticator
+	transport http.RoundTripper
 	client    *http.Client
 	UserAgent string
 	BaseURL   *url.URL
+
+	beforeRequest []BeforeRequestHook
+	afterResponse []AfterResponseHook
+	afterDo       []AfterDoHook
+
+	logger     *slog.Logger
+	logOptions LogOptions
+
+	// common is shared by the services instead of allocating one per service
+	common service
+
+	// Services for the different parts of the API
+	Projects             *ProjectsService
+	Users                *UsersService
+	Repositories         *RepositoriesService
+	SearchService        *SearchService
+	SubscriptionsService *SubscriptionsService
+}
+
+// service is embedded by the services to share the Client
+type service struct {
+	client *Client
 }
 
 // NewClient returns a new libraries.io API client
@@ -35,18 +61,36 @@ func NewClient(apiKey string) *Client {
 	transport := &http.Transport{}
 	client := &http.Client{Transport: transport}
 
-	return &Client{
+	c := &Client{
 		apiKey:    apiKey,
+		auth:      &QueryAuth{Key: apiKey},
 		client:    client,
 		transport: transport,
 		UserAgent: userAgent,
 		BaseURL:   APIBaseURL,
 	}
+
+	c.common.client = c
+	c.Projects = (*ProjectsService)(&c.common)
+	c.Users = (*UsersService)(&c.common)
+	c.Repositories = (*RepositoriesService)(&c.common)
+	c.SearchService = (*SearchService)(&c.common)
+	c.SubscriptionsService = (*SubscriptionsService)(&c.common)
+
+	return c
+}
+
+// SetTransport replaces the http.RoundTripper used to send requests, e.g. to
+// configure a proxy or to wrap the transport with caching
+func (c *Client) SetTransport(transport http.RoundTripper) {
+	c.transport = transport
+	c.client.Transport = transport
 }
 
 // NewRequest creates a new API request, that can be used for client.Do().
 // It creates an absolute URL from the given URL string and serialize the
-// given payload, set the according headers and add the api_key query param.
+// given payload, set the according headers and authenticate it with the
+// Authenticator of the client, which adds the api_key query param by default.
 func (c *Client) NewRequest(method, urlStr string, data interface{}) (*http.Request, error) {
 	relativeURL, err := url.Parse(urlStr)
 	if err != nil {
@@ -70,10 +114,9 @@ func (c *Client) NewRequest(method, urlStr string, data interface{}) (*http.Requ
 		return nil, err
 	}
 
-	// set api_key for auth
-	q := req.URL.Query()
-	q.Set("api_key", c.apiKey)
-	req.URL.RawQuery = q.Encode()
+	if err := c.authenticate(req); err != nil {
+		return nil, err
+	}
 
 	req.Header.Set("Accept", mediaType)
 	req.Header.Set("User-Agent", c.UserAgent)
@@ -85,6 +128,32 @@ func (c *Client) NewRequest(method, urlStr string, data interface{}) (*http.Requ
 	return req, nil
 }
 
+// ListOptions specifies the pagination for methods returning lists.
+// Zero values are omitted, so the API defaults apply.
+type ListOptions struct {
+	// Page of the results to return, starting at 1
+	Page int
+
+	// PerPage is the number of results per page, libraries.io allows up to 100
+	PerPage int
+}
+
+// addListOptions sets the pagination query params on the given request
+func addListOptions(req *http.Request, opt *ListOptions) {
+	if opt == nil {
+		return
+	}
+
+	q := req.URL.Query()
+	if opt.Page > 0 {
+		q.Set("page", strconv.Itoa(opt.Page))
+	}
+	if opt.PerPage > 0 {
+		q.Set("per_page", strconv.Itoa(opt.PerPage))
+	}
+	req.URL.RawQuery = q.Encode()
+}
+
 // redactAPIKey overwrites the secret api_key query param
 func redactAPIKey(url *url.URL) *url.URL {
 	q := url.Query()
@@ -93,6 +162,17 @@ func redactAPIKey(url *url.URL) *url.URL {
 	return url
 }
 
+// RedactAPIKey returns a copy of the URL with the secret api_key query param
+// overwritten, e.g. for logging or recording requests. It returns nil for a
+// nil URL.
+func RedactAPIKey(u *url.URL) *url.URL {
+	if u == nil {
+		return nil
+	}
+	redacted := *u
+	return redactAPIKey(&redacted)
+}
+
 // ErrorResponse holds information about an unsuccessful API request.
 // The error message from the API response is stored to the Message field.
 type ErrorResponse struct {
@@ -130,29 +210,54 @@ func CheckResponse(resp *http.Response) error {
 // Do sends an HTTP request, that can be cancelled via the given context.
 // It makes sure to redact the API secret key from any URL errors and load
 // the body from the HTTP response into the given obj and return the response.
+//
+// The hooks of the client are called around every attempt to send the
+// request, see BeforeRequest, AfterResponse and AfterDo. Options carried by
+// the context are applied to a copy of the request, see WithRequestOptions.
 func (c *Client) Do(ctx context.Context, req *http.Request, obj interface{}) (*http.Response, error) {
-	req = req.WithContext(ctx)
+	opts := requestOptionsFrom(ctx)
+	if opts.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.timeout)
+		defer cancel()
+	}
 
-	resp, err := c.client.Do(req)
+	// The URL and header are copied, as authenticators, hooks and options
+	// modify them and the request passed to Do must stay unchanged
+	info := &RequestInfo{Request: req.WithContext(ctx)}
+	if req.URL != nil {
+		u := *req.URL
+		info.Request.URL = &u
+	}
+	info.Request.Header = req.Header.Clone()
+	if info.Request.Header == nil {
+		info.Request.Header = make(http.Header)
+	}
+	if req.URL != nil {
+		info.Endpoint, info.Platform = endpoint(c.BaseURL, req.URL)
+	}
+
+	start := time.Now()
+	var resp *http.Response
+	err := c.applyOptions(info, opts)
+	if err == nil {
+		resp, err = c.do(ctx, info, obj)
+	}
+
+	for _, hook := range c.afterDo {
+		hook(info, err)
+	}
+	if c.logger != nil {
+		c.logRequest(ctx, info, time.Since(start), err)
+	}
+	return resp, err
+}
+
+// do sends the request of info and loads the response body into obj
+func (c *Client) do(ctx context.Context, info *RequestInfo, obj interface{}) (*http.Response, error) {
+	resp, err := c.send(ctx, info)
 	if err != nil {
-		select {
-		case <-ctx.Done():
-			// Cancel the HTTP request if the given context is cancelled
-			// This can be because the deadline exceeds or the caller
-			// cancels the context explicitly.
-			c.transport.CancelRequest(req)
-			return nil, ctx.Err()
-		default:
-			// If we have encountered an url.Error make sure
-			// to redact the API secret key from the URL
-			if urlError, ok := err.(*url.Error); ok {
-				if url, err := url.Parse(urlError.URL); err == nil {
-					urlError.URL = redactAPIKey(url).String()
-					return nil, urlError
-				}
-			}
-			return nil, err
-		}
+		return nil, err
 	}
 	defer resp.Body.Close()
 
@@ -176,3 +281,92 @@ func (c *Client) Do(ctx context.Context, req *http.Request, obj interface{}) (*h
 
 	return resp, nil
 }
+
+// send sends the request until no AfterResponse hook asks for a retry. The
+// fields of info are reset for every attempt.
+func (c *Client) send(ctx context.Context, info *RequestInfo) (*http.Response, error) {
+	for attempt := 1; ; attempt++ {
+		req := info.Request
+		if attempt > 1 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req.Body = body
+		}
+		if a := c.authenticator(ctx); attempt > 1 && a != nil {
+			if err := a.Authenticate(req); err != nil {
+				return nil, err
+			}
+		}
+
+		info.URL = RedactAPIKey(req.URL)
+		info.Attempt = attempt
+		info.Response, info.StatusCode, info.Duration, info.Err, info.Retry = nil, 0, 0, nil, false
+		info.responseBody = nil
+
+		for _, hook := range c.beforeRequest {
+			if err := hook(info); err != nil {
+				return nil, err
+			}
+		}
+		req = info.Request
+
+		start := time.Now()
+		resp, err := c.roundTrip(ctx, req)
+		info.Duration = time.Since(start)
+		info.Response, info.Err = resp, err
+		if resp != nil {
+			info.StatusCode = resp.StatusCode
+			if c.dumpBodies(ctx) {
+				info.responseBody = new(bytes.Buffer)
+				resp.Body = teeBody(resp.Body, info.responseBody)
+			}
+		}
+
+		c.authAfterResponse(ctx, info)
+		for _, hook := range c.afterResponse {
+			hook(info)
+		}
+
+		if !info.Retry || attempt >= maxAttempts || ctx.Err() != nil {
+			return resp, err
+		}
+		if c.logger != nil {
+			c.logRetry(ctx, info)
+		}
+		if resp != nil {
+			resp.Body.Close()
+		}
+	}
+}
+
+// roundTrip sends the request once and redacts the API key from errors
+func (c *Client) roundTrip(ctx context.Context, req *http.Request) (*http.Response, error) {
+	resp, err := c.client.Do(req)
+	if err != nil {
+		select {
+		case <-ctx.Done():
+			// Cancel the HTTP request if the given context is cancelled
+			// This can be because the deadline exceeds or the caller
+			// cancels the context explicitly.
+			if canceler, ok := c.transport.(interface {
+				CancelRequest(*http.Request)
+			}); ok {
+				canceler.CancelRequest(req)
+			}
+			return nil, ctx.Err()
+		default:
+			// If we have encountered an url.Error make sure
+			// to redact the API secret key from the URL
+			if urlError, ok := err.(*url.Error); ok {
+				if url, err := url.Parse(urlError.URL); err == nil {
+					urlError.URL = redactAPIKey(url).String()
+					return nil, urlError
+				}
+			}
+			return nil, err
+		}
+	}
+	return resp, nil
+}
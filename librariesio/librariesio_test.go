@@ -166,6 +166,17 @@ func TestRedactAPIKey(t *testing.T) {
 	}
 }
 
+func TestRedactAPIKey_copy(t *testing.T) {
+	u, _ := url.Parse("pypi/poyo?api_key=1234&page=2")
+
+	if got, want := RedactAPIKey(u).String(), "pypi/poyo?api_key=REDACTED&page=2"; got != want {
+		t.Errorf("RedactAPIKey returned %v, want %v", got, want)
+	}
+	if got, want := u.String(), "pypi/poyo?api_key=1234&page=2"; got != want {
+		t.Errorf("RedactAPIKey modified the URL, got %v", got)
+	}
+}
+
 func TestCheckResponse(t *testing.T) {
 	response := &http.Response{
 		Request:    &http.Request{},
@@ -232,6 +243,35 @@ func TestDo(t *testing.T) {
 	}
 }
 
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestDo_setTransport(t *testing.T) {
+	client := NewClient(APIKey)
+
+	var called bool
+	client.SetTransport(roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		called = true
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(`{}`)),
+			Request:    req,
+		}, nil
+	}))
+
+	req, _ := client.NewRequest("GET", "/", nil)
+	if _, err := client.Do(context.Background(), req, nil); err != nil {
+		t.Fatalf("Do returned unexpected error: %v", err)
+	}
+
+	if !called {
+		t.Errorf("expected request to be sent with the custom transport")
+	}
+}
+
 func TestDo_httpClientError(t *testing.T) {
 	server, _, url := startNewServer()
 	client := NewClient(APIKey)
@@ -0,0 +1,59 @@
+package librariesiotest
+
+import (
+	"encoding/json"
+	"io/ioutil"
+
+	"github.com/hackebrot/go-librariesio/librariesio"
+)
+
+// ProjectFixture is a project served by the Server
+type ProjectFixture struct {
+	*librariesio.Project
+
+	// VersionDependencies maps release numbers of the project to the
+	// dependencies of the release
+	VersionDependencies map[string][]*librariesio.ProjectDependency `json:"version_dependencies,omitempty"`
+
+	SourceRank *librariesio.SourceRank `json:"sourcerank,omitempty"`
+
+	Contributors []*librariesio.User `json:"contributors,omitempty"`
+}
+
+// UserFixture is a GitHub user served by the Server
+type UserFixture struct {
+	*librariesio.User
+
+	// Projects refers to projects of the fixtures owned by the user
+	Projects []librariesio.ProjectRef `json:"projects,omitempty"`
+
+	// Repositories are owned by the user. Their dependencies are only
+	// served by the dependencies endpoint of a repository.
+	Repositories []*librariesio.Repository `json:"repositories,omitempty"`
+}
+
+// Fixtures are the data the Server is seeded with
+type Fixtures struct {
+	// APIKeys are the keys accepted by the server. If empty, any non-empty
+	// key is accepted.
+	APIKeys []string `json:"api_keys,omitempty"`
+
+	Platforms     []*librariesio.Platform     `json:"platforms,omitempty"`
+	Projects      []*ProjectFixture           `json:"projects,omitempty"`
+	Users         []*UserFixture              `json:"users,omitempty"`
+	Subscriptions []*librariesio.Subscription `json:"subscriptions,omitempty"`
+}
+
+// LoadFixtures reads fixtures from a JSON file
+func LoadFixtures(path string) (*Fixtures, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	f := &Fixtures{}
+	if err := json.Unmarshal(data, f); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
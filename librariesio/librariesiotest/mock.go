@@ -0,0 +1,183 @@
+package librariesiotest
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/hackebrot/go-librariesio/librariesio"
+)
+
+// ProjectsMock implements librariesio.ProjectsAPI with functions set by a
+// test. Calling a method whose function is nil returns an error.
+//
+//	mock := &librariesiotest.ProjectsMock{
+//		GetFunc: func(ctx context.Context, plat, name string) (*librariesio.Project, *http.Response, error) {
+//			return &librariesio.Project{Name: librariesio.String(name)}, nil, nil
+//		},
+//	}
+type ProjectsMock struct {
+	GetFunc          func(ctx context.Context, plat, name string) (*librariesio.Project, *http.Response, error)
+	GetByPURLFunc    func(ctx context.Context, purl string) (*librariesio.Project, *http.Response, error)
+	DepsFunc         func(ctx context.Context, plat, name, ver string) (*librariesio.Project, *http.Response, error)
+	DependentsFunc   func(ctx context.Context, plat, name string, opt *librariesio.ListOptions) ([]*librariesio.Project, *http.Response, error)
+	ContributorsFunc func(ctx context.Context, plat, name string, opt *librariesio.ListOptions) ([]*librariesio.User, *http.Response, error)
+	SourceRankFunc   func(ctx context.Context, plat, name string) (*librariesio.SourceRank, *http.Response, error)
+}
+
+// UsersMock implements librariesio.UsersAPI with functions set by a test
+type UsersMock struct {
+	GetFunc          func(ctx context.Context, login string) (*librariesio.User, *http.Response, error)
+	ProjectsFunc     func(ctx context.Context, login string, opt *librariesio.ListOptions) ([]*librariesio.Project, *http.Response, error)
+	RepositoriesFunc func(ctx context.Context, login string, opt *librariesio.ListOptions) ([]*librariesio.Repository, *http.Response, error)
+}
+
+// RepositoriesMock implements librariesio.RepositoriesAPI with functions set
+// by a test
+type RepositoriesMock struct {
+	GetFunc  func(ctx context.Context, owner, name string) (*librariesio.Repository, *http.Response, error)
+	DepsFunc func(ctx context.Context, owner, name string) (*librariesio.Repository, *http.Response, error)
+}
+
+// SearchMock implements librariesio.SearchAPI with a function set by a test
+type SearchMock struct {
+	ProjectsFunc func(ctx context.Context, q string, opt *librariesio.SearchOptions) ([]*librariesio.Project, *http.Response, error)
+}
+
+// SubscriptionsMock implements librariesio.SubscriptionsAPI with a function
+// set by a test
+type SubscriptionsMock struct {
+	ListFunc func(ctx context.Context, opt *librariesio.ListOptions) ([]*librariesio.Subscription, *http.Response, error)
+}
+
+// PlatformsMock implements librariesio.PlatformsAPI with a function set by a
+// test
+type PlatformsMock struct {
+	PlatformsFunc func(ctx context.Context) ([]*librariesio.Platform, *http.Response, error)
+}
+
+var (
+	_ librariesio.PlatformsAPI     = (*PlatformsMock)(nil)
+	_ librariesio.ProjectsAPI      = (*ProjectsMock)(nil)
+	_ librariesio.UsersAPI         = (*UsersMock)(nil)
+	_ librariesio.RepositoriesAPI  = (*RepositoriesMock)(nil)
+	_ librariesio.SearchAPI        = (*SearchMock)(nil)
+	_ librariesio.SubscriptionsAPI = (*SubscriptionsMock)(nil)
+)
+
+// notImplemented is returned by methods of mocks without a function
+func notImplemented(mock, method string) error {
+	return fmt.Errorf("librariesiotest: %v.%vFunc is not set", mock, method)
+}
+
+// Platforms calls PlatformsFunc
+func (m *PlatformsMock) Platforms(ctx context.Context) ([]*librariesio.Platform, *http.Response, error) {
+	if m.PlatformsFunc == nil {
+		return nil, nil, notImplemented("PlatformsMock", "Platforms")
+	}
+	return m.PlatformsFunc(ctx)
+}
+
+// Get calls GetFunc
+func (m *ProjectsMock) Get(ctx context.Context, plat, name string) (*librariesio.Project, *http.Response, error) {
+	if m.GetFunc == nil {
+		return nil, nil, notImplemented("ProjectsMock", "Get")
+	}
+	return m.GetFunc(ctx, plat, name)
+}
+
+// GetByPURL calls GetByPURLFunc
+func (m *ProjectsMock) GetByPURL(ctx context.Context, purl string) (*librariesio.Project, *http.Response, error) {
+	if m.GetByPURLFunc == nil {
+		return nil, nil, notImplemented("ProjectsMock", "GetByPURL")
+	}
+	return m.GetByPURLFunc(ctx, purl)
+}
+
+// Deps calls DepsFunc
+func (m *ProjectsMock) Deps(ctx context.Context, plat, name, ver string) (*librariesio.Project, *http.Response, error) {
+	if m.DepsFunc == nil {
+		return nil, nil, notImplemented("ProjectsMock", "Deps")
+	}
+	return m.DepsFunc(ctx, plat, name, ver)
+}
+
+// Dependents calls DependentsFunc
+func (m *ProjectsMock) Dependents(ctx context.Context, plat, name string, opt *librariesio.ListOptions) ([]*librariesio.Project, *http.Response, error) {
+	if m.DependentsFunc == nil {
+		return nil, nil, notImplemented("ProjectsMock", "Dependents")
+	}
+	return m.DependentsFunc(ctx, plat, name, opt)
+}
+
+// Contributors calls ContributorsFunc
+func (m *ProjectsMock) Contributors(ctx context.Context, plat, name string, opt *librariesio.ListOptions) ([]*librariesio.User, *http.Response, error) {
+	if m.ContributorsFunc == nil {
+		return nil, nil, notImplemented("ProjectsMock", "Contributors")
+	}
+	return m.ContributorsFunc(ctx, plat, name, opt)
+}
+
+// SourceRank calls SourceRankFunc
+func (m *ProjectsMock) SourceRank(ctx context.Context, plat, name string) (*librariesio.SourceRank, *http.Response, error) {
+	if m.SourceRankFunc == nil {
+		return nil, nil, notImplemented("ProjectsMock", "SourceRank")
+	}
+	return m.SourceRankFunc(ctx, plat, name)
+}
+
+// Get calls GetFunc
+func (m *UsersMock) Get(ctx context.Context, login string) (*librariesio.User, *http.Response, error) {
+	if m.GetFunc == nil {
+		return nil, nil, notImplemented("UsersMock", "Get")
+	}
+	return m.GetFunc(ctx, login)
+}
+
+// Projects calls ProjectsFunc
+func (m *UsersMock) Projects(ctx context.Context, login string, opt *librariesio.ListOptions) ([]*librariesio.Project, *http.Response, error) {
+	if m.ProjectsFunc == nil {
+		return nil, nil, notImplemented("UsersMock", "Projects")
+	}
+	return m.ProjectsFunc(ctx, login, opt)
+}
+
+// Repositories calls RepositoriesFunc
+func (m *UsersMock) Repositories(ctx context.Context, login string, opt *librariesio.ListOptions) ([]*librariesio.Repository, *http.Response, error) {
+	if m.RepositoriesFunc == nil {
+		return nil, nil, notImplemented("UsersMock", "Repositories")
+	}
+	return m.RepositoriesFunc(ctx, login, opt)
+}
+
+// Get calls GetFunc
+func (m *RepositoriesMock) Get(ctx context.Context, owner, name string) (*librariesio.Repository, *http.Response, error) {
+	if m.GetFunc == nil {
+		return nil, nil, notImplemented("RepositoriesMock", "Get")
+	}
+	return m.GetFunc(ctx, owner, name)
+}
+
+// Deps calls DepsFunc
+func (m *RepositoriesMock) Deps(ctx context.Context, owner, name string) (*librariesio.Repository, *http.Response, error) {
+	if m.DepsFunc == nil {
+		return nil, nil, notImplemented("RepositoriesMock", "Deps")
+	}
+	return m.DepsFunc(ctx, owner, name)
+}
+
+// Projects calls ProjectsFunc
+func (m *SearchMock) Projects(ctx context.Context, q string, opt *librariesio.SearchOptions) ([]*librariesio.Project, *http.Response, error) {
+	if m.ProjectsFunc == nil {
+		return nil, nil, notImplemented("SearchMock", "Projects")
+	}
+	return m.ProjectsFunc(ctx, q, opt)
+}
+
+// List calls ListFunc
+func (m *SubscriptionsMock) List(ctx context.Context, opt *librariesio.ListOptions) ([]*librariesio.Subscription, *http.Response, error) {
+	if m.ListFunc == nil {
+		return nil, nil, notImplemented("SubscriptionsMock", "List")
+	}
+	return m.ListFunc(ctx, opt)
+}
@@ -0,0 +1,104 @@
+package librariesiotest
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/hackebrot/go-librariesio/librariesio"
+	"github.com/hackebrot/go-librariesio/librariesio/manifest"
+)
+
+func TestProjectsMock(t *testing.T) {
+	var calls []string
+	mock := &ProjectsMock{
+		GetFunc: func(ctx context.Context, plat, name string) (*librariesio.Project, *http.Response, error) {
+			calls = append(calls, plat+"/"+name)
+			return &librariesio.Project{Name: librariesio.String(name)}, nil, nil
+		},
+	}
+
+	project, _, err := mock.Get(context.Background(), "npm", "chalk")
+	if err != nil {
+		t.Fatalf("Get returned unexpected error: %v", err)
+	}
+	if *project.Name != "chalk" {
+		t.Errorf("\nExpected %v\nGot %v", "chalk", *project.Name)
+	}
+	if len(calls) != 1 || calls[0] != "npm/chalk" {
+		t.Errorf("unexpected calls %v", calls)
+	}
+}
+
+func TestProjectsMock_notImplemented(t *testing.T) {
+	mock := &ProjectsMock{}
+
+	_, _, err := mock.Dependents(context.Background(), "npm", "chalk", nil)
+	if err == nil {
+		t.Fatal("Expected error to be returned")
+	}
+	if want := "librariesiotest: ProjectsMock.DependentsFunc is not set"; err.Error() != want {
+		t.Errorf("\nExpected %v\nGot %v", want, err)
+	}
+}
+
+func TestProjectsMock_outdated(t *testing.T) {
+	mock := &ProjectsMock{
+		GetFunc: func(ctx context.Context, plat, name string) (*librariesio.Project, *http.Response, error) {
+			return &librariesio.Project{
+				Name:                librariesio.String(name),
+				LatestStableRelease: &librariesio.Release{Number: librariesio.String("2.0.0")},
+			}, nil, nil
+		},
+	}
+
+	deps := []manifest.Dependency{{
+		ProjectRef:  librariesio.ProjectRef{Platform: "npm", Name: "chalk"},
+		Requirement: "1.0.0",
+	}}
+	report, err := manifest.Outdated(context.Background(), mock, deps)
+	if err != nil {
+		t.Fatalf("Outdated returned unexpected error: %v", err)
+	}
+	if outdated := report.Outdated(); len(outdated) != 1 || outdated[0].Drift != manifest.DriftMajor {
+		t.Errorf("unexpected result %+v", outdated)
+	}
+}
+
+func TestSearchMock(t *testing.T) {
+	mock := &SearchMock{
+		ProjectsFunc: func(ctx context.Context, q string, opt *librariesio.SearchOptions) ([]*librariesio.Project, *http.Response, error) {
+			return []*librariesio.Project{{Name: librariesio.String(q)}}, nil, nil
+		},
+	}
+
+	var search librariesio.SearchAPI = mock
+	projects, _, err := search.Projects(context.Background(), "pytest", nil)
+	if err != nil {
+		t.Fatalf("Projects returned unexpected error: %v", err)
+	}
+	if len(projects) != 1 || *projects[0].Name != "pytest" {
+		t.Errorf("unexpected result %v", projects)
+	}
+}
+
+func TestPlatformsMock(t *testing.T) {
+	mock := &PlatformsMock{
+		PlatformsFunc: func(ctx context.Context) ([]*librariesio.Platform, *http.Response, error) {
+			return []*librariesio.Platform{{Name: librariesio.String("Pypi")}}, nil, nil
+		},
+	}
+
+	var api librariesio.PlatformsAPI = mock
+	platforms, _, err := api.Platforms(context.Background())
+	if err != nil {
+		t.Fatalf("Platforms returned unexpected error: %v", err)
+	}
+	if len(platforms) != 1 || *platforms[0].Name != "Pypi" {
+		t.Errorf("unexpected result %v", platforms)
+	}
+
+	if _, _, err := (&PlatformsMock{}).Platforms(context.Background()); err == nil {
+		t.Error("Expected error to be returned")
+	}
+}
@@ -0,0 +1,527 @@
+/*
+Package librariesiotest provides an in-process fake of the libraries.io API
+for testing code which uses a librariesio.Client.
+
+The Server is seeded with Fixtures and implements the endpoints supported by
+the services of the Client, including pagination, search filters, validation
+of the API key sent as api_key query param or librariesio.DefaultAuthHeader, 404
+responses for unknown resources and 429 responses once a rate limit set
+with SetRateLimit is exhausted:
+
+	server := librariesiotest.NewServer(&librariesiotest.Fixtures{
+		Projects: []*librariesiotest.ProjectFixture{
+			{Project: &librariesio.Project{
+				Platform: librariesio.String("Pypi"),
+				Name:     librariesio.String("cookiecutter"),
+			}},
+		},
+	})
+	defer server.Close()
+
+	client := server.NewClient()
+	project, _, err := client.Projects.Get(ctx, "pypi", "cookiecutter")
+
+Code which accepts one of the service interfaces, such as
+librariesio.ProjectsAPI, rather than a *librariesio.Client can also be
+tested with a mock, such as ProjectsMock, which calls functions set by the
+test instead of making HTTP requests.
+*/
+package librariesiotest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/hackebrot/go-librariesio/librariesio"
+)
+
+// APIKey is the key used by Server.NewClient
+const APIKey = "librariesiotest"
+
+const (
+	defaultPerPage = 30
+	maxPerPage     = 100
+)
+
+// Server is a fake libraries.io API. Its fixtures can be changed while it
+// is running, e.g. to publish a new release between two requests.
+type Server struct {
+	*httptest.Server
+
+	mu            sync.Mutex
+	apiKeys       map[string]bool
+	platforms     []*librariesio.Platform
+	projects      []*ProjectFixture
+	users         []*UserFixture
+	subscriptions []*librariesio.Subscription
+
+	// remaining is the number of requests until the rate limit is hit,
+	// negative if there is no limit
+	remaining int
+}
+
+// NewServer starts a Server seeded with the fixtures, which may be nil
+func NewServer(f *Fixtures) *Server {
+	s := &Server{
+		apiKeys:   make(map[string]bool),
+		remaining: -1,
+	}
+
+	if f != nil {
+		for _, key := range f.APIKeys {
+			s.apiKeys[key] = true
+		}
+		if len(s.apiKeys) > 0 {
+			s.apiKeys[APIKey] = true
+		}
+		s.platforms = f.Platforms
+		s.projects = f.Projects
+		s.users = f.Users
+		s.subscriptions = f.Subscriptions
+	}
+
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// NewClient returns a client for the server using APIKey
+func (s *Server) NewClient() *librariesio.Client {
+	c := librariesio.NewClient(APIKey)
+	c.BaseURL, _ = url.Parse(s.URL + "/api/")
+	return c
+}
+
+// NewMux starts a server serving the returned mux and a client for it, for
+// tests which need responses the Server doesn't produce, e.g. server errors:
+//
+//	server, mux, client := librariesiotest.NewMux()
+//	defer server.Close()
+//
+//	mux.HandleFunc("/pypi/cookiecutter", func(w http.ResponseWriter, r *http.Request) {
+//		http.Error(w, `{"error":"Internal Server Error"}`, http.StatusInternalServerError)
+//	})
+func NewMux() (*httptest.Server, *http.ServeMux, *librariesio.Client) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	client := librariesio.NewClient(APIKey)
+	client.BaseURL, _ = url.Parse(server.URL + "/")
+	return server, mux, client
+}
+
+// AddProject adds a project or replaces the project with the same platform
+// and name
+func (s *Server) AddProject(p *ProjectFixture) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, existing := range s.projects {
+		if sameProject(existing.Project, p.Platform, p.Name) {
+			s.projects[i] = p
+			return
+		}
+	}
+	s.projects = append(s.projects, p)
+}
+
+// AddUser adds a user or replaces the user with the same login
+func (s *Server) AddUser(u *UserFixture) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, existing := range s.users {
+		if existing.User != nil && u.User != nil && equalFold(existing.Login, u.Login) {
+			s.users[i] = u
+			return
+		}
+	}
+	s.users = append(s.users, u)
+}
+
+// AddSubscription adds a subscription of the authenticated user
+func (s *Server) AddSubscription(sub *librariesio.Subscription) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.subscriptions = append(s.subscriptions, sub)
+}
+
+// SetRateLimit answers requests with 429 Too Many Requests once the given
+// number of requests has been served. A negative limit removes the limit.
+func (s *Server) SetRateLimit(remaining int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.remaining = remaining
+}
+
+// writeJSON writes v as JSON response
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeError writes an error response as returned by libraries.io
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"error": message})
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if r.Method != "GET" {
+		writeError(w, http.StatusMethodNotAllowed, "Method Not Allowed")
+		return
+	}
+
+	key := r.URL.Query().Get("api_key")
+	if key == "" {
+		key = r.Header.Get(librariesio.DefaultAuthHeader)
+	}
+	if key == "" || (len(s.apiKeys) > 0 && !s.apiKeys[key]) {
+		writeError(w, http.StatusForbidden, "Invalid API key")
+		return
+	}
+
+	if s.remaining == 0 {
+		w.Header().Set("Retry-After", "60")
+		writeError(w, http.StatusTooManyRequests, "Too Many Requests")
+		return
+	}
+	if s.remaining > 0 {
+		s.remaining--
+		w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(s.remaining))
+	}
+
+	segments, ok := pathSegments(r.URL)
+	if !ok || len(segments) == 0 || segments[0] != "api" {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	segments = segments[1:]
+
+	switch {
+	case len(segments) == 1 && segments[0] == "platforms":
+		writeJSON(w, http.StatusOK, nonNil(s.platforms))
+	case len(segments) == 1 && segments[0] == "search":
+		s.serveSearch(w, r)
+	case len(segments) == 1 && segments[0] == "subscriptions":
+		writeJSON(w, http.StatusOK, paginate(r, s.subscriptions))
+	case len(segments) >= 2 && segments[0] == "github":
+		s.serveUser(w, r, segments[1], segments[2:])
+	case len(segments) >= 2:
+		s.serveProject(w, r, segments[0], segments[1], segments[2:])
+	default:
+		writeError(w, http.StatusNotFound, "Not Found")
+	}
+}
+
+// serveProject serves the project endpoints below :platform/:name
+func (s *Server) serveProject(w http.ResponseWriter, r *http.Request, plat, name string, rest []string) {
+	p := s.project(plat, name)
+	if p == nil {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+
+	switch {
+	case len(rest) == 0:
+		project := *p.Project
+		project.Dependencies = nil
+		writeJSON(w, http.StatusOK, &project)
+	case len(rest) == 1 && rest[0] == "dependents":
+		writeJSON(w, http.StatusOK, paginate(r, s.dependents(p)))
+	case len(rest) == 1 && rest[0] == "contributors":
+		writeJSON(w, http.StatusOK, paginate(r, p.Contributors))
+	case len(rest) == 1 && rest[0] == "sourcerank":
+		if p.SourceRank == nil {
+			writeError(w, http.StatusNotFound, "Not Found")
+			return
+		}
+		writeJSON(w, http.StatusOK, p.SourceRank)
+	case len(rest) == 2 && rest[1] == "dependencies":
+		version := rest[0]
+		if version == "latest" {
+			version = latestVersion(p)
+		}
+		deps, ok := p.VersionDependencies[version]
+		if !ok && !hasVersion(p, version) {
+			writeError(w, http.StatusNotFound, "Not Found")
+			return
+		}
+		project := *p.Project
+		project.Dependencies = nonNil(deps).([]*librariesio.ProjectDependency)
+		writeJSON(w, http.StatusOK, &project)
+	default:
+		writeError(w, http.StatusNotFound, "Not Found")
+	}
+}
+
+// serveUser serves the endpoints below github/:login, including the
+// repositories of the user
+func (s *Server) serveUser(w http.ResponseWriter, r *http.Request, login string, rest []string) {
+	var user *UserFixture
+	for _, u := range s.users {
+		if u.User != nil && equalFold(u.Login, &login) {
+			user = u
+		}
+	}
+	if user == nil {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+
+	switch {
+	case len(rest) == 0:
+		writeJSON(w, http.StatusOK, user.User)
+	case len(rest) == 1 && rest[0] == "projects":
+		projects := []*librariesio.Project{}
+		for _, ref := range user.Projects {
+			if p := s.project(ref.Platform, ref.Name); p != nil {
+				projects = append(projects, p.Project)
+			}
+		}
+		writeJSON(w, http.StatusOK, paginate(r, projects))
+	case len(rest) == 1 && rest[0] == "repositories":
+		repos := make([]*librariesio.Repository, len(user.Repositories))
+		for i, repo := range user.Repositories {
+			repos[i] = withoutDependencies(repo)
+		}
+		writeJSON(w, http.StatusOK, paginate(r, repos))
+	case len(rest) == 1 || (len(rest) == 2 && rest[1] == "dependencies"):
+		var repo *librariesio.Repository
+		fullName := login + "/" + rest[0]
+		for _, candidate := range user.Repositories {
+			if equalFold(candidate.FullName, &fullName) || equalFold(candidate.Name, &rest[0]) {
+				repo = candidate
+			}
+		}
+		if repo == nil {
+			writeError(w, http.StatusNotFound, "Not Found")
+			return
+		}
+		if len(rest) == 1 {
+			repo = withoutDependencies(repo)
+		} else if repo.Dependencies == nil {
+			copied := *repo
+			copied.Dependencies = []*librariesio.RepositoryDependency{}
+			repo = &copied
+		}
+		writeJSON(w, http.StatusOK, repo)
+	default:
+		writeError(w, http.StatusNotFound, "Not Found")
+	}
+}
+
+// withoutDependencies returns a copy of the repository without its
+// dependencies
+func withoutDependencies(repo *librariesio.Repository) *librariesio.Repository {
+	copied := *repo
+	copied.Dependencies = nil
+	return &copied
+}
+
+// serveSearch filters and sorts the projects like the search endpoint
+func (s *Server) serveSearch(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	q := strings.ToLower(query.Get("q"))
+
+	filters := map[string]func(p *librariesio.Project) []*string{
+		"platforms": func(p *librariesio.Project) []*string { return []*string{p.Platform} },
+		"languages": func(p *librariesio.Project) []*string { return []*string{p.Language} },
+		"licenses":  func(p *librariesio.Project) []*string { return p.NormalizedLicenses },
+		"keywords":  func(p *librariesio.Project) []*string { return p.Keywords },
+	}
+
+	projects := []*librariesio.Project{}
+	for _, fixture := range s.projects {
+		p := fixture.Project
+		if q != "" && !matches(q, p) {
+			continue
+		}
+
+		ok := true
+		for param, values := range filters {
+			if filter := query.Get(param); filter != "" && !anyEqualFold(strings.Split(filter, ","), values(p)) {
+				ok = false
+			}
+		}
+		if ok {
+			projects = append(projects, p)
+		}
+	}
+
+	switch query.Get("sort") {
+	case "stars":
+		sort.SliceStable(projects, func(i, j int) bool { return intValue(projects[i].Stars) > intValue(projects[j].Stars) })
+	case "rank", "":
+		sort.SliceStable(projects, func(i, j int) bool { return intValue(projects[i].Rank) > intValue(projects[j].Rank) })
+	case "latest_release_published_at":
+		sort.SliceStable(projects, func(i, j int) bool {
+			a, b := projects[i].LatestReleasePublishedAt, projects[j].LatestReleasePublishedAt
+			return a != nil && (b == nil || a.After(*b))
+		})
+	}
+
+	writeJSON(w, http.StatusOK, paginate(r, projects))
+}
+
+// project returns the fixture of the project or nil
+func (s *Server) project(plat, name string) *ProjectFixture {
+	for _, p := range s.projects {
+		if sameProject(p.Project, &plat, &name) {
+			return p
+		}
+	}
+	return nil
+}
+
+// dependents returns the projects with a release depending on p
+func (s *Server) dependents(p *ProjectFixture) []*librariesio.Project {
+	projects := []*librariesio.Project{}
+	for _, other := range s.projects {
+		if other == p {
+			continue
+		}
+	versions:
+		for _, deps := range other.VersionDependencies {
+			for _, dep := range deps {
+				plat := dep.Platform
+				if plat == nil {
+					plat = other.Platform
+				}
+				if sameProject(p.Project, plat, dep.Name) {
+					projects = append(projects, other.Project)
+					break versions
+				}
+			}
+		}
+	}
+	return projects
+}
+
+// latestVersion returns the latest release number of the project
+func latestVersion(p *ProjectFixture) string {
+	if p.LatestReleaseNumber != nil {
+		return *p.LatestReleaseNumber
+	}
+	if n := len(p.Versions); n > 0 && p.Versions[n-1].Number != nil {
+		return *p.Versions[n-1].Number
+	}
+	return ""
+}
+
+// hasVersion reports whether the project has a release with the number
+func hasVersion(p *ProjectFixture, version string) bool {
+	if version != "" && version == latestVersion(p) {
+		return true
+	}
+	for _, v := range p.Versions {
+		if v.Number != nil && *v.Number == version {
+			return true
+		}
+	}
+	return false
+}
+
+// paginate returns the page of the list selected by the page and per_page
+// query params
+func paginate(r *http.Request, list interface{}) interface{} {
+	items := nonNilValue(list)
+
+	query := r.URL.Query()
+	page, err := strconv.Atoi(query.Get("page"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+	perPage, err := strconv.Atoi(query.Get("per_page"))
+	if err != nil || perPage < 1 {
+		perPage = defaultPerPage
+	}
+	if perPage > maxPerPage {
+		perPage = maxPerPage
+	}
+
+	start := (page - 1) * perPage
+	if start > items.Len() {
+		start = items.Len()
+	}
+	end := start + perPage
+	if end > items.Len() {
+		end = items.Len()
+	}
+	return items.Slice(start, end).Interface()
+}
+
+// pathSegments splits the escaped path of the URL, so names containing
+// slashes like Go modules are kept in a single segment
+func pathSegments(u *url.URL) ([]string, bool) {
+	var segments []string
+	for _, segment := range strings.Split(strings.Trim(u.EscapedPath(), "/"), "/") {
+		unescaped, err := url.PathUnescape(segment)
+		if err != nil {
+			return nil, false
+		}
+		segments = append(segments, unescaped)
+	}
+	return segments, true
+}
+
+// sameProject compares the platform case-insensitively and the name exactly
+func sameProject(p *librariesio.Project, plat, name *string) bool {
+	return p != nil && equalFold(p.Platform, plat) && p.Name != nil && name != nil && *p.Name == *name
+}
+
+// matches reports whether the lowercase query is part of the name,
+// description or keywords of the project
+func matches(q string, p *librariesio.Project) bool {
+	fields := append([]*string{p.Name, p.Description}, p.Keywords...)
+	for _, field := range fields {
+		if field != nil && strings.Contains(strings.ToLower(*field), q) {
+			return true
+		}
+	}
+	return false
+}
+
+// nonNil returns an empty slice for a nil slice, so it is encoded as []
+// instead of null
+func nonNil(list interface{}) interface{} {
+	return nonNilValue(list).Interface()
+}
+
+func nonNilValue(list interface{}) reflect.Value {
+	v := reflect.ValueOf(list)
+	if v.IsNil() {
+		return reflect.MakeSlice(v.Type(), 0, 0)
+	}
+	return v
+}
+
+func equalFold(a, b *string) bool {
+	return a != nil && b != nil && strings.EqualFold(*a, *b)
+}
+
+func anyEqualFold(filter []string, values []*string) bool {
+	for _, f := range filter {
+		for _, v := range values {
+			if v != nil && strings.EqualFold(strings.TrimSpace(f), *v) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func intValue(i *int) int {
+	if i == nil {
+		return 0
+	}
+	return *i
+}
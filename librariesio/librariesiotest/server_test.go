@@ -0,0 +1,306 @@
+package librariesiotest
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/hackebrot/go-librariesio/librariesio"
+)
+
+func newTestServer(t *testing.T) *Server {
+	f, err := LoadFixtures("testdata/fixtures.json")
+	if err != nil {
+		t.Fatalf("LoadFixtures returned unexpected error: %v", err)
+	}
+	return NewServer(f)
+}
+
+// names returns the names of the projects
+func names(projects []*librariesio.Project) []string {
+	var n []string
+	for _, p := range projects {
+		n = append(n, *p.Name)
+	}
+	return n
+}
+
+// statusCode returns the status code of an *librariesio.ErrorResponse
+func statusCode(err error) int {
+	if errResp, ok := err.(*librariesio.ErrorResponse); ok {
+		return errResp.Response.StatusCode
+	}
+	return 0
+}
+
+func TestServer_Project(t *testing.T) {
+	server := newTestServer(t)
+	defer server.Close()
+	client := server.NewClient()
+
+	project, _, err := client.Project(context.Background(), "pypi", "cookiecutter")
+	if err != nil {
+		t.Fatalf("Project returned unexpected error: %v", err)
+	}
+	if *project.Name != "cookiecutter" || project.Dependencies != nil {
+		t.Errorf("unexpected project %+v", project)
+	}
+
+	project, _, err = client.Project(context.Background(), "go", "github.com/hackebrot/go-repr")
+	if err != nil {
+		t.Fatalf("Project returned unexpected error for escaped name: %v", err)
+	}
+	if *project.Name != "github.com/hackebrot/go-repr" {
+		t.Errorf("unexpected project %v", *project.Name)
+	}
+
+	if _, _, err := client.Project(context.Background(), "pypi", "missing"); statusCode(err) != http.StatusNotFound {
+		t.Errorf("expected 404 for unknown project, got %v", err)
+	}
+}
+
+func TestServer_ProjectDeps(t *testing.T) {
+	server := newTestServer(t)
+	defer server.Close()
+	client := server.NewClient()
+
+	for _, version := range []string{"1.5.1", "latest"} {
+		project, _, err := client.ProjectDeps(context.Background(), "pypi", "cookiecutter", version)
+		if err != nil {
+			t.Fatalf("ProjectDeps returned unexpected error: %v", err)
+		}
+		if n := len(project.Dependencies); n != 2 {
+			t.Errorf("expected 2 dependencies of %v, got %d", version, n)
+		}
+	}
+
+	project, _, err := client.ProjectDeps(context.Background(), "pypi", "cookiecutter", "1.5.0")
+	if err != nil {
+		t.Fatalf("ProjectDeps returned unexpected error: %v", err)
+	}
+	if len(project.Dependencies) != 0 {
+		t.Errorf("expected no dependencies for 1.5.0, got %v", project.Dependencies)
+	}
+
+	if _, _, err := client.ProjectDeps(context.Background(), "pypi", "cookiecutter", "0.1.0"); statusCode(err) != http.StatusNotFound {
+		t.Errorf("expected 404 for unknown version, got %v", err)
+	}
+}
+
+func TestServer_Dependents(t *testing.T) {
+	server := newTestServer(t)
+	defer server.Close()
+	client := server.NewClient()
+
+	projects, _, err := client.Dependents(context.Background(), "pypi", "click", nil)
+	if err != nil {
+		t.Fatalf("Dependents returned unexpected error: %v", err)
+	}
+	if got, want := names(projects), []string{"cookiecutter"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("\nExpected %v\nGot %v", want, got)
+	}
+}
+
+func TestServer_Search(t *testing.T) {
+	server := newTestServer(t)
+	defer server.Close()
+	client := server.NewClient()
+
+	projects, _, err := client.SearchService.Projects(context.Background(), "templates", &librariesio.SearchOptions{Sort: "stars"})
+	if err != nil {
+		t.Fatalf("Search returned unexpected error: %v", err)
+	}
+	if got, want := names(projects), []string{"cookiecutter", "jinja2"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("\nExpected %v\nGot %v", want, got)
+	}
+
+	projects, _, err = client.SearchService.Projects(context.Background(), "", &librariesio.SearchOptions{
+		Platforms:   "pypi",
+		ListOptions: librariesio.ListOptions{Page: 2, PerPage: 2},
+	})
+	if err != nil {
+		t.Fatalf("Search returned unexpected error: %v", err)
+	}
+	if got, want := names(projects), []string{"cookiecutter"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("\nExpected page 2 sorted by rank %v\nGot %v", want, got)
+	}
+}
+
+func TestServer_users(t *testing.T) {
+	server := newTestServer(t)
+	defer server.Close()
+	client := server.NewClient()
+
+	user, _, err := client.User(context.Background(), "hackebrot")
+	if err != nil {
+		t.Fatalf("User returned unexpected error: %v", err)
+	}
+	if *user.Name != "Raphael Pierzina" {
+		t.Errorf("unexpected user %v", *user.Name)
+	}
+
+	projects, _, err := client.UserProjects(context.Background(), "hackebrot")
+	if err != nil {
+		t.Fatalf("UserProjects returned unexpected error: %v", err)
+	}
+	if got, want := names(projects), []string{"cookiecutter"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("\nExpected %v\nGot %v", want, got)
+	}
+
+	repos, _, err := client.UserRepositories(context.Background(), "hackebrot")
+	if err != nil {
+		t.Fatalf("UserRepositories returned unexpected error: %v", err)
+	}
+	if len(repos) != 1 || *repos[0].FullName != "hackebrot/go-librariesio" {
+		t.Errorf("unexpected repositories %v", repos)
+	}
+
+	if _, _, err := client.User(context.Background(), "nobody"); statusCode(err) != http.StatusNotFound {
+		t.Errorf("expected 404 for unknown user, got %v", err)
+	}
+}
+
+func TestServer_repositories(t *testing.T) {
+	server := newTestServer(t)
+	defer server.Close()
+	client := server.NewClient()
+
+	repo, _, err := client.Repositories.Get(context.Background(), "hackebrot", "go-librariesio")
+	if err != nil {
+		t.Fatalf("Repositories.Get returned unexpected error: %v", err)
+	}
+	if *repo.FullName != "hackebrot/go-librariesio" || repo.Dependencies != nil {
+		t.Errorf("unexpected repository %+v", repo)
+	}
+
+	repo, _, err = client.Repositories.Deps(context.Background(), "hackebrot", "go-librariesio")
+	if err != nil {
+		t.Fatalf("Repositories.Deps returned unexpected error: %v", err)
+	}
+	if len(repo.Dependencies) != 1 || *repo.Dependencies[0].Name != "github.com/hackebrot/go-repr" {
+		t.Errorf("unexpected dependencies %v", repo.Dependencies)
+	}
+
+	if _, _, err := client.Repositories.Get(context.Background(), "hackebrot", "missing"); statusCode(err) != http.StatusNotFound {
+		t.Errorf("expected 404 for unknown repository, got %v", err)
+	}
+}
+
+func TestServer_contributors(t *testing.T) {
+	server := newTestServer(t)
+	defer server.Close()
+	client := server.NewClient()
+
+	users, _, err := client.Projects.Contributors(context.Background(), "pypi", "cookiecutter", &librariesio.ListOptions{PerPage: 1})
+	if err != nil {
+		t.Fatalf("Contributors returned unexpected error: %v", err)
+	}
+	if len(users) != 1 || *users[0].Login != "hackebrot" {
+		t.Errorf("unexpected contributors %v", users)
+	}
+}
+
+func TestServer_platformsAndSubscriptions(t *testing.T) {
+	server := newTestServer(t)
+	defer server.Close()
+	client := server.NewClient()
+
+	platforms, _, err := client.Platforms(context.Background())
+	if err != nil {
+		t.Fatalf("Platforms returned unexpected error: %v", err)
+	}
+	if len(platforms) != 2 {
+		t.Errorf("expected 2 platforms, got %d", len(platforms))
+	}
+
+	server.AddSubscription(&librariesio.Subscription{
+		Project: &librariesio.Project{Platform: librariesio.String("Pypi"), Name: librariesio.String("click")},
+	})
+
+	subscriptions, _, err := client.SubscriptionsService.List(context.Background(), &librariesio.ListOptions{Page: 2, PerPage: 1})
+	if err != nil {
+		t.Fatalf("Subscriptions returned unexpected error: %v", err)
+	}
+	if len(subscriptions) != 1 || *subscriptions[0].Project.Name != "click" {
+		t.Errorf("unexpected subscriptions %v", subscriptions)
+	}
+}
+
+func TestServer_AddProject(t *testing.T) {
+	server := newTestServer(t)
+	defer server.Close()
+	client := server.NewClient()
+
+	server.AddProject(&ProjectFixture{Project: &librariesio.Project{
+		Platform:            librariesio.String("Pypi"),
+		Name:                librariesio.String("click"),
+		LatestReleaseNumber: librariesio.String("7.0"),
+	}})
+
+	project, _, err := client.Project(context.Background(), "pypi", "click")
+	if err != nil {
+		t.Fatalf("Project returned unexpected error: %v", err)
+	}
+	if *project.LatestReleaseNumber != "7.0" {
+		t.Errorf("expected updated project, got %v", *project.LatestReleaseNumber)
+	}
+}
+
+func TestServer_apiKey(t *testing.T) {
+	server := newTestServer(t)
+	defer server.Close()
+
+	client := librariesio.NewClient("wrong")
+	client.BaseURL = server.NewClient().BaseURL
+
+	if _, _, err := client.Platforms(context.Background()); statusCode(err) != http.StatusForbidden {
+		t.Errorf("expected 403 for invalid API key, got %v", err)
+	}
+
+	client = librariesio.NewClient("1234")
+	client.BaseURL = server.NewClient().BaseURL
+
+	if _, _, err := client.Platforms(context.Background()); err != nil {
+		t.Errorf("expected API key from fixtures to be accepted, got %v", err)
+	}
+}
+
+func TestServer_SetRateLimit(t *testing.T) {
+	server := newTestServer(t)
+	defer server.Close()
+	client := server.NewClient()
+
+	server.SetRateLimit(1)
+
+	_, resp, err := client.Platforms(context.Background())
+	if err != nil {
+		t.Fatalf("Platforms returned unexpected error: %v", err)
+	}
+	if remaining := resp.Header.Get("X-RateLimit-Remaining"); remaining != "0" {
+		t.Errorf("expected 0 remaining requests, got %q", remaining)
+	}
+
+	if _, _, err := client.Platforms(context.Background()); statusCode(err) != http.StatusTooManyRequests {
+		t.Errorf("expected 429 after the rate limit, got %v", err)
+	}
+
+	server.SetRateLimit(-1)
+	if _, _, err := client.Platforms(context.Background()); err != nil {
+		t.Errorf("expected no error without rate limit, got %v", err)
+	}
+}
+
+func TestNewMux(t *testing.T) {
+	server, mux, client := NewMux()
+	defer server.Close()
+
+	mux.HandleFunc("/pypi/cookiecutter", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"error":"Internal Server Error"}`, http.StatusInternalServerError)
+	})
+
+	_, _, err := client.Projects.Get(context.Background(), "pypi", "cookiecutter")
+	if got, want := statusCode(err), http.StatusInternalServerError; got != want {
+		t.Errorf("\nExpected %v\nGot %v (%v)", want, got, err)
+	}
+}
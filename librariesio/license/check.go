@@ -0,0 +1,134 @@
+/*
+Package license checks the licenses of dependencies against a Policy.
+
+Licenses are taken from Project.NormalizedLicenses, which holds SPDX license
+identifiers, and can be combined with SPDX expressions using AND, OR and
+WITH. Check evaluates every project of a dependency graph, so violations of
+transitive dependencies are reported along with the path to them.
+*/
+package license
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/hackebrot/go-librariesio/librariesio"
+	"github.com/hackebrot/go-librariesio/librariesio/depgraph"
+)
+
+// PackageResult is the result of checking a single project
+type PackageResult struct {
+	librariesio.ProjectRef
+
+	Version string `json:"version,omitempty"`
+
+	// License is the SPDX expression of the project, empty if unknown
+	License string `json:"license"`
+
+	Allowed bool `json:"allowed"`
+
+	// Reason explains why the project is not allowed or was skipped
+	Reason string `json:"reason,omitempty"`
+
+	// Transitive is set for projects which are not a root of the graph
+	Transitive bool `json:"transitive"`
+
+	// Path lists the projects from a root of the graph to the project
+	Path []librariesio.ProjectRef `json:"path,omitempty"`
+}
+
+// Result holds the outcome of checking a dependency graph against a Policy
+type Result struct {
+	CheckedAt time.Time        `json:"checked_at"`
+	Policy    *Policy          `json:"policy"`
+	Compliant bool             `json:"compliant"`
+	Packages  []*PackageResult `json:"packages"`
+}
+
+// Violations returns the packages which are not allowed by the policy
+func (r *Result) Violations() []*PackageResult {
+	var violations []*PackageResult
+	for _, pkg := range r.Packages {
+		if !pkg.Allowed {
+			violations = append(violations, pkg)
+		}
+	}
+	return violations
+}
+
+// WriteJSON writes the result as indented JSON, e.g. for archiving
+func (r *Result) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+// Check evaluates the licenses of every project in the graph. Projects which
+// were not fetched, e.g. because of depgraph.Options.MaxDepth, have no
+// license information and are only allowed if the policy allows unknown
+// licenses.
+func (p *Policy) Check(g *depgraph.Graph) *Result {
+	result := &Result{
+		CheckedAt: time.Now().UTC(),
+		Policy:    p,
+		Compliant: true,
+	}
+
+	for _, node := range g.Nodes() {
+		pkg := p.checkNode(node)
+		if !pkg.Allowed {
+			result.Compliant = false
+		}
+		result.Packages = append(result.Packages, pkg)
+	}
+
+	return result
+}
+
+// checkNode evaluates a single node of the graph
+func (p *Policy) checkNode(node *depgraph.Node) *PackageResult {
+	pkg := &PackageResult{
+		ProjectRef: node.ProjectRef,
+		Version:    node.ResolvedVersion(),
+		Transitive: node.Depth > 0,
+	}
+	for _, n := range node.Path() {
+		pkg.Path = append(pkg.Path, n.ProjectRef)
+	}
+
+	if p.ignored(node.Platform, node.Name) {
+		pkg.Allowed = true
+		pkg.Reason = "ignored by policy"
+		return pkg
+	}
+
+	var expr Expression
+	var ok bool
+	if node.Project != nil {
+		expr, ok = ProjectExpression(node.Project)
+	}
+
+	if !ok {
+		pkg.Allowed = p.AllowUnknown
+		if !pkg.Allowed {
+			pkg.Reason = "unknown license"
+		}
+		return pkg
+	}
+
+	pkg.License = expr.String()
+
+	allowed, rejected := p.Allowed(expr)
+	pkg.Allowed = allowed
+	if !allowed {
+		names := make([]string, len(rejected))
+		for i, l := range rejected {
+			names[i] = l.String()
+		}
+		pkg.Reason = fmt.Sprintf("license not allowed: %v", strings.Join(names, ", "))
+	}
+	return pkg
+}
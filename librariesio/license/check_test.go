@@ -0,0 +1,109 @@
+package license
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/hackebrot/go-librariesio/librariesio"
+	"github.com/hackebrot/go-librariesio/librariesio/depgraph"
+	"github.com/hackebrot/go-librariesio/librariesio/manifest"
+)
+
+func TestPolicyCheck(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client := librariesio.NewClient("1234")
+	client.BaseURL, _ = url.Parse(server.URL + "/")
+
+	mux.HandleFunc("/cargo/app/latest/dependencies", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{
+			"name": "app",
+			"normalized_licenses": ["MIT", "Apache-2.0"],
+			"dependencies": [{"name": "gpl-lib"}, {"name": "unlicensed"}, {"name": "vendored"}]
+		}`)
+	})
+	mux.HandleFunc("/cargo/gpl-lib/latest/dependencies", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"name": "gpl-lib", "latest_release_number": "1.0.0", "normalized_licenses": ["GPL-3.0"]}`)
+	})
+	mux.HandleFunc("/cargo/unlicensed/latest/dependencies", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"name": "unlicensed"}`)
+	})
+	mux.HandleFunc("/cargo/vendored/latest/dependencies", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"name": "vendored", "normalized_licenses": ["Proprietary"]}`)
+	})
+
+	roots := []manifest.Dependency{{ProjectRef: librariesio.ProjectRef{Platform: "cargo", Name: "app"}}}
+	g, err := depgraph.Resolve(context.Background(), client.Projects, roots, &depgraph.Options{MaxDepth: 1})
+	if err != nil {
+		t.Fatalf("Resolve returned unexpected error: %v", err)
+	}
+
+	policy := &Policy{
+		Allow:  []string{"MIT", "Apache-2.0"},
+		Ignore: []string{"cargo/vendored"},
+	}
+	result := policy.Check(g)
+
+	if result.Compliant {
+		t.Fatal("Expected result not to be compliant")
+	}
+
+	violations := result.Violations()
+	if got, want := len(violations), 2; got != want {
+		t.Fatalf("Expected %d violations, got %d: %+v", want, got, violations)
+	}
+
+	gpl := violations[0]
+	if gpl.Name != "gpl-lib" || !gpl.Transitive || gpl.Version != "1.0.0" || gpl.License != "GPL-3.0" || len(gpl.Path) != 2 {
+		t.Errorf("unexpected violation %+v", gpl)
+	}
+	if unlicensed := violations[1]; unlicensed.Name != "unlicensed" || unlicensed.Reason != "unknown license" {
+		t.Errorf("unexpected violation %+v", unlicensed)
+	}
+
+	var buf bytes.Buffer
+	if err := result.WriteJSON(&buf); err != nil {
+		t.Fatalf("WriteJSON returned unexpected error: %v", err)
+	}
+
+	var decoded struct {
+		Compliant bool `json:"compliant"`
+		Packages  []struct {
+			Platform string `json:"platform"`
+			Name     string `json:"name"`
+			License  string `json:"license"`
+		} `json:"packages"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if decoded.Compliant || len(decoded.Packages) != 4 || decoded.Packages[0].License != "MIT AND Apache-2.0" {
+		t.Errorf("unexpected JSON result %+v", decoded)
+	}
+}
+
+func TestPolicyCheck_severalLicenses(t *testing.T) {
+	node := &depgraph.Node{
+		ProjectRef: librariesio.ProjectRef{Platform: "cargo", Name: "app"},
+		Project: &librariesio.Project{
+			NormalizedLicenses: []*string{librariesio.String("MIT"), librariesio.String("GPL-3.0")},
+		},
+	}
+	policy := &Policy{Allow: []string{"MIT"}}
+
+	pkg := policy.checkNode(node)
+	if pkg.Allowed {
+		t.Errorf("Expected a project with an allowed and a denied license not to be allowed: %+v", pkg)
+	}
+	if got, want := pkg.Reason, "license not allowed: GPL-3.0"; got != want {
+		t.Errorf("\nExpected %v\nGot %v", want, got)
+	}
+}
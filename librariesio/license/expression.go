@@ -0,0 +1,249 @@
+package license
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hackebrot/go-librariesio/librariesio"
+)
+
+// Expression is a parsed SPDX license expression such as
+// "MIT OR (Apache-2.0 AND GPL-2.0-only WITH Classpath-exception-2.0)"
+type Expression interface {
+	// String returns the expression in SPDX syntax
+	String() string
+
+	// licenses calls fn for every license of the expression
+	licenses(fn func(*License))
+}
+
+// License is a single license in an Expression
+type License struct {
+	// ID is the SPDX license identifier or a LicenseRef
+	ID string
+
+	// OrLater is set for identifiers with a trailing "+"
+	OrLater bool
+
+	// Exception is the identifier following WITH, if any
+	Exception string
+}
+
+// String returns the license in SPDX syntax
+func (l *License) String() string {
+	s := l.ID
+	if l.OrLater {
+		s += "+"
+	}
+	if l.Exception != "" {
+		s += " WITH " + l.Exception
+	}
+	return s
+}
+
+func (l *License) licenses(fn func(*License)) {
+	fn(l)
+}
+
+// And requires all of its terms to be satisfied
+type And []Expression
+
+// String returns the expression in SPDX syntax
+func (a And) String() string {
+	return join(a, " AND ")
+}
+
+func (a And) licenses(fn func(*License)) {
+	for _, e := range a {
+		e.licenses(fn)
+	}
+}
+
+// Or requires any of its terms to be satisfied
+type Or []Expression
+
+// String returns the expression in SPDX syntax
+func (o Or) String() string {
+	return join(o, " OR ")
+}
+
+func (o Or) licenses(fn func(*License)) {
+	for _, e := range o {
+		e.licenses(fn)
+	}
+}
+
+// join returns the terms joined by op, putting compound terms in parentheses
+func join(terms []Expression, op string) string {
+	parts := make([]string, len(terms))
+	for i, term := range terms {
+		switch term.(type) {
+		case And, Or:
+			parts[i] = "(" + term.String() + ")"
+		default:
+			parts[i] = term.String()
+		}
+	}
+	return strings.Join(parts, op)
+}
+
+// Licenses returns all licenses of the expression
+func Licenses(e Expression) []*License {
+	var licenses []*License
+	e.licenses(func(l *License) {
+		licenses = append(licenses, l)
+	})
+	return licenses
+}
+
+// ParseExpression parses a SPDX license expression. WITH binds stronger
+// than AND, which binds stronger than OR. Operators are case-insensitive.
+func ParseExpression(s string) (Expression, error) {
+	p := &parser{tokens: tokenize(s)}
+	if len(p.tokens) == 0 {
+		return nil, fmt.Errorf("empty license expression")
+	}
+
+	e, err := p.parseOr()
+	if err != nil {
+		return nil, fmt.Errorf("invalid license expression %q: %v", s, err)
+	}
+	if tok := p.peek(); tok != "" {
+		return nil, fmt.Errorf("invalid license expression %q: unexpected %q", s, tok)
+	}
+	return e, nil
+}
+
+// ProjectExpression returns the Expression for the normalized licenses of the
+// project. libraries.io doesn't tell whether a project listing several
+// licenses is available under any or all of them, so the licenses are
+// combined with AND, which requires a Policy to allow each of them. It
+// returns false if the project has no normalized licenses.
+func ProjectExpression(project *librariesio.Project) (Expression, bool) {
+	var terms And
+	for _, id := range project.NormalizedLicenses {
+		if id == nil || *id == "" {
+			continue
+		}
+		e, err := ParseExpression(*id)
+		if err != nil {
+			e = &License{ID: *id}
+		}
+		terms = append(terms, e)
+	}
+
+	switch len(terms) {
+	case 0:
+		return nil, false
+	case 1:
+		return terms[0], true
+	default:
+		return terms, true
+	}
+}
+
+type parser struct {
+	tokens []string
+	pos    int
+}
+
+func (p *parser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return ""
+}
+
+func (p *parser) next() string {
+	tok := p.peek()
+	p.pos++
+	return tok
+}
+
+func isOperator(tok, op string) bool {
+	return strings.EqualFold(tok, op)
+}
+
+func (p *parser) parseOr() (Expression, error) {
+	var terms Or
+	for {
+		e, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		terms = append(terms, e)
+
+		if !isOperator(p.peek(), "OR") {
+			break
+		}
+		p.next()
+	}
+
+	if len(terms) == 1 {
+		return terms[0], nil
+	}
+	return terms, nil
+}
+
+func (p *parser) parseAnd() (Expression, error) {
+	var terms And
+	for {
+		e, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		terms = append(terms, e)
+
+		if !isOperator(p.peek(), "AND") {
+			break
+		}
+		p.next()
+	}
+
+	if len(terms) == 1 {
+		return terms[0], nil
+	}
+	return terms, nil
+}
+
+func (p *parser) parseTerm() (Expression, error) {
+	tok := p.next()
+
+	switch {
+	case tok == "":
+		return nil, fmt.Errorf("unexpected end of expression")
+	case tok == "(":
+		e, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.next() != ")" {
+			return nil, fmt.Errorf("missing closing parenthesis")
+		}
+		return e, nil
+	case tok == ")" || isOperator(tok, "AND") || isOperator(tok, "OR") || isOperator(tok, "WITH"):
+		return nil, fmt.Errorf("unexpected %q", tok)
+	}
+
+	l := &License{ID: tok}
+	if strings.HasSuffix(tok, "+") {
+		l.ID, l.OrLater = strings.TrimSuffix(tok, "+"), true
+	}
+
+	if isOperator(p.peek(), "WITH") {
+		p.next()
+		exception := p.next()
+		if exception == "" || exception == "(" || exception == ")" {
+			return nil, fmt.Errorf("missing exception after WITH")
+		}
+		l.Exception = exception
+	}
+	return l, nil
+}
+
+// tokenize splits the expression into identifiers, operators and parentheses
+func tokenize(s string) []string {
+	s = strings.Replace(s, "(", " ( ", -1)
+	s = strings.Replace(s, ")", " ) ", -1)
+	return strings.Fields(s)
+}
@@ -0,0 +1,88 @@
+package license
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hackebrot/go-librariesio/librariesio"
+)
+
+func TestParseExpression(t *testing.T) {
+	testCases := []struct {
+		expr string
+		want Expression
+	}{
+		{
+			expr: "MIT",
+			want: &License{ID: "MIT"},
+		},
+		{
+			expr: "LGPL-2.1+",
+			want: &License{ID: "LGPL-2.1", OrLater: true},
+		},
+		{
+			expr: "MIT OR Apache-2.0 AND BSD-3-Clause",
+			want: Or{
+				&License{ID: "MIT"},
+				And{&License{ID: "Apache-2.0"}, &License{ID: "BSD-3-Clause"}},
+			},
+		},
+		{
+			expr: "(MIT or ISC) and GPL-2.0-only WITH Classpath-exception-2.0",
+			want: And{
+				Or{&License{ID: "MIT"}, &License{ID: "ISC"}},
+				&License{ID: "GPL-2.0-only", Exception: "Classpath-exception-2.0"},
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.expr, func(t *testing.T) {
+			got, err := ParseExpression(testCase.expr)
+			if err != nil {
+				t.Fatalf("ParseExpression returned unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, testCase.want) {
+				t.Errorf("\nExpected %v\nGot %v", testCase.want, got)
+			}
+		})
+	}
+}
+
+func TestParseExpression_invalid(t *testing.T) {
+	for _, expr := range []string{"", "MIT AND", "(MIT", "MIT)", "OR MIT", "MIT WITH"} {
+		if _, err := ParseExpression(expr); err == nil {
+			t.Errorf("Expected error for %q", expr)
+		}
+	}
+}
+
+func TestExpressionString(t *testing.T) {
+	e, err := ParseExpression("(MIT OR ISC) AND GPL-2.0+ WITH Classpath-exception-2.0")
+	if err != nil {
+		t.Fatalf("ParseExpression returned unexpected error: %v", err)
+	}
+
+	want := "(MIT OR ISC) AND GPL-2.0+ WITH Classpath-exception-2.0"
+	if got := e.String(); got != want {
+		t.Errorf("String returned %q, want %q", got, want)
+	}
+}
+
+func TestProjectExpression(t *testing.T) {
+	project := &librariesio.Project{
+		NormalizedLicenses: []*string{librariesio.String("MIT"), librariesio.String("Apache-2.0")},
+	}
+
+	e, ok := ProjectExpression(project)
+	if !ok {
+		t.Fatal("Expected expression to be returned")
+	}
+	if got, want := e.String(), "MIT AND Apache-2.0"; got != want {
+		t.Errorf("String returned %q, want %q", got, want)
+	}
+
+	if _, ok := ProjectExpression(&librariesio.Project{}); ok {
+		t.Error("Expected no expression for project without licenses")
+	}
+}
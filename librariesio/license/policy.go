@@ -0,0 +1,122 @@
+package license
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+// Policy defines which licenses are acceptable for dependencies.
+//
+// Entries are matched case-insensitively against the full license, e.g.
+// "GPL-2.0-only WITH Classpath-exception-2.0", and then against the license
+// identifier alone. Full matches take precedence, so a specific exception
+// can be allowed while the license itself is denied. Denied entries take
+// precedence over allowed entries on the same level. If Allow is empty, every
+// license which is not denied is allowed.
+type Policy struct {
+	Allow []string `json:"allow,omitempty" yaml:"allow,omitempty"`
+	Deny  []string `json:"deny,omitempty" yaml:"deny,omitempty"`
+
+	// AllowUnknown accepts projects without license information
+	AllowUnknown bool `json:"allow_unknown,omitempty" yaml:"allow_unknown,omitempty"`
+
+	// Ignore lists projects as "platform/name" which are not checked
+	Ignore []string `json:"ignore,omitempty" yaml:"ignore,omitempty"`
+}
+
+// LoadPolicy reads a Policy from a JSON or YAML file. The format is
+// determined by the file extension. Unknown fields are an error in both
+// formats, so a misspelled entry doesn't silently allow licenses.
+func LoadPolicy(path string) (*Policy, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	policy := new(Policy)
+
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".json":
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		if err = dec.Decode(policy); err == nil && dec.More() {
+			err = fmt.Errorf("unexpected data after the policy")
+		}
+	case ".yaml", ".yml":
+		err = yaml.UnmarshalStrict(data, policy)
+	default:
+		return nil, fmt.Errorf("unsupported policy file extension %q", ext)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("error loading policy %v: %v", path, err)
+	}
+	return policy, nil
+}
+
+// Allowed reports whether the expression satisfies the policy. If it
+// doesn't, the licenses that were rejected are returned.
+func (p *Policy) Allowed(e Expression) (bool, []*License) {
+	switch e := e.(type) {
+	case *License:
+		if p.allowedLicense(e) {
+			return true, nil
+		}
+		return false, []*License{e}
+	case And:
+		var rejected []*License
+		for _, term := range e {
+			if ok, r := p.Allowed(term); !ok {
+				rejected = append(rejected, r...)
+			}
+		}
+		return len(rejected) == 0, rejected
+	case Or:
+		var rejected []*License
+		for _, term := range e {
+			ok, r := p.Allowed(term)
+			if ok {
+				return true, nil
+			}
+			rejected = append(rejected, r...)
+		}
+		return false, rejected
+	}
+	return false, nil
+}
+
+// ignored reports whether the project is exempt from the policy
+func (p *Policy) ignored(plat, name string) bool {
+	return contains(p.Ignore, plat+"/"+name)
+}
+
+// allowedLicense checks a single license against the policy
+func (p *Policy) allowedLicense(l *License) bool {
+	full := l.String()
+
+	switch {
+	case contains(p.Deny, full):
+		return false
+	case contains(p.Allow, full):
+		return true
+	case contains(p.Deny, l.ID):
+		return false
+	}
+	return len(p.Allow) == 0 || contains(p.Allow, l.ID)
+}
+
+// contains reports whether the list contains s, ignoring case
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if strings.EqualFold(strings.TrimSpace(item), s) {
+			return true
+		}
+	}
+	return false
+}
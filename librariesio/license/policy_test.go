@@ -0,0 +1,118 @@
+package license
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLoadPolicy(t *testing.T) {
+	dir, err := ioutil.TempDir("", "license")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	want := &Policy{
+		Allow:        []string{"MIT", "Apache-2.0"},
+		Deny:         []string{"GPL-3.0-only"},
+		AllowUnknown: true,
+	}
+
+	files := map[string]string{
+		"policy.yaml": "allow: [MIT, Apache-2.0]\ndeny:\n  - GPL-3.0-only\nallow_unknown: true\n",
+		"policy.json": `{"allow": ["MIT", "Apache-2.0"], "deny": ["GPL-3.0-only"], "allow_unknown": true}`,
+	}
+
+	for name, content := range files {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(dir, name)
+			if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			policy, err := LoadPolicy(path)
+			if err != nil {
+				t.Fatalf("LoadPolicy returned unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(policy, want) {
+				t.Errorf("\nExpected %+v\nGot %+v", want, policy)
+			}
+		})
+	}
+}
+
+func TestLoadPolicy_unknownField(t *testing.T) {
+	dir, err := ioutil.TempDir("", "license")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	files := map[string]string{
+		"policy.yml":  "alow: [MIT]\n",
+		"policy.json": `{"alow": ["MIT"]}`,
+	}
+
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if _, err := LoadPolicy(path); err == nil {
+			t.Errorf("Expected error to be returned for %v", name)
+		}
+	}
+}
+
+func TestPolicyAllowed(t *testing.T) {
+	policy := &Policy{
+		Allow: []string{"MIT", "Apache-2.0", "GPL-2.0-only WITH Classpath-exception-2.0"},
+		Deny:  []string{"GPL-2.0-only"},
+	}
+
+	testCases := []struct {
+		expr    string
+		allowed bool
+	}{
+		{"MIT", true},
+		{"mit", true},
+		{"ISC", false},
+		{"GPL-2.0-only", false},
+		{"GPL-2.0-only WITH Classpath-exception-2.0", true},
+		{"MIT OR GPL-2.0-only", true},
+		{"MIT AND GPL-2.0-only", false},
+		{"(ISC OR GPL-2.0-only) AND MIT", false},
+	}
+
+	for _, testCase := range testCases {
+		e, err := ParseExpression(testCase.expr)
+		if err != nil {
+			t.Fatalf("ParseExpression returned unexpected error: %v", err)
+		}
+		if got, _ := policy.Allowed(e); got != testCase.allowed {
+			t.Errorf("Allowed(%q) returned %v, want %v", testCase.expr, got, testCase.allowed)
+		}
+	}
+}
+
+func TestPolicyAllowed_denylistOnly(t *testing.T) {
+	policy := &Policy{Deny: []string{"AGPL-3.0-only"}}
+
+	e, _ := ParseExpression("ISC AND AGPL-3.0-only")
+	allowed, rejected := policy.Allowed(e)
+
+	if allowed {
+		t.Fatal("Expected expression not to be allowed")
+	}
+	if want := []*License{{ID: "AGPL-3.0-only"}}; !reflect.DeepEqual(rejected, want) {
+		t.Errorf("\nExpected %v\nGot %v", want, rejected)
+	}
+
+	if allowed, _ := policy.Allowed(&License{ID: "ISC"}); !allowed {
+		t.Error("Expected ISC to be allowed")
+	}
+}
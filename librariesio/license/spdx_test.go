@@ -0,0 +1,43 @@
+package license
+
+import (
+	"testing"
+)
+
+func TestWithRefs(t *testing.T) {
+	testCases := []struct {
+		expr Expression
+		want string
+	}{
+		{
+			expr: &License{ID: "MIT"},
+			want: "MIT",
+		},
+		{
+			expr: &License{ID: "Other"},
+			want: "LicenseRef-Other",
+		},
+		{
+			expr: Or{&License{ID: "MIT"}, &License{ID: "Public Domain"}},
+			want: "MIT OR LicenseRef-Public-Domain",
+		},
+		{
+			expr: And{&License{ID: "LicenseRef-Custom"}, &License{ID: "Apache-2.0"}},
+			want: "LicenseRef-Custom AND Apache-2.0",
+		},
+		{
+			expr: Or{&License{ID: "GPL-2.0", OrLater: true}, And{&License{ID: "Foo"}, &License{ID: "BSD-3-Clause"}}},
+			want: "GPL-2.0+ OR (LicenseRef-Foo AND BSD-3-Clause)",
+		},
+	}
+
+	for _, testCase := range testCases {
+		before := testCase.expr.String()
+		if got := WithRefs(testCase.expr).String(); got != testCase.want {
+			t.Errorf("\nExpected %v\nGot %v", testCase.want, got)
+		}
+		if got := testCase.expr.String(); got != before {
+			t.Errorf("WithRefs modified the expression %q: %q", before, got)
+		}
+	}
+}
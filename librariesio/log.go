@@ -0,0 +1,139 @@
+package librariesio
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+// rateLimitRemainingHeader holds the number of requests left in the current
+// rate limit window
+const rateLimitRemainingHeader = "X-RateLimit-Remaining"
+
+// LogOptions configures the logging of requests, see Client.SetLogger
+type LogOptions struct {
+	// Level of requests which succeeded, slog.LevelDebug if nil
+	Level slog.Leveler
+
+	// ErrorLevel of requests which failed, including API errors and errors
+	// decoding the response, slog.LevelError if nil
+	ErrorLevel slog.Leveler
+
+	// DumpBodies adds the request and response bodies to the records of the
+	// requests if the logger is enabled for slog.LevelDebug. The api_key is
+	// redacted from the bodies.
+	DumpBodies bool
+}
+
+// SetLogger logs every call of Client.Do to the logger with the method,
+// redacted URL, status code, duration and remaining rate limit. A nil
+// logger disables logging.
+func (c *Client) SetLogger(logger *slog.Logger, opt *LogOptions) {
+	if opt == nil {
+		opt = &LogOptions{}
+	}
+	c.logger = logger
+	c.logOptions = *opt
+}
+
+// dumpBodies reports whether bodies are logged for requests with the context
+func (c *Client) dumpBodies(ctx context.Context) bool {
+	return c.logger != nil && c.logOptions.DumpBodies && c.logger.Enabled(ctx, slog.LevelDebug)
+}
+
+// teeBody copies the response body to a buffer while it is read
+func teeBody(body io.ReadCloser, buf *bytes.Buffer) io.ReadCloser {
+	return struct {
+		io.Reader
+		io.Closer
+	}{io.TeeReader(body, buf), body}
+}
+
+// redactBody removes the API keys from a dumped body
+func (c *Client) redactBody(ctx context.Context, body []byte) string {
+	s := string(body)
+	for _, key := range c.secrets(ctx) {
+		if key != "" {
+			s = strings.Replace(s, key, "REDACTED", -1)
+		}
+	}
+	return s
+}
+
+// redactedURL returns the redacted URL of the request as a string
+func redactedURL(info *RequestInfo) string {
+	if info.URL == nil {
+		return ""
+	}
+	return info.URL.String()
+}
+
+// logRequest logs a call of Client.Do
+func (c *Client) logRequest(ctx context.Context, info *RequestInfo, duration time.Duration, err error) {
+	level := slog.LevelDebug
+	if c.logOptions.Level != nil {
+		level = c.logOptions.Level.Level()
+	}
+	if err != nil {
+		level = slog.LevelError
+		if c.logOptions.ErrorLevel != nil {
+			level = c.logOptions.ErrorLevel.Level()
+		}
+	}
+	if !c.logger.Enabled(ctx, level) {
+		return
+	}
+
+	attrs := []slog.Attr{
+		slog.String("method", info.Request.Method),
+		slog.String("url", redactedURL(info)),
+		slog.Duration("duration", duration),
+		slog.Int("attempts", info.Attempt),
+	}
+	if info.StatusCode != 0 {
+		attrs = append(attrs, slog.Int("status", info.StatusCode))
+	}
+	if info.Response != nil {
+		if remaining := info.Response.Header.Get(rateLimitRemainingHeader); remaining != "" {
+			attrs = append(attrs, slog.String("rate_limit_remaining", remaining))
+		}
+	}
+	if err != nil {
+		attrs = append(attrs, slog.String("error", err.Error()))
+	}
+
+	if c.dumpBodies(ctx) {
+		if info.Request.GetBody != nil {
+			if body, err := info.Request.GetBody(); err == nil {
+				data, _ := ioutil.ReadAll(body)
+				body.Close()
+				attrs = append(attrs, slog.String("request_body", c.redactBody(ctx, data)))
+			}
+		}
+		if info.responseBody != nil {
+			attrs = append(attrs, slog.String("response_body", c.redactBody(ctx, info.responseBody.Bytes())))
+		}
+	}
+
+	c.logger.LogAttrs(ctx, level, "libraries.io request", attrs...)
+}
+
+// logRetry logs an attempt which is retried
+func (c *Client) logRetry(ctx context.Context, info *RequestInfo) {
+	attrs := []slog.Attr{
+		slog.String("method", info.Request.Method),
+		slog.String("url", redactedURL(info)),
+		slog.Int("attempt", info.Attempt),
+	}
+	if info.StatusCode != 0 {
+		attrs = append(attrs, slog.Int("status", info.StatusCode))
+	}
+	if info.Err != nil {
+		attrs = append(attrs, slog.String("error", info.Err.Error()))
+	}
+	c.logger.LogAttrs(ctx, slog.LevelDebug, "retrying libraries.io request", attrs...)
+}
@@ -0,0 +1,169 @@
+package librariesio
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// logRecords decodes the records written by a slog.JSONHandler
+func logRecords(t *testing.T, buf *bytes.Buffer) []map[string]interface{} {
+	var records []map[string]interface{}
+	dec := json.NewDecoder(buf)
+	for dec.More() {
+		record := make(map[string]interface{})
+		if err := dec.Decode(&record); err != nil {
+			t.Fatalf("invalid log record: %v", err)
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+func TestSetLogger(t *testing.T) {
+	server, mux, url := startNewServer()
+	client := NewClient(APIKey)
+	client.BaseURL = url
+	defer server.Close()
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Remaining", "59")
+		fmt.Fprintf(w, `{"name":"cookiecutter"}`)
+	})
+
+	buf := new(bytes.Buffer)
+	logger := slog.New(slog.NewJSONHandler(buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	client.SetLogger(logger, nil)
+
+	if _, _, err := client.Projects.Get(context.Background(), "pypi", "cookiecutter"); err != nil {
+		t.Fatalf("Projects.Get returned unexpected error: %v", err)
+	}
+
+	records := logRecords(t, buf)
+	if len(records) != 1 {
+		t.Fatalf("expected 1 record, got %v", records)
+	}
+	record := records[0]
+
+	want := map[string]interface{}{
+		"level":                "DEBUG",
+		"method":               "GET",
+		"status":               float64(200),
+		"attempts":             float64(1),
+		"rate_limit_remaining": "59",
+	}
+	for key, value := range want {
+		if record[key] != value {
+			t.Errorf("expected %v=%v, got %v", key, value, record[key])
+		}
+	}
+	if u, _ := record["url"].(string); !strings.Contains(u, "api_key=REDACTED") {
+		t.Errorf("expected redacted URL, got %v", record["url"])
+	}
+	if _, ok := record["response_body"]; ok {
+		t.Error("expected no response body without DumpBodies")
+	}
+}
+
+func TestSetLogger_errors(t *testing.T) {
+	server, mux, url := startNewServer()
+	client := NewClient(APIKey)
+	client.BaseURL = url
+	defer server.Close()
+
+	mux.HandleFunc("/pypi/missing", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"error":"Not Found"}`, http.StatusNotFound)
+	})
+	mux.HandleFunc("/pypi/broken", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"name":`)
+	})
+
+	buf := new(bytes.Buffer)
+	logger := slog.New(slog.NewJSONHandler(buf, nil))
+	client.SetLogger(logger, &LogOptions{Level: slog.LevelDebug, ErrorLevel: slog.LevelWarn})
+
+	client.Projects.Get(context.Background(), "pypi", "missing")
+	client.Projects.Get(context.Background(), "pypi", "broken")
+
+	records := logRecords(t, buf)
+	if len(records) != 2 {
+		t.Fatalf("expected 2 records, got %v", records)
+	}
+	for _, record := range records {
+		if record["level"] != "WARN" {
+			t.Errorf("expected level WARN, got %v", record["level"])
+		}
+		if record["error"] == nil {
+			t.Errorf("expected an error, got %v", record)
+		}
+	}
+	if errMsg, _ := records[1]["error"].(string); !strings.Contains(errMsg, "unexpected end of JSON input") {
+		t.Errorf("expected the decode error, got %v", records[1]["error"])
+	}
+}
+
+func TestSetLogger_dumpBodies(t *testing.T) {
+	server, mux, url := startNewServer()
+	client := NewClient(APIKey)
+	client.BaseURL = url
+	defer server.Close()
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"name":"cookiecutter","description":"key %v"}`, APIKey)
+	})
+
+	buf := new(bytes.Buffer)
+	logger := slog.New(slog.NewJSONHandler(buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	client.SetLogger(logger, &LogOptions{DumpBodies: true})
+
+	project, _, err := client.Projects.Get(context.Background(), "pypi", "cookiecutter")
+	if err != nil {
+		t.Fatalf("Projects.Get returned unexpected error: %v", err)
+	}
+	if *project.Name != "cookiecutter" {
+		t.Errorf("unexpected project %v", *project.Name)
+	}
+
+	records := logRecords(t, buf)
+	if len(records) != 1 {
+		t.Fatalf("expected 1 record, got %v", records)
+	}
+	want := `{"name":"cookiecutter","description":"key REDACTED"}`
+	if got := records[0]["response_body"]; got != want {
+		t.Errorf("\nExpected %v\nGot %v", want, got)
+	}
+}
+
+func TestSetLogger_dumpBodiesRequestAPIKey(t *testing.T) {
+	server, mux, url := startNewServer()
+	client := NewClient(APIKey)
+	client.BaseURL = url
+	defer server.Close()
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"name":"cookiecutter","description":"key %v"}`, r.URL.Query().Get("api_key"))
+	})
+
+	buf := new(bytes.Buffer)
+	logger := slog.New(slog.NewJSONHandler(buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	client.SetLogger(logger, &LogOptions{DumpBodies: true})
+
+	ctx := WithRequestOptions(context.Background(), WithAPIKey("other-key"))
+	if _, _, err := client.Projects.Get(ctx, "pypi", "cookiecutter"); err != nil {
+		t.Fatalf("Projects.Get returned unexpected error: %v", err)
+	}
+
+	records := logRecords(t, buf)
+	if len(records) != 1 {
+		t.Fatalf("expected 1 record, got %v", records)
+	}
+	want := `{"name":"cookiecutter","description":"key REDACTED"}`
+	if got := records[0]["response_body"]; got != want {
+		t.Errorf("\nExpected %v\nGot %v", want, got)
+	}
+}
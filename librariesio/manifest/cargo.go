@@ -0,0 +1,245 @@
+package manifest
+
+import (
+	"bufio"
+	"io"
+	"strings"
+)
+
+var cargoDependencyTables = []string{
+	"dependencies",
+	"dev-dependencies",
+	"build-dependencies",
+}
+
+// parseCargoToml reads the dependency tables of a Cargo.toml file, including
+// target specific tables such as [target.'cfg(unix)'.dependencies] and
+// tables for a single crate such as [dependencies.serde]. Crates which only
+// refer to a local path are skipped.
+func parseCargoToml(r io.Reader) ([]Dependency, error) {
+	var deps []Dependency
+
+	// Either the dependency table we're in or the single crate table
+	inTable := false
+	var crate map[string]string
+	var crateName string
+
+	flushCrate := func() {
+		if crate != nil {
+			if dep, ok := cargoDependency(crateName, crate); ok {
+				deps = append(deps, dep)
+			}
+		}
+		crate = nil
+	}
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := stripTomlComment(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") {
+			flushCrate()
+			header := strings.Trim(line, "[] ")
+			inTable = isCargoDependencyTable(header)
+			if name, ok := cargoCrateTable(header); ok {
+				crateName = name
+				crate = make(map[string]string)
+			}
+			continue
+		}
+
+		key, value, ok := splitTomlKeyValue(line)
+		if !ok {
+			continue
+		}
+
+		switch {
+		case crate != nil:
+			crate[key] = unquoteToml(value)
+		case inTable:
+			// Dotted keys such as serde.workspace = true
+			name := key
+			if i := strings.Index(key, "."); i >= 0 {
+				name = key[:i]
+			}
+
+			var table map[string]string
+			if strings.HasPrefix(value, "{") {
+				table = parseTomlInlineTable(value)
+			} else if name == key {
+				table = map[string]string{"version": unquoteToml(value)}
+			} else {
+				table = map[string]string{}
+			}
+
+			if dep, ok := cargoDependency(name, table); ok {
+				deps = append(deps, dep)
+			}
+		}
+	}
+	flushCrate()
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return deps, nil
+}
+
+// cargoDependency returns the Dependency for the crate with the given
+// settings, honoring renames via the package key
+func cargoDependency(name string, table map[string]string) (Dependency, bool) {
+	if _, ok := table["path"]; ok {
+		if _, ok := table["version"]; !ok {
+			return Dependency{}, false
+		}
+	}
+	if pkg, ok := table["package"]; ok {
+		name = pkg
+	}
+	return newDependency(PlatformCargo, name, table["version"]), true
+}
+
+// isCargoDependencyTable reports whether the table header is a table of
+// dependencies, e.g. dependencies or target.'cfg(unix)'.dev-dependencies
+func isCargoDependencyTable(header string) bool {
+	for _, table := range cargoDependencyTables {
+		if header == table || strings.HasSuffix(header, "."+table) {
+			return true
+		}
+	}
+	return false
+}
+
+// cargoCrateTable returns the crate name for a table header of a single
+// dependency, e.g. dependencies.serde
+func cargoCrateTable(header string) (string, bool) {
+	for _, table := range cargoDependencyTables {
+		if strings.HasPrefix(header, table+".") {
+			return unquoteToml(header[len(table)+1:]), true
+		}
+		if i := strings.LastIndex(header, "."+table+"."); i >= 0 {
+			return unquoteToml(header[i+len(table)+2:]), true
+		}
+	}
+	return "", false
+}
+
+// parseCargoLock reads the packages of a Cargo.lock file. Packages without
+// a source are part of the local workspace and skipped.
+func parseCargoLock(r io.Reader) ([]Dependency, error) {
+	var deps []Dependency
+	var pkg map[string]string
+
+	flush := func() {
+		if pkg != nil && pkg["source"] != "" {
+			deps = append(deps, newDependency(PlatformCargo, pkg["name"], pkg["version"]))
+		}
+		pkg = nil
+	}
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := stripTomlComment(scanner.Text())
+
+		if strings.HasPrefix(line, "[") {
+			flush()
+			if line == "[[package]]" {
+				pkg = make(map[string]string)
+			}
+			continue
+		}
+
+		if pkg == nil {
+			continue
+		}
+		if key, value, ok := splitTomlKeyValue(line); ok {
+			pkg[key] = unquoteToml(value)
+		}
+	}
+	flush()
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return deps, nil
+}
+
+// stripTomlComment removes a trailing comment and surrounding whitespace
+func stripTomlComment(line string) string {
+	inString := byte(0)
+	for i := 0; i < len(line); i++ {
+		switch c := line[i]; {
+		case inString != 0:
+			if c == inString {
+				inString = 0
+			}
+		case c == '"' || c == '\'':
+			inString = c
+		case c == '#':
+			return strings.TrimSpace(line[:i])
+		}
+	}
+	return strings.TrimSpace(line)
+}
+
+// splitTomlKeyValue splits a "key = value" line
+func splitTomlKeyValue(line string) (string, string, bool) {
+	i := strings.Index(line, "=")
+	if i < 0 {
+		return "", "", false
+	}
+	key := unquoteToml(strings.TrimSpace(line[:i]))
+	return key, strings.TrimSpace(line[i+1:]), true
+}
+
+// parseTomlInlineTable returns the string values of an inline table such as
+// { version = "1.0", features = ["derive"] }. Non-string values are kept as
+// written.
+func parseTomlInlineTable(value string) map[string]string {
+	table := make(map[string]string)
+	value = strings.TrimSpace(value)
+	value = strings.TrimSuffix(strings.TrimPrefix(value, "{"), "}")
+
+	depth := 0
+	inString := byte(0)
+	start := 0
+
+	add := func(entry string) {
+		if key, value, ok := splitTomlKeyValue(strings.TrimSpace(entry)); ok {
+			table[key] = unquoteToml(value)
+		}
+	}
+
+	for i := 0; i < len(value); i++ {
+		switch c := value[i]; {
+		case inString != 0:
+			if c == inString {
+				inString = 0
+			}
+		case c == '"' || c == '\'':
+			inString = c
+		case c == '[' || c == '{':
+			depth++
+		case c == ']' || c == '}':
+			depth--
+		case c == ',' && depth == 0:
+			add(value[start:i])
+			start = i + 1
+		}
+	}
+	add(value[start:])
+
+	return table
+}
+
+// unquoteToml removes the quotes of a basic or literal TOML string
+func unquoteToml(s string) string {
+	s = strings.TrimSpace(s)
+	if len(s) >= 2 && (s[0] == '"' || s[0] == '\'') && s[len(s)-1] == s[0] {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
@@ -0,0 +1,79 @@
+package manifest
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseCargoToml(t *testing.T) {
+	cargo := `[package]
+name = "example"
+version = "0.1.0" # not a dependency
+
+[dependencies]
+serde = { version = "1.0", features = ["derive", "rc"] }
+rand = "0.8.5"
+local = { path = "../local" }
+common.workspace = true
+web = { package = "actix-web", version = "4" }
+
+[dev-dependencies]
+criterion = '0.5'
+
+[target.'cfg(unix)'.dependencies]
+libc = "0.2"
+
+[dependencies.tokio]
+version = "1.28"
+features = ["full"]
+`
+
+	deps, err := Parse("Cargo.toml", strings.NewReader(cargo))
+	if err != nil {
+		t.Fatalf("Parse returned unexpected error: %v", err)
+	}
+
+	assertDependencies(t, deps, []Dependency{
+		dep("cargo", "serde", "1.0"),
+		dep("cargo", "rand", "0.8.5"),
+		dep("cargo", "common", ""),
+		dep("cargo", "actix-web", "4"),
+		dep("cargo", "criterion", "0.5"),
+		dep("cargo", "libc", "0.2"),
+		dep("cargo", "tokio", "1.28"),
+	})
+}
+
+func TestParseCargoLock(t *testing.T) {
+	lock := `# This file is automatically @generated by Cargo.
+version = 3
+
+[[package]]
+name = "example"
+version = "0.1.0"
+dependencies = [
+ "rand",
+]
+
+[[package]]
+name = "rand"
+version = "0.8.5"
+source = "registry+https://github.com/rust-lang/crates.io-index"
+checksum = "abc"
+
+[[package]]
+name = "libc"
+version = "0.2.147"
+source = "registry+https://github.com/rust-lang/crates.io-index"
+`
+
+	deps, err := Parse("Cargo.lock", strings.NewReader(lock))
+	if err != nil {
+		t.Fatalf("Parse returned unexpected error: %v", err)
+	}
+
+	assertDependencies(t, deps, []Dependency{
+		dep("cargo", "rand", "0.8.5"),
+		dep("cargo", "libc", "0.2.147"),
+	})
+}
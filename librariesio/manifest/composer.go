@@ -0,0 +1,30 @@
+package manifest
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+)
+
+// parseComposerJSON reads the require and require-dev sections of a
+// composer.json file. Platform requirements such as php or ext-json are
+// skipped as they are not published on Packagist.
+func parseComposerJSON(r io.Reader) ([]Dependency, error) {
+	var composer struct {
+		Require    map[string]string `json:"require"`
+		RequireDev map[string]string `json:"require-dev"`
+	}
+
+	if err := json.NewDecoder(r).Decode(&composer); err != nil {
+		return nil, err
+	}
+
+	var deps []Dependency
+	for _, dep := range fromMaps(PlatformPackagist, composer.Require, composer.RequireDev) {
+		if !strings.Contains(dep.Name, "/") {
+			continue
+		}
+		deps = append(deps, dep)
+	}
+	return deps, nil
+}
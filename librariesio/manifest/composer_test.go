@@ -0,0 +1,29 @@
+package manifest
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseComposerJSON(t *testing.T) {
+	composer := `{
+		"require": {
+			"php": ">=7.1",
+			"ext-json": "*",
+			"monolog/monolog": "^1.0"
+		},
+		"require-dev": {
+			"phpunit/phpunit": "^9.5"
+		}
+	}`
+
+	deps, err := Parse("composer.json", strings.NewReader(composer))
+	if err != nil {
+		t.Fatalf("Parse returned unexpected error: %v", err)
+	}
+
+	assertDependencies(t, deps, []Dependency{
+		dep("packagist", "monolog/monolog", "^1.0"),
+		dep("packagist", "phpunit/phpunit", "^9.5"),
+	})
+}
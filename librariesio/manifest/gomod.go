@@ -0,0 +1,64 @@
+package manifest
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// parseGoMod reads the require directives of a go.mod file
+func parseGoMod(r io.Reader) ([]Dependency, error) {
+	var deps []Dependency
+
+	scanner := bufio.NewScanner(r)
+	inRequire := false
+	lineNo := 0
+
+	for scanner.Scan() {
+		lineNo++
+		line := scanner.Text()
+		if i := strings.Index(line, "//"); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+
+		if len(fields) == 0 {
+			continue
+		}
+
+		if inRequire {
+			if fields[0] == ")" {
+				inRequire = false
+				continue
+			}
+		} else {
+			if fields[0] != "require" {
+				continue
+			}
+			if len(fields) == 2 && fields[1] == "(" {
+				inRequire = true
+				continue
+			}
+			fields = fields[1:]
+		}
+
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("line %d: malformed require %q", lineNo, scanner.Text())
+		}
+		deps = append(deps, newDependency(PlatformGo, unquote(fields[0]), fields[1]))
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return deps, nil
+}
+
+// unquote removes surrounding double quotes or backticks from s
+func unquote(s string) string {
+	if len(s) >= 2 && (s[0] == '"' || s[0] == '`') && s[len(s)-1] == s[0] {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
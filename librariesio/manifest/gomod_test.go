@@ -0,0 +1,39 @@
+package manifest
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseGoMod(t *testing.T) {
+	gomod := `module github.com/hackebrot/example
+
+go 1.21
+
+require github.com/hackebrot/go-repr v0.1.0
+
+require (
+	golang.org/x/sync v0.5.0 // indirect
+	"gopkg.in/yaml.v2" v2.4.0
+)
+
+replace golang.org/x/sync => ../sync
+`
+	deps, err := Parse("go.mod", strings.NewReader(gomod))
+	if err != nil {
+		t.Fatalf("Parse returned unexpected error: %v", err)
+	}
+
+	assertDependencies(t, deps, []Dependency{
+		dep("go", "github.com/hackebrot/go-repr", "v0.1.0"),
+		dep("go", "golang.org/x/sync", "v0.5.0"),
+		dep("go", "gopkg.in/yaml.v2", "v2.4.0"),
+	})
+}
+
+func TestParseGoMod_malformed(t *testing.T) {
+	_, err := Parse("go.mod", strings.NewReader("require (\n\tgithub.com/foo\n)\n"))
+	if err == nil {
+		t.Fatal("Expected error to be returned")
+	}
+}
@@ -0,0 +1,123 @@
+/*
+Package manifest reads the dependencies declared in package manager manifest
+and lock files, so they can be looked up on libraries.io.
+
+Supported files are go.mod, package.json, package-lock.json,
+requirements.txt, Pipfile.lock, Gemfile.lock, Cargo.toml, Cargo.lock,
+composer.json and pom.xml.
+
+Outdated compares the dependencies of a manifest to the latest stable
+releases on libraries.io, similar to npm outdated.
+*/
+package manifest
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hackebrot/go-librariesio/librariesio"
+)
+
+// Platform names as used by the libraries.io API
+const (
+	PlatformCargo     = "cargo"
+	PlatformGo        = "go"
+	PlatformMaven     = "maven"
+	PlatformNPM       = "npm"
+	PlatformPackagist = "packagist"
+	PlatformPypi      = "pypi"
+	PlatformRubygems  = "rubygems"
+)
+
+// Dependency is a project declared in a manifest file.
+//
+// Requirement holds the version constraint as written in the file, e.g.
+// "^1.2.0" for package.json or "v0.3.1" for go.mod. It is empty if the
+// manifest does not constrain the version.
+type Dependency struct {
+	librariesio.ProjectRef
+	Requirement string `json:"requirement,omitempty"`
+}
+
+type parseFunc func(r io.Reader) ([]Dependency, error)
+
+var parsers = map[string]parseFunc{
+	"Cargo.lock":        parseCargoLock,
+	"Cargo.toml":        parseCargoToml,
+	"Gemfile.lock":      parseGemfileLock,
+	"Pipfile.lock":      parsePipfileLock,
+	"composer.json":     parseComposerJSON,
+	"go.mod":            parseGoMod,
+	"package-lock.json": parsePackageLock,
+	"package.json":      parsePackageJSON,
+	"pom.xml":           parsePom,
+	"requirements.txt":  parseRequirements,
+}
+
+// parserFor returns the parser for the given file name
+func parserFor(filename string) (parseFunc, bool) {
+	base := filepath.Base(filename)
+
+	if p, ok := parsers[base]; ok {
+		return p, true
+	}
+
+	// requirements-dev.txt, requirements_test.txt and friends
+	if strings.HasPrefix(base, "requirements") && strings.HasSuffix(base, ".txt") {
+		return parseRequirements, true
+	}
+	return nil, false
+}
+
+// Supported reports whether the given file name is a known manifest file
+func Supported(filename string) bool {
+	_, ok := parserFor(filename)
+	return ok
+}
+
+// Parse reads the manifest from r and returns its dependencies.
+// The format is determined from the base name of filename.
+func Parse(filename string, r io.Reader) ([]Dependency, error) {
+	parse, ok := parserFor(filename)
+	if !ok {
+		return nil, fmt.Errorf("unsupported manifest file %q", filename)
+	}
+
+	deps, err := parse(r)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing %v: %v", filename, err)
+	}
+	return deps, nil
+}
+
+// ParseFile opens the manifest at the given path and returns its dependencies
+func ParseFile(path string) ([]Dependency, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return Parse(path, f)
+}
+
+// Refs returns the ProjectRef of every dependency, for instance to look them
+// up with Projects.Get.
+func Refs(deps []Dependency) []librariesio.ProjectRef {
+	refs := make([]librariesio.ProjectRef, len(deps))
+	for i, dep := range deps {
+		refs[i] = dep.ProjectRef
+	}
+	return refs
+}
+
+// newDependency returns a Dependency for the given values
+func newDependency(plat, name, requirement string) Dependency {
+	return Dependency{
+		ProjectRef:  librariesio.ProjectRef{Platform: plat, Name: name},
+		Requirement: strings.TrimSpace(requirement),
+	}
+}
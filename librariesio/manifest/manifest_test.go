@@ -0,0 +1,99 @@
+package manifest
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hackebrot/go-librariesio/librariesio"
+)
+
+func dep(plat, name, requirement string) Dependency {
+	return Dependency{
+		ProjectRef:  librariesio.ProjectRef{Platform: plat, Name: name},
+		Requirement: requirement,
+	}
+}
+
+func assertDependencies(t *testing.T, got, want []Dependency) {
+	t.Helper()
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("\nExpected %+v\nGot %+v", want, got)
+	}
+}
+
+func TestSupported(t *testing.T) {
+	testCases := []struct {
+		filename string
+		want     bool
+	}{
+		{"go.mod", true},
+		{"path/to/package.json", true},
+		{"requirements-dev.txt", true},
+		{"Pipfile.lock", true},
+		{"Pipfile", false},
+		{"setup.py", false},
+	}
+
+	for _, testCase := range testCases {
+		if got := Supported(testCase.filename); got != testCase.want {
+			t.Errorf("Supported(%q) returned %v, want %v", testCase.filename, got, testCase.want)
+		}
+	}
+}
+
+func TestParse_unsupported(t *testing.T) {
+	_, err := Parse("setup.py", strings.NewReader(""))
+	if err == nil {
+		t.Fatal("Expected error to be returned")
+	}
+}
+
+func TestParse_badInput(t *testing.T) {
+	_, err := Parse("package.json", strings.NewReader(`{"dependencies": [`))
+	if err == nil {
+		t.Fatal("Expected error to be returned")
+	}
+	if !strings.Contains(err.Error(), "package.json") {
+		t.Errorf("Expected error to mention the file name, got %v", err)
+	}
+}
+
+func TestParseFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "manifest")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "requirements.txt")
+	if err := ioutil.WriteFile(path, []byte("cookiecutter==1.5.1\n"), 0644); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	deps, err := ParseFile(path)
+	if err != nil {
+		t.Fatalf("ParseFile returned unexpected error: %v", err)
+	}
+
+	assertDependencies(t, deps, []Dependency{dep("pypi", "cookiecutter", "==1.5.1")})
+}
+
+func TestRefs(t *testing.T) {
+	deps := []Dependency{
+		dep("npm", "ava", "^0.19.0"),
+		dep("pypi", "poyo", ""),
+	}
+
+	want := []librariesio.ProjectRef{
+		{Platform: "npm", Name: "ava"},
+		{Platform: "pypi", Name: "poyo"},
+	}
+
+	if got := Refs(deps); !reflect.DeepEqual(got, want) {
+		t.Errorf("\nExpected %+v\nGot %+v", want, got)
+	}
+}
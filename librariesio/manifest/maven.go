@@ -0,0 +1,85 @@
+package manifest
+
+import (
+	"encoding/xml"
+	"io"
+	"regexp"
+)
+
+var mavenProperty = regexp.MustCompile(`\$\{([^}]+)\}`)
+
+// parsePom reads the dependencies of a Maven pom.xml file. The project name
+// on libraries.io is "groupId:artifactId". Property references such as
+// ${junit.version} are resolved from the properties of the pom.
+func parsePom(r io.Reader) ([]Dependency, error) {
+	type dependency struct {
+		GroupID    string `xml:"groupId"`
+		ArtifactID string `xml:"artifactId"`
+		Version    string `xml:"version"`
+	}
+
+	type property struct {
+		XMLName xml.Name
+		Value   string `xml:",chardata"`
+	}
+
+	var pom struct {
+		GroupID string `xml:"groupId"`
+		Version string `xml:"version"`
+		Parent  struct {
+			GroupID string `xml:"groupId"`
+			Version string `xml:"version"`
+		} `xml:"parent"`
+		Properties struct {
+			Properties []property `xml:",any"`
+		} `xml:"properties"`
+		Dependencies         []dependency `xml:"dependencies>dependency"`
+		DependencyManagement []dependency `xml:"dependencyManagement>dependencies>dependency"`
+	}
+
+	if err := xml.NewDecoder(r).Decode(&pom); err != nil {
+		return nil, err
+	}
+
+	props := map[string]string{
+		"project.groupId":        pom.GroupID,
+		"project.version":        pom.Version,
+		"project.parent.groupId": pom.Parent.GroupID,
+		"project.parent.version": pom.Parent.Version,
+	}
+	if pom.GroupID == "" {
+		props["project.groupId"] = pom.Parent.GroupID
+	}
+	if pom.Version == "" {
+		props["project.version"] = pom.Parent.Version
+	}
+	for _, p := range pom.Properties.Properties {
+		props[p.XMLName.Local] = p.Value
+	}
+
+	expand := func(s string) string {
+		return mavenProperty.ReplaceAllStringFunc(s, func(ref string) string {
+			if v, ok := props[ref[2:len(ref)-1]]; ok {
+				return v
+			}
+			return ref
+		})
+	}
+
+	// Versions may be managed in dependencyManagement
+	managed := make(map[string]string)
+	for _, dep := range pom.DependencyManagement {
+		managed[expand(dep.GroupID)+":"+expand(dep.ArtifactID)] = expand(dep.Version)
+	}
+
+	var deps []Dependency
+	for _, dep := range pom.Dependencies {
+		name := expand(dep.GroupID) + ":" + expand(dep.ArtifactID)
+		version := expand(dep.Version)
+		if version == "" {
+			version = managed[name]
+		}
+		deps = append(deps, newDependency(PlatformMaven, name, version))
+	}
+	return deps, nil
+}
@@ -0,0 +1,125 @@
+package manifest
+
+import (
+	"encoding/json"
+	"io"
+	"sort"
+	"strings"
+)
+
+// parsePackageJSON reads the dependencies of a npm package.json file
+func parsePackageJSON(r io.Reader) ([]Dependency, error) {
+	var pkg struct {
+		Dependencies         map[string]string `json:"dependencies"`
+		DevDependencies      map[string]string `json:"devDependencies"`
+		OptionalDependencies map[string]string `json:"optionalDependencies"`
+	}
+
+	if err := json.NewDecoder(r).Decode(&pkg); err != nil {
+		return nil, err
+	}
+
+	return fromMaps(
+		PlatformNPM,
+		pkg.Dependencies,
+		pkg.DevDependencies,
+		pkg.OptionalDependencies,
+	), nil
+}
+
+// parsePackageLock reads the installed packages of a npm package-lock.json
+// file. Lockfile version 1 lists them under "dependencies", later versions
+// under "packages" keyed by their node_modules path.
+func parsePackageLock(r io.Reader) ([]Dependency, error) {
+	type lockDependency struct {
+		Version      string                     `json:"version"`
+		Link         bool                       `json:"link"`
+		Dependencies map[string]json.RawMessage `json:"dependencies"`
+	}
+
+	var lock struct {
+		Packages     map[string]lockDependency  `json:"packages"`
+		Dependencies map[string]json.RawMessage `json:"dependencies"`
+	}
+
+	if err := json.NewDecoder(r).Decode(&lock); err != nil {
+		return nil, err
+	}
+
+	versions := make(map[string]string)
+
+	if len(lock.Packages) > 0 {
+		for path, pkg := range lock.Packages {
+			i := strings.LastIndex(path, "node_modules/")
+			if i < 0 || pkg.Link {
+				// The root package or a workspace member
+				continue
+			}
+			versions[path[i+len("node_modules/"):]+"@"+pkg.Version] = pkg.Version
+		}
+		return fromVersions(PlatformNPM, versions), nil
+	}
+
+	// lockfile version 1 nests dependencies of dependencies
+	var walk func(deps map[string]json.RawMessage) error
+	walk = func(deps map[string]json.RawMessage) error {
+		for name, raw := range deps {
+			var dep lockDependency
+			if err := json.Unmarshal(raw, &dep); err != nil {
+				return err
+			}
+			versions[name+"@"+dep.Version] = dep.Version
+			if err := walk(dep.Dependencies); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if err := walk(lock.Dependencies); err != nil {
+		return nil, err
+	}
+	return fromVersions(PlatformNPM, versions), nil
+}
+
+// fromMaps returns the dependencies for maps of names to requirements.
+// A name is only reported once, with the requirement of the first map.
+func fromMaps(plat string, maps ...map[string]string) []Dependency {
+	var deps []Dependency
+	seen := make(map[string]bool)
+
+	for _, m := range maps {
+		names := make([]string, 0, len(m))
+		for name := range m {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			if seen[name] {
+				continue
+			}
+			seen[name] = true
+			deps = append(deps, newDependency(plat, name, m[name]))
+		}
+	}
+	return deps
+}
+
+// fromVersions returns the dependencies for a map of "name@version" keys to
+// versions, sorted by name and version.
+func fromVersions(plat string, versions map[string]string) []Dependency {
+	keys := make([]string, 0, len(versions))
+	for key := range versions {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	deps := make([]Dependency, 0, len(keys))
+	for _, key := range keys {
+		version := versions[key]
+		name := strings.TrimSuffix(key, "@"+version)
+		deps = append(deps, newDependency(plat, name, version))
+	}
+	return deps
+}
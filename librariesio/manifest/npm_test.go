@@ -0,0 +1,75 @@
+package manifest
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParsePackageJSON(t *testing.T) {
+	pkg := `{
+		"name": "example",
+		"dependencies": {"chalk": "^1.1.3", "@babel/core": "7.0.0"},
+		"devDependencies": {"ava": "~0.19.0", "chalk": "^2.0.0"}
+	}`
+
+	deps, err := Parse("package.json", strings.NewReader(pkg))
+	if err != nil {
+		t.Fatalf("Parse returned unexpected error: %v", err)
+	}
+
+	assertDependencies(t, deps, []Dependency{
+		dep("npm", "@babel/core", "7.0.0"),
+		dep("npm", "chalk", "^1.1.3"),
+		dep("npm", "ava", "~0.19.0"),
+	})
+}
+
+func TestParsePackageLock(t *testing.T) {
+	testCases := []struct {
+		name string
+		lock string
+	}{
+		{
+			name: "lockfileVersion 1",
+			lock: `{
+				"lockfileVersion": 1,
+				"dependencies": {
+					"chalk": {
+						"version": "1.1.3",
+						"dependencies": {"ansi-styles": {"version": "2.2.1"}}
+					},
+					"ansi-styles": {"version": "3.2.1"}
+				}
+			}`,
+		},
+		{
+			name: "lockfileVersion 2",
+			lock: `{
+				"lockfileVersion": 2,
+				"packages": {
+					"": {"name": "example", "version": "1.0.0"},
+					"node_modules/chalk": {"version": "1.1.3"},
+					"node_modules/chalk/node_modules/ansi-styles": {"version": "2.2.1"},
+					"node_modules/ansi-styles": {"version": "3.2.1"},
+					"node_modules/workspace": {"link": true}
+				}
+			}`,
+		},
+	}
+
+	want := []Dependency{
+		dep("npm", "ansi-styles", "2.2.1"),
+		dep("npm", "ansi-styles", "3.2.1"),
+		dep("npm", "chalk", "1.1.3"),
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			deps, err := Parse("package-lock.json", strings.NewReader(testCase.lock))
+			if err != nil {
+				t.Fatalf("Parse returned unexpected error: %v", err)
+			}
+			assertDependencies(t, deps, want)
+		})
+	}
+}
@@ -0,0 +1,166 @@
+package manifest
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hackebrot/go-librariesio/librariesio"
+)
+
+// Drift classifies how far a dependency is behind its latest stable release
+type Drift int
+
+// Drift values, ordered by severity
+const (
+	DriftNone Drift = iota
+	DriftPatch
+	DriftMinor
+	DriftMajor
+	DriftUnknown
+)
+
+var driftNames = map[Drift]string{
+	DriftNone:    "none",
+	DriftPatch:   "patch",
+	DriftMinor:   "minor",
+	DriftMajor:   "major",
+	DriftUnknown: "unknown",
+}
+
+// String returns the name of the Drift
+func (d Drift) String() string {
+	return driftNames[d]
+}
+
+// MarshalText encodes the Drift as its name, e.g. for JSON reports
+func (d Drift) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalText decodes the name of a Drift, e.g. for command line flags
+func (d *Drift) UnmarshalText(text []byte) error {
+	for drift, name := range driftNames {
+		if name == string(text) {
+			*d = drift
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown drift %q", text)
+}
+
+// OutdatedDependency is the result of comparing a single dependency with the
+// latest stable release of the project on libraries.io.
+type OutdatedDependency struct {
+	Dependency
+
+	// Current is the version derived from the requirement, see
+	// Dependency.Version
+	Current string `json:"current"`
+
+	// LatestStable is the number of the latest stable release
+	LatestStable string `json:"latest_stable"`
+
+	Drift Drift `json:"drift"`
+
+	// Project is nil if the lookup failed, see Err
+	Project *librariesio.Project `json:"-"`
+	Err     error                `json:"-"`
+}
+
+// OutdatedReport holds the results for all dependencies of a manifest
+type OutdatedReport struct {
+	Dependencies []*OutdatedDependency `json:"dependencies"`
+}
+
+// Outdated returns the dependencies which are behind their latest stable
+// release, i.e. have a Drift other than DriftNone and DriftUnknown.
+func (r *OutdatedReport) Outdated() []*OutdatedDependency {
+	var outdated []*OutdatedDependency
+	for _, dep := range r.Dependencies {
+		if dep.Drift != DriftNone && dep.Drift != DriftUnknown {
+			outdated = append(outdated, dep)
+		}
+	}
+	return outdated
+}
+
+// Outdated looks up every dependency via Projects.Get and compares the
+// version in the manifest to the latest stable release of the project.
+//
+// Failing lookups, e.g. for projects not published on libraries.io, are
+// reported via the Err field of the dependency and result in DriftUnknown.
+// An error is only returned if the context is cancelled.
+func Outdated(ctx context.Context, c librariesio.ProjectsAPI, deps []Dependency) (*OutdatedReport, error) {
+	report := &OutdatedReport{}
+
+	for _, dep := range deps {
+		project, _, err := c.Get(ctx, dep.Platform, dep.Name)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			result := Compare(dep, nil)
+			result.Err = err
+			report.Dependencies = append(report.Dependencies, result)
+			continue
+		}
+
+		report.Dependencies = append(report.Dependencies, Compare(dep, project))
+	}
+
+	return report, nil
+}
+
+// Compare compares the version in the manifest to the latest stable release
+// of a project which was already looked up, e.g. when resolving a dependency
+// graph. The Drift is DriftUnknown if project is nil.
+func Compare(dep Dependency, project *librariesio.Project) *OutdatedDependency {
+	result := &OutdatedDependency{
+		Dependency: dep,
+		Current:    dep.Version(),
+		Drift:      DriftUnknown,
+		Project:    project,
+	}
+
+	if project != nil {
+		result.LatestStable = latestStable(project)
+		result.Drift = drift(result.Current, result.LatestStable)
+	}
+
+	return result
+}
+
+// latestStable returns the number of the latest stable release of the
+// project and falls back to the latest release number
+func latestStable(project *librariesio.Project) string {
+	if r := project.LatestStableRelease; r != nil && r.Number != nil {
+		return *r.Number
+	}
+	if project.LatestReleaseNumber != nil {
+		return *project.LatestReleaseNumber
+	}
+	return ""
+}
+
+// drift classifies the difference between the current and latest version
+func drift(current, latest string) Drift {
+	c, ok := parseVersion(current)
+	if !ok {
+		return DriftUnknown
+	}
+	l, ok := parseVersion(latest)
+	if !ok {
+		return DriftUnknown
+	}
+
+	switch {
+	case c.compare(l) >= 0:
+		return DriftNone
+	case c.major != l.major:
+		return DriftMajor
+	case c.minor != l.minor:
+		return DriftMinor
+	default:
+		return DriftPatch
+	}
+}
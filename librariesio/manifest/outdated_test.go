@@ -0,0 +1,136 @@
+package manifest
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/hackebrot/go-librariesio/librariesio"
+)
+
+func TestDrift(t *testing.T) {
+	testCases := []struct {
+		current, latest string
+		want            Drift
+	}{
+		{"1.2.3", "1.2.3", DriftNone},
+		{"1.3.0", "1.2.3", DriftNone},
+		{"1.2.3", "1.2.4", DriftPatch},
+		{"1.2.3", "1.4.0", DriftMinor},
+		{"v1.2.3", "v2.0.0", DriftMajor},
+		{"", "1.0.0", DriftUnknown},
+		{"1.0.0", "", DriftUnknown},
+	}
+
+	for _, testCase := range testCases {
+		if got := drift(testCase.current, testCase.latest); got != testCase.want {
+			t.Errorf("drift(%q, %q) returned %v, want %v", testCase.current, testCase.latest, got, testCase.want)
+		}
+	}
+}
+
+func TestDrift_UnmarshalText(t *testing.T) {
+	var d Drift
+	if err := d.UnmarshalText([]byte("minor")); err != nil {
+		t.Fatalf("UnmarshalText returned unexpected error: %v", err)
+	}
+	if d != DriftMinor {
+		t.Errorf("expected %v, got %v", DriftMinor, d)
+	}
+
+	if err := d.UnmarshalText([]byte("huge")); err == nil {
+		t.Errorf("expected an error for an unknown drift")
+	}
+}
+
+func TestCompare(t *testing.T) {
+	dep := Dependency{
+		ProjectRef:  librariesio.ProjectRef{Platform: PlatformNPM, Name: "chalk"},
+		Requirement: "^1.1.3",
+	}
+	project := &librariesio.Project{
+		LatestStableRelease: &librariesio.Release{Number: librariesio.String("2.0.1")},
+	}
+
+	got := Compare(dep, project)
+	if got.Current != "1.1.3" || got.LatestStable != "2.0.1" || got.Drift != DriftMajor {
+		t.Errorf("unexpected result %+v", got)
+	}
+
+	if got := Compare(dep, nil); got.Drift != DriftUnknown {
+		t.Errorf("expected %v without a project, got %v", DriftUnknown, got.Drift)
+	}
+}
+
+func TestOutdated(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client := librariesio.NewClient("1234")
+	client.BaseURL, _ = url.Parse(server.URL + "/")
+
+	mux.HandleFunc("/pypi/cookiecutter", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{
+			"name": "cookiecutter",
+			"latest_release_number": "2.0.0rc1",
+			"latest_stable_release": {"number": "1.6.0"}
+		}`)
+	})
+	mux.HandleFunc("/pypi/poyo", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"name": "poyo", "latest_release_number": "0.4.1"}`)
+	})
+	mux.HandleFunc("/pypi/unknown", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"error":"Not Found"}`, http.StatusNotFound)
+	})
+
+	deps := []Dependency{
+		dep("pypi", "cookiecutter", "==1.5.1"),
+		dep("pypi", "poyo", ">=0.4.1"),
+		dep("pypi", "unknown", "==1.0"),
+	}
+
+	report, err := Outdated(context.Background(), client.Projects, deps)
+	if err != nil {
+		t.Fatalf("Outdated returned unexpected error: %v", err)
+	}
+
+	if got, want := len(report.Dependencies), 3; got != want {
+		t.Fatalf("Expected %d results, got %d", want, got)
+	}
+
+	cookiecutter := report.Dependencies[0]
+	if cookiecutter.LatestStable != "1.6.0" || cookiecutter.Drift != DriftMinor {
+		t.Errorf("unexpected result for cookiecutter: %+v", cookiecutter)
+	}
+
+	poyo := report.Dependencies[1]
+	if poyo.LatestStable != "0.4.1" || poyo.Drift != DriftNone {
+		t.Errorf("unexpected result for poyo: %+v", poyo)
+	}
+
+	unknown := report.Dependencies[2]
+	if unknown.Err == nil || unknown.Drift != DriftUnknown {
+		t.Errorf("unexpected result for unknown: %+v", unknown)
+	}
+
+	outdated := report.Outdated()
+	if len(outdated) != 1 || outdated[0] != cookiecutter {
+		t.Errorf("Outdated returned %+v, want only cookiecutter", outdated)
+	}
+}
+
+func TestOutdated_cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	client := librariesio.NewClient("1234")
+	_, err := Outdated(ctx, client.Projects, []Dependency{dep("pypi", "poyo", "")})
+
+	if err != context.Canceled {
+		t.Fatalf("expected ctx error, got %v", err)
+	}
+}
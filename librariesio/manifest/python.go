@@ -0,0 +1,124 @@
+package manifest
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// parseRequirements reads a pip requirements file. Options such as -r or -e,
+// as well as URLs and local paths, are skipped since they don't refer to
+// projects on PyPI.
+func parseRequirements(r io.Reader) ([]Dependency, error) {
+	var deps []Dependency
+
+	scanner := bufio.NewScanner(r)
+	var line string
+
+	for scanner.Scan() {
+		text := scanner.Text()
+
+		// Join lines continued with a backslash
+		if strings.HasSuffix(text, `\`) {
+			line += strings.TrimSuffix(text, `\`)
+			continue
+		}
+		line += text
+
+		if dep, ok := parseRequirement(line); ok {
+			deps = append(deps, dep)
+		}
+		line = ""
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if dep, ok := parseRequirement(line); ok {
+		deps = append(deps, dep)
+	}
+	return deps, nil
+}
+
+// requirementOption matches the start of per-requirement options such as
+// the --hash options written by pip-compile --generate-hashes
+var requirementOption = regexp.MustCompile(`\s-`)
+
+// parseRequirement parses a single requirement specifier such as
+// "requests[security]>=2.8.1,==2.8.* ; python_version < '2.7'"
+func parseRequirement(line string) (Dependency, bool) {
+	if i := strings.Index(line, "#"); i >= 0 {
+		line = line[:i]
+	}
+	if loc := requirementOption.FindStringIndex(line); loc != nil {
+		line = line[:loc[0]]
+	}
+	if i := strings.Index(line, ";"); i >= 0 {
+		line = line[:i]
+	}
+	line = strings.TrimSpace(line)
+
+	if line == "" || strings.HasPrefix(line, "-") ||
+		strings.HasPrefix(line, ".") || strings.HasPrefix(line, "/") {
+		return Dependency{}, false
+	}
+
+	end := strings.IndexFunc(line, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' ||
+			r >= '0' && r <= '9' || r == '-' || r == '_' || r == '.')
+	})
+	if end < 0 {
+		end = len(line)
+	}
+	name, rest := line[:end], line[end:]
+
+	// URLs such as https://... or git+https://...
+	if strings.HasPrefix(rest, ":") || strings.HasPrefix(rest, "+") {
+		return Dependency{}, false
+	}
+
+	// Drop extras like [security]
+	if strings.HasPrefix(rest, "[") {
+		if i := strings.Index(rest, "]"); i >= 0 {
+			rest = rest[i+1:]
+		}
+	}
+
+	// Direct references like "name @ file:///..." have no version constraint
+	if strings.HasPrefix(strings.TrimSpace(rest), "@") {
+		rest = ""
+	}
+
+	if name == "" {
+		return Dependency{}, false
+	}
+	return newDependency(PlatformPypi, name, strings.Replace(rest, " ", "", -1)), true
+}
+
+// parsePipfileLock reads the default and develop packages of a Pipfile.lock
+func parsePipfileLock(r io.Reader) ([]Dependency, error) {
+	type lockPackage struct {
+		Version string `json:"version"`
+	}
+
+	var lock struct {
+		Default map[string]lockPackage `json:"default"`
+		Develop map[string]lockPackage `json:"develop"`
+	}
+
+	if err := json.NewDecoder(r).Decode(&lock); err != nil {
+		return nil, err
+	}
+
+	versions := func(packages map[string]lockPackage) map[string]string {
+		m := make(map[string]string, len(packages))
+		for name, pkg := range packages {
+			m[name] = pkg.Version
+		}
+		return m
+	}
+
+	return fromMaps(PlatformPypi, versions(lock.Default), versions(lock.Develop)), nil
+}
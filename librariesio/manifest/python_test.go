@@ -0,0 +1,62 @@
+package manifest
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseRequirements(t *testing.T) {
+	requirements := `# Pinned dependencies
+cookiecutter==1.5.1
+requests[security]>=2.8.1, <3 ; python_version < "3.0"
+poyo  # no version
+https://example.com/archive.zip
+-r requirements-dev.txt
+-e git+https://github.com/hackebrot/poyo.git#egg=poyo
+./local/package
+pytest \
+    >=3.0
+mypkg @ https://example.com/mypkg.zip
+certifi==2023.7.22 \
+    --hash=sha256:abc \
+    --hash=sha256:def
+`
+
+	deps, err := Parse("requirements.txt", strings.NewReader(requirements))
+	if err != nil {
+		t.Fatalf("Parse returned unexpected error: %v", err)
+	}
+
+	assertDependencies(t, deps, []Dependency{
+		dep("pypi", "cookiecutter", "==1.5.1"),
+		dep("pypi", "requests", ">=2.8.1,<3"),
+		dep("pypi", "poyo", ""),
+		dep("pypi", "pytest", ">=3.0"),
+		dep("pypi", "mypkg", ""),
+		dep("pypi", "certifi", "==2023.7.22"),
+	})
+}
+
+func TestParsePipfileLock(t *testing.T) {
+	lock := `{
+		"_meta": {"hash": {"sha256": "abc"}},
+		"default": {
+			"requests": {"version": "==2.18.4", "hashes": []},
+			"certifi": {"version": "==2017.11.5"}
+		},
+		"develop": {
+			"pytest": {"version": "==3.3.1"}
+		}
+	}`
+
+	deps, err := Parse("Pipfile.lock", strings.NewReader(lock))
+	if err != nil {
+		t.Fatalf("Parse returned unexpected error: %v", err)
+	}
+
+	assertDependencies(t, deps, []Dependency{
+		dep("pypi", "certifi", "==2017.11.5"),
+		dep("pypi", "requests", "==2.18.4"),
+		dep("pypi", "pytest", "==3.3.1"),
+	})
+}
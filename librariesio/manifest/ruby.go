@@ -0,0 +1,50 @@
+package manifest
+
+import (
+	"bufio"
+	"io"
+	"strings"
+)
+
+// parseGemfileLock reads the resolved gems from the specs of the GEM
+// sections in a Gemfile.lock. Gems are listed with four spaces of
+// indentation, their own dependencies with six.
+func parseGemfileLock(r io.Reader) ([]Dependency, error) {
+	var deps []Dependency
+
+	scanner := bufio.NewScanner(r)
+	inGem, inSpecs := false, false
+
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		if line != "" && !strings.HasPrefix(line, " ") {
+			// A new top-level section such as GEM, GIT, PLATFORMS
+			inGem = line == "GEM"
+			inSpecs = false
+			continue
+		}
+		if !inGem {
+			continue
+		}
+		if strings.TrimSpace(line) == "specs:" {
+			inSpecs = true
+			continue
+		}
+		if !inSpecs || !strings.HasPrefix(line, "    ") || strings.HasPrefix(line, "     ") {
+			continue
+		}
+
+		spec := strings.TrimSpace(line)
+		name, version := spec, ""
+		if i := strings.Index(spec, " ("); i >= 0 && strings.HasSuffix(spec, ")") {
+			name, version = spec[:i], spec[i+2:len(spec)-1]
+		}
+		deps = append(deps, newDependency(PlatformRubygems, name, version))
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return deps, nil
+}
@@ -0,0 +1,44 @@
+package manifest
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseGemfileLock(t *testing.T) {
+	lock := `GIT
+  remote: https://github.com/rails/rails.git
+  revision: abc
+  specs:
+    rails (7.1.0.alpha)
+
+GEM
+  remote: https://rubygems.org/
+  specs:
+    rack (2.2.3)
+    rack-test (1.1.0)
+      rack (>= 1.0, < 3)
+    rake (13.0.6)
+
+PLATFORMS
+  ruby
+
+DEPENDENCIES
+  rack-test
+  rake (~> 13.0)
+
+BUNDLED WITH
+   2.3.7
+`
+
+	deps, err := Parse("Gemfile.lock", strings.NewReader(lock))
+	if err != nil {
+		t.Fatalf("Parse returned unexpected error: %v", err)
+	}
+
+	assertDependencies(t, deps, []Dependency{
+		dep("rubygems", "rack", "2.2.3"),
+		dep("rubygems", "rack-test", "1.1.0"),
+		dep("rubygems", "rake", "13.0.6"),
+	})
+}
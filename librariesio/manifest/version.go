@@ -0,0 +1,69 @@
+package manifest
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Version returns the version pinned by the requirement of the dependency or
+// the lower bound of its range, e.g. "1.2.0" for "^1.2.0" or ">=1.2.0,<2".
+// It is a best-effort interpretation across the different syntaxes of the
+// package managers and returns "" if the requirement doesn't name a version.
+func (d Dependency) Version() string {
+	req := strings.TrimLeft(d.Requirement, "=<>~^![( ")
+
+	// Take the first clause of ranges like ">=1.0, <2" or "1.x || 2.x"
+	if i := strings.IndexAny(req, ", |"); i >= 0 {
+		req = req[:i]
+	}
+
+	req = strings.TrimRight(req, "])")
+	req = strings.TrimSuffix(strings.TrimSuffix(req, ".*"), ".x")
+
+	if req == "" || req == "*" || req == "x" || req == "latest" {
+		return ""
+	}
+	return req
+}
+
+// version is a parsed major.minor.patch version number
+type version struct {
+	major, minor, patch int
+}
+
+// parseVersion parses versions like "v1.2.3", "1.2" or "2.0.0-rc1". Missing
+// parts default to zero, pre-release and build suffixes are ignored.
+func parseVersion(s string) (version, bool) {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "v")
+	if i := strings.IndexAny(s, "-+_ "); i >= 0 {
+		s = s[:i]
+	}
+	if s == "" {
+		return version{}, false
+	}
+
+	parts := strings.SplitN(s, ".", 4)
+	var nums [3]int
+
+	for i := 0; i < len(parts) && i < 3; i++ {
+		n, err := strconv.Atoi(parts[i])
+		if err != nil {
+			return version{}, false
+		}
+		nums[i] = n
+	}
+	return version{nums[0], nums[1], nums[2]}, true
+}
+
+// compare returns -1, 0 or 1 if v is less than, equal to or greater than o
+func (v version) compare(o version) int {
+	for _, d := range []int{v.major - o.major, v.minor - o.minor, v.patch - o.patch} {
+		if d < 0 {
+			return -1
+		}
+		if d > 0 {
+			return 1
+		}
+	}
+	return 0
+}
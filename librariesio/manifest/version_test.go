@@ -0,0 +1,50 @@
+package manifest
+
+import "testing"
+
+func TestDependencyVersion(t *testing.T) {
+	testCases := []struct {
+		requirement string
+		want        string
+	}{
+		{"v0.3.1", "v0.3.1"},
+		{"==1.5.1", "1.5.1"},
+		{"^1.2.0", "1.2.0"},
+		{"~> 13.0", "13.0"},
+		{">=2.8.1,<3", "2.8.1"},
+		{"1.x || 2.x", "1"},
+		{"1.2.*", "1.2"},
+		{"[1.0,2.0)", "1.0"},
+		{"*", ""},
+		{"", ""},
+	}
+
+	for _, testCase := range testCases {
+		d := dep("npm", "ava", testCase.requirement)
+		if got := d.Version(); got != testCase.want {
+			t.Errorf("Version() for %q returned %q, want %q", testCase.requirement, got, testCase.want)
+		}
+	}
+}
+
+func TestParseVersion(t *testing.T) {
+	testCases := []struct {
+		s    string
+		want version
+		ok   bool
+	}{
+		{"v1.2.3", version{1, 2, 3}, true},
+		{"1.2", version{1, 2, 0}, true},
+		{"2.0.0-rc1", version{2, 0, 0}, true},
+		{"31.1-jre", version{31, 1, 0}, true},
+		{"latest", version{}, false},
+		{"", version{}, false},
+	}
+
+	for _, testCase := range testCases {
+		got, ok := parseVersion(testCase.s)
+		if got != testCase.want || ok != testCase.ok {
+			t.Errorf("parseVersion(%q) returned %v, %v, want %v, %v", testCase.s, got, ok, testCase.want, testCase.ok)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package librariesio
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// RequestOption changes the requests sent with a context, see
+// WithRequestOptions
+type RequestOption func(*requestOptions)
+
+// requestOptions are the options of a context
+type requestOptions struct {
+	header  http.Header
+	timeout time.Duration
+	auth    Authenticator
+}
+
+// requestOptionsKey is the context key of the request options
+type requestOptionsKey struct{}
+
+// WithRequestOptions returns a copy of ctx carrying the options. Client.Do
+// applies them to every request sent with the context, so a single Client
+// can be shared by callers with different needs:
+//
+//	ctx = librariesio.WithRequestOptions(ctx, librariesio.SkipCache(), librariesio.WithTimeout(5*time.Second))
+//	project, _, err := c.Projects.Get(ctx, "pypi", "cookiecutter")
+//
+// Options are added to those already carried by ctx and later options take
+// precedence.
+func WithRequestOptions(ctx context.Context, opts ...RequestOption) context.Context {
+	o := requestOptionsFrom(ctx)
+	o.header = o.header.Clone()
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return context.WithValue(ctx, requestOptionsKey{}, o)
+}
+
+// requestOptionsFrom returns the options carried by ctx
+func requestOptionsFrom(ctx context.Context) requestOptions {
+	o, _ := ctx.Value(requestOptionsKey{}).(requestOptions)
+	return o
+}
+
+// SkipCache asks caching transports such as transport.Cache to fetch a fresh
+// response by sending a "Cache-Control: no-cache" header
+func SkipCache() RequestOption {
+	return WithHeader("Cache-Control", "no-cache")
+}
+
+// WithTimeout limits the time Client.Do waits for a response, including
+// retries
+func WithTimeout(d time.Duration) RequestOption {
+	return func(o *requestOptions) {
+		o.timeout = d
+	}
+}
+
+// WithHeader sets a header of the request, replacing any value set by the
+// client
+func WithHeader(key, value string) RequestOption {
+	return func(o *requestOptions) {
+		if o.header == nil {
+			o.header = make(http.Header)
+		}
+		o.header.Set(key, value)
+	}
+}
+
+// WithAPIKey sends the given key as api_key query param instead of the key of
+// the client. Use WithAuthenticator to send it in a header.
+func WithAPIKey(key string) RequestOption {
+	return WithAuthenticator(&QueryAuth{Key: key})
+}
+
+// WithAuthenticator authenticates the request with a instead of the
+// Authenticator of the client. The api_key query param and the header of a
+// HeaderAuth or KeyPool of the client are removed first, so only the
+// credentials of a are sent.
+func WithAuthenticator(a Authenticator) RequestOption {
+	return func(o *requestOptions) {
+		o.auth = a
+	}
+}
+
+// authenticator returns the Authenticator for requests sent with ctx
+func (c *Client) authenticator(ctx context.Context) Authenticator {
+	if o := requestOptionsFrom(ctx); o.auth != nil {
+		return o.auth
+	}
+	return c.auth
+}
+
+// applyOptions applies the options to the request of info, which is a copy
+// of the request passed to Client.Do
+func (c *Client) applyOptions(info *RequestInfo, o requestOptions) error {
+	req := info.Request
+	for key, values := range o.header {
+		req.Header[key] = values
+	}
+
+	if o.auth != nil {
+		c.removeCredentials(req)
+		return o.auth.Authenticate(req)
+	}
+	return nil
+}
@@ -0,0 +1,143 @@
+package librariesio
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestWithRequestOptions(t *testing.T) {
+	server, mux, url := startNewServer()
+	client := NewClient(APIKey)
+	client.BaseURL = url
+	defer server.Close()
+
+	mux.HandleFunc("/pypi/cookiecutter", func(w http.ResponseWriter, r *http.Request) {
+		if got, want := r.Header.Get("Cache-Control"), "no-cache"; got != want {
+			t.Errorf("\nExpected Cache-Control %v\nGot %v", want, got)
+		}
+		if got, want := r.Header.Get("X-Team"), "search"; got != want {
+			t.Errorf("\nExpected X-Team %v\nGot %v", want, got)
+		}
+		if got, want := r.URL.Query().Get("api_key"), "other"; got != want {
+			t.Errorf("\nExpected api_key %v\nGot %v", want, got)
+		}
+		fmt.Fprintf(w, `{"name":"cookiecutter"}`)
+	})
+
+	ctx := WithRequestOptions(context.Background(), SkipCache(), WithHeader("X-Team", "platform"))
+	ctx = WithRequestOptions(ctx, WithHeader("X-Team", "search"), WithAPIKey("other"))
+
+	req, err := client.NewRequest("GET", "pypi/cookiecutter", nil)
+	if err != nil {
+		t.Fatalf("NewRequest returned error: %v", err)
+	}
+	if _, err := client.Do(ctx, req, nil); err != nil {
+		t.Fatalf("Do returned error: %v", err)
+	}
+
+	if got := req.URL.Query().Get("api_key"); got != APIKey {
+		t.Errorf("expected the request to be unchanged, got api_key %v", got)
+	}
+	if got := req.Header.Get("X-Team"); got != "" {
+		t.Errorf("expected the request to be unchanged, got X-Team %v", got)
+	}
+}
+
+func TestWithRequestOptions_timeout(t *testing.T) {
+	server, mux, url := startNewServer()
+	client := NewClient(APIKey)
+	client.BaseURL = url
+	defer server.Close()
+
+	mux.HandleFunc("/pypi/cookiecutter", func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+		fmt.Fprintf(w, `{"name":"cookiecutter"}`)
+	})
+
+	ctx := WithRequestOptions(context.Background(), WithTimeout(10*time.Millisecond))
+	if _, _, err := client.Projects.Get(ctx, "pypi", "cookiecutter"); err != context.DeadlineExceeded {
+		t.Errorf("\nExpected %v\nGot %v", context.DeadlineExceeded, err)
+	}
+}
+
+func TestWithAuthenticator_keyPool(t *testing.T) {
+	server, mux, url := startNewServer()
+	client := NewClient(APIKey)
+	client.BaseURL = url
+	defer server.Close()
+
+	var keys []string
+	mux.HandleFunc("/pypi/cookiecutter", func(w http.ResponseWriter, r *http.Request) {
+		key := r.URL.Query().Get("api_key")
+		keys = append(keys, key)
+		if key != "b" {
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprintf(w, `{"error":"Invalid API key"}`)
+			return
+		}
+		fmt.Fprintf(w, `{"name":"cookiecutter"}`)
+	})
+
+	ctx := WithRequestOptions(context.Background(), WithAuthenticator(NewKeyPool("a", "b")))
+	if _, _, err := client.Projects.Get(ctx, "pypi", "cookiecutter"); err != nil {
+		t.Fatalf("Projects.Get returned error: %v", err)
+	}
+	if got, want := fmt.Sprint(keys), "[a b]"; got != want {
+		t.Errorf("\nExpected %v\nGot %v", want, got)
+	}
+}
+
+func TestWithAuthenticator_header(t *testing.T) {
+	server, mux, url := startNewServer()
+	client := NewClient(APIKey)
+	client.BaseURL = url
+	defer server.Close()
+
+	mux.HandleFunc("/pypi/cookiecutter", func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.RawQuery; got != "page=2" {
+			t.Errorf("expected the api_key of the client to be removed, got query %v", got)
+		}
+		if got, want := r.Header.Get("X-Api-Key"), "other"; got != want {
+			t.Errorf("\nExpected X-Api-Key %v\nGot %v", want, got)
+		}
+		fmt.Fprintf(w, `{"name":"cookiecutter"}`)
+	})
+
+	req, err := client.NewRequest("GET", "pypi/cookiecutter?page=2", nil)
+	if err != nil {
+		t.Fatalf("NewRequest returned error: %v", err)
+	}
+	ctx := WithRequestOptions(context.Background(), WithAuthenticator(&HeaderAuth{Key: "other"}))
+	if _, err := client.Do(ctx, req, nil); err != nil {
+		t.Fatalf("Do returned error: %v", err)
+	}
+}
+
+func TestWithAuthenticator_clientHeader(t *testing.T) {
+	server, mux, url := startNewServer()
+	client := NewClient(APIKey)
+	client.BaseURL = url
+	client.SetAuthenticator(&HeaderAuth{Key: APIKey})
+	defer server.Close()
+
+	mux.HandleFunc("/pypi/cookiecutter", func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get(DefaultAuthHeader); got != "" {
+			t.Errorf("expected the header of the client to be removed, got %v", got)
+		}
+		if got, want := r.URL.Query().Get("api_key"), "other"; got != want {
+			t.Errorf("\nExpected api_key %v\nGot %v", want, got)
+		}
+		fmt.Fprintf(w, `{"name":"cookiecutter"}`)
+	})
+
+	ctx := WithRequestOptions(context.Background(), WithAPIKey("other"))
+	if _, _, err := client.Projects.Get(ctx, "pypi", "cookiecutter"); err != nil {
+		t.Fatalf("Projects.Get returned error: %v", err)
+	}
+}
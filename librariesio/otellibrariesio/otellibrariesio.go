@@ -0,0 +1,216 @@
+/*
+Package otellibrariesio instruments a librariesio.Client with OpenTelemetry
+traces and metrics.
+
+Instrument adds hooks to the client which create a span for every call of
+Client.Do, covering all attempts to send the request, and record the number
+and latency of requests:
+
+	client := librariesio.NewClient(apiKey)
+	if err := otellibrariesio.Instrument(client); err != nil {
+		return err
+	}
+
+Spans and metrics carry the endpoint template, e.g. ":platform/:name", and
+the platform of the project rather than the request URL. The URL attribute of
+spans has the api_key redacted. The trace context is injected into the
+request headers with the configured propagators.
+*/
+package otellibrariesio
+
+import (
+	"context"
+	"time"
+
+	"github.com/hackebrot/go-librariesio/librariesio"
+	"github.com/hackebrot/go-librariesio/librariesio/transport"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName identifies the tracer and meter of the package
+const instrumentationName = "github.com/hackebrot/go-librariesio/librariesio/otellibrariesio"
+
+// Attribute keys of spans and metrics
+const (
+	MethodKey     = attribute.Key("http.request.method")
+	StatusCodeKey = attribute.Key("http.response.status_code")
+	URLKey        = attribute.Key("url.full")
+	EndpointKey   = attribute.Key("url.template")
+	PlatformKey   = attribute.Key("librariesio.platform")
+	RetryCountKey = attribute.Key("http.request.resend_count")
+	CacheHitKey   = attribute.Key("librariesio.cache_hit")
+)
+
+// Names of the metrics
+const (
+	RequestsMetric = "librariesio.client.requests"
+	DurationMetric = "librariesio.client.request.duration"
+)
+
+// config holds the options of Instrument
+type config struct {
+	tracerProvider trace.TracerProvider
+	meterProvider  metric.MeterProvider
+	propagators    propagation.TextMapPropagator
+}
+
+// Option configures Instrument
+type Option func(*config)
+
+// WithTracerProvider sets the TracerProvider, the global provider is used by
+// default
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return func(c *config) {
+		c.tracerProvider = provider
+	}
+}
+
+// WithMeterProvider sets the MeterProvider, the global provider is used by
+// default
+func WithMeterProvider(provider metric.MeterProvider) Option {
+	return func(c *config) {
+		c.meterProvider = provider
+	}
+}
+
+// WithPropagators sets the propagators which inject the trace context into
+// requests, the global propagators are used by default
+func WithPropagators(propagators propagation.TextMapPropagator) Option {
+	return func(c *config) {
+		c.propagators = propagators
+	}
+}
+
+// stateKey is the context key of the state of an instrumented request
+type stateKey struct{}
+
+// state is stored in the request context when the span is started
+type state struct {
+	span  trace.Span
+	start time.Time
+}
+
+// instrumentation holds the tracer and metric instruments of a client
+type instrumentation struct {
+	tracer      trace.Tracer
+	propagators propagation.TextMapPropagator
+	requests    metric.Int64Counter
+	duration    metric.Float64Histogram
+}
+
+// Instrument adds hooks to the client which trace and measure its requests.
+// It returns an error if the metric instruments can't be created.
+func Instrument(c *librariesio.Client, opts ...Option) error {
+	cfg := &config{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	if cfg.tracerProvider == nil {
+		cfg.tracerProvider = otel.GetTracerProvider()
+	}
+	if cfg.meterProvider == nil {
+		cfg.meterProvider = otel.GetMeterProvider()
+	}
+	if cfg.propagators == nil {
+		cfg.propagators = otel.GetTextMapPropagator()
+	}
+
+	meter := cfg.meterProvider.Meter(instrumentationName)
+	requests, err := meter.Int64Counter(RequestsMetric,
+		metric.WithDescription("Number of calls to the libraries.io API"),
+		metric.WithUnit("{request}"),
+	)
+	if err != nil {
+		return err
+	}
+	duration, err := meter.Float64Histogram(DurationMetric,
+		metric.WithDescription("Duration of calls to the libraries.io API including retries"),
+		metric.WithUnit("s"),
+	)
+	if err != nil {
+		return err
+	}
+
+	i := &instrumentation{
+		tracer:      cfg.tracerProvider.Tracer(instrumentationName),
+		propagators: cfg.propagators,
+		requests:    requests,
+		duration:    duration,
+	}
+	c.BeforeRequest(i.beforeRequest)
+	c.AfterDo(i.afterDo)
+	return nil
+}
+
+// attributes returns the attributes shared by spans and metrics
+func attributes(info *librariesio.RequestInfo) []attribute.KeyValue {
+	attrs := []attribute.KeyValue{MethodKey.String(info.Request.Method)}
+	if info.Endpoint != "" {
+		attrs = append(attrs, EndpointKey.String(info.Endpoint))
+	}
+	if info.Platform != "" {
+		attrs = append(attrs, PlatformKey.String(info.Platform))
+	}
+	return attrs
+}
+
+// beforeRequest starts the span on the first attempt and injects the trace
+// context into every attempt
+func (i *instrumentation) beforeRequest(info *librariesio.RequestInfo) error {
+	ctx := info.Request.Context()
+
+	if info.Attempt == 1 {
+		name := info.Request.Method
+		if info.Endpoint != "" {
+			name += " " + info.Endpoint
+		}
+
+		attrs := attributes(info)
+		if info.URL != nil {
+			attrs = append(attrs, URLKey.String(info.URL.String()))
+		}
+
+		var span trace.Span
+		ctx, span = i.tracer.Start(ctx, name,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(attrs...),
+		)
+		ctx = context.WithValue(ctx, stateKey{}, &state{span: span, start: time.Now()})
+		info.Request = info.Request.WithContext(ctx)
+	}
+
+	i.propagators.Inject(ctx, propagation.HeaderCarrier(info.Request.Header))
+	return nil
+}
+
+// afterDo ends the span and records the metrics of the request
+func (i *instrumentation) afterDo(info *librariesio.RequestInfo, err error) {
+	s, ok := info.Request.Context().Value(stateKey{}).(*state)
+	if !ok {
+		return
+	}
+
+	attrs := attributes(info)
+	if info.StatusCode != 0 {
+		attrs = append(attrs, StatusCodeKey.Int(info.StatusCode))
+	}
+
+	cacheHit := info.Response != nil && info.Response.Header.Get(transport.FromCacheHeader) == "1"
+	attrs = append(attrs, CacheHitKey.Bool(cacheHit))
+
+	s.span.SetAttributes(append(attrs, RetryCountKey.Int(info.Attempt-1))...)
+	if err != nil {
+		s.span.RecordError(err)
+		s.span.SetStatus(codes.Error, err.Error())
+	}
+	s.span.End()
+
+	ctx := info.Request.Context()
+	i.requests.Add(ctx, 1, metric.WithAttributes(attrs...))
+	i.duration.Record(ctx, time.Since(s.start).Seconds(), metric.WithAttributes(attrs...))
+}
@@ -0,0 +1,168 @@
+package otellibrariesio
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/hackebrot/go-librariesio/librariesio"
+	"github.com/hackebrot/go-librariesio/librariesio/librariesiotest"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// instrumented returns a client for a fake server instrumented with an
+// in-memory span recorder and metric reader
+func instrumented(t *testing.T) (*librariesiotest.Server, *librariesio.Client, *tracetest.SpanRecorder, *sdkmetric.ManualReader) {
+	server := librariesiotest.NewServer(&librariesiotest.Fixtures{
+		Projects: []*librariesiotest.ProjectFixture{
+			{Project: &librariesio.Project{
+				Platform: librariesio.String("Pypi"),
+				Name:     librariesio.String("cookiecutter"),
+			}},
+		},
+	})
+	client := server.NewClient()
+
+	spans := tracetest.NewSpanRecorder()
+	reader := sdkmetric.NewManualReader()
+
+	err := Instrument(client,
+		WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans))),
+		WithMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))),
+		WithPropagators(propagation.TraceContext{}),
+	)
+	if err != nil {
+		t.Fatalf("Instrument returned unexpected error: %v", err)
+	}
+	return server, client, spans, reader
+}
+
+func attributeMap(attrs []attribute.KeyValue) map[attribute.Key]attribute.Value {
+	m := make(map[attribute.Key]attribute.Value)
+	for _, kv := range attrs {
+		m[kv.Key] = kv.Value
+	}
+	return m
+}
+
+func TestInstrument_span(t *testing.T) {
+	server, client, spans, _ := instrumented(t)
+	defer server.Close()
+
+	var traceparent string
+	client.AfterResponse(func(info *librariesio.RequestInfo) {
+		traceparent = info.Request.Header.Get("Traceparent")
+	})
+
+	if _, _, err := client.Projects.Get(context.Background(), "pypi", "cookiecutter"); err != nil {
+		t.Fatalf("Projects.Get returned unexpected error: %v", err)
+	}
+
+	ended := spans.Ended()
+	if len(ended) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(ended))
+	}
+	span := ended[0]
+
+	if want := "GET :platform/:name"; span.Name() != want {
+		t.Errorf("\nExpected %v\nGot %v", want, span.Name())
+	}
+
+	attrs := attributeMap(span.Attributes())
+	if got := attrs[EndpointKey].AsString(); got != ":platform/:name" {
+		t.Errorf("unexpected endpoint %q", got)
+	}
+	if got := attrs[PlatformKey].AsString(); got != "pypi" {
+		t.Errorf("unexpected platform %q", got)
+	}
+	if got := attrs[StatusCodeKey].AsInt64(); got != http.StatusOK {
+		t.Errorf("unexpected status code %v", got)
+	}
+	if got := attrs[RetryCountKey].AsInt64(); got != 0 {
+		t.Errorf("unexpected retry count %v", got)
+	}
+	if got := attrs[CacheHitKey].AsBool(); got {
+		t.Error("expected no cache hit")
+	}
+
+	for _, kv := range span.Attributes() {
+		if strings.Contains(kv.Value.Emit(), librariesiotest.APIKey) {
+			t.Errorf("attribute %v contains the API key: %v", kv.Key, kv.Value.Emit())
+		}
+	}
+
+	if !strings.Contains(traceparent, span.SpanContext().TraceID().String()) {
+		t.Errorf("expected the trace context to be injected, got %q", traceparent)
+	}
+}
+
+func TestInstrument_retry(t *testing.T) {
+	server, client, spans, _ := instrumented(t)
+	defer server.Close()
+
+	client.AfterResponse(func(info *librariesio.RequestInfo) {
+		info.Retry = info.Attempt < 3
+	})
+
+	if _, _, err := client.Projects.Get(context.Background(), "pypi", "cookiecutter"); err != nil {
+		t.Fatalf("Projects.Get returned unexpected error: %v", err)
+	}
+
+	ended := spans.Ended()
+	if len(ended) != 1 {
+		t.Fatalf("expected 1 span for all attempts, got %d", len(ended))
+	}
+	if got := attributeMap(ended[0].Attributes())[RetryCountKey].AsInt64(); got != 2 {
+		t.Errorf("expected retry count 2, got %v", got)
+	}
+}
+
+func TestInstrument_error(t *testing.T) {
+	server, client, spans, reader := instrumented(t)
+	defer server.Close()
+
+	if _, _, err := client.Projects.Get(context.Background(), "pypi", "missing"); err == nil {
+		t.Fatal("Expected error to be returned")
+	}
+
+	span := spans.Ended()[0]
+	if span.Status().Code != codes.Error {
+		t.Errorf("expected error status, got %v", span.Status())
+	}
+	if strings.Contains(span.Status().Description, librariesiotest.APIKey) {
+		t.Errorf("status contains the API key: %v", span.Status().Description)
+	}
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatalf("Collect returned unexpected error: %v", err)
+	}
+
+	metrics := make(map[string]metricdata.Aggregation)
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			metrics[m.Name] = m.Data
+		}
+	}
+
+	requests, ok := metrics[RequestsMetric].(metricdata.Sum[int64])
+	if !ok || len(requests.DataPoints) != 1 {
+		t.Fatalf("unexpected requests metric %+v", metrics[RequestsMetric])
+	}
+	point := requests.DataPoints[0]
+	if status, _ := point.Attributes.Value(StatusCodeKey); point.Value != 1 || status.AsInt64() != http.StatusNotFound {
+		t.Errorf("unexpected data point %+v", point)
+	}
+
+	duration, ok := metrics[DurationMetric].(metricdata.Histogram[float64])
+	if !ok || len(duration.DataPoints) != 1 || duration.DataPoints[0].Count != 1 {
+		t.Errorf("unexpected duration metric %+v", metrics[DurationMetric])
+	}
+}
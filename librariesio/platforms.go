@@ -0,0 +1,34 @@
+package librariesio
+
+import (
+	"context"
+	"net/http"
+)
+
+// Platform represents a package manager supported by libraries.io
+type Platform struct {
+	Color           *string `json:"color,omitempty"`
+	DefaultLanguage *string `json:"default_language,omitempty"`
+	Homepage        *string `json:"homepage,omitempty"`
+	Name            *string `json:"name,omitempty"`
+	ProjectCount    *int    `json:"project_count,omitempty"`
+}
+
+// Platforms returns the package managers supported by libraries.io
+//
+// GET https://libraries.io/api/platforms
+func (c *Client) Platforms(ctx context.Context) ([]*Platform, *http.Response, error) {
+	request, err := c.NewRequest("GET", "platforms", nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var platforms []*Platform
+
+	response, err := c.Do(ctx, request, &platforms)
+	if err != nil {
+		return nil, response, err
+	}
+
+	return platforms, response, nil
+}
@@ -0,0 +1,52 @@
+package librariesio
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/hackebrot/go-repr/repr"
+)
+
+func TestPlatforms(t *testing.T) {
+	server, mux, url := startNewServer()
+	client := NewClient(APIKey)
+	client.BaseURL = url
+	defer server.Close()
+
+	mux.HandleFunc("/platforms", func(w http.ResponseWriter, r *http.Request) {
+		if method := "GET"; method != r.Method {
+			t.Errorf("expected HTTP %v request, got %v", method, r.Method)
+		}
+		fmt.Fprintf(w, `[
+			{
+				"name": "NPM",
+				"project_count": 1200000,
+				"homepage": "https://www.npmjs.com",
+				"color": "#e8d44d",
+				"default_language": "JavaScript"
+			}
+		]`)
+	})
+
+	platforms, _, err := client.Platforms(context.Background())
+	if err != nil {
+		t.Fatalf("Platforms returned unexpected error: %v", err)
+	}
+
+	want := []*Platform{
+		{
+			Name:            String("NPM"),
+			ProjectCount:    Int(1200000),
+			Homepage:        String("https://www.npmjs.com"),
+			Color:           String("#e8d44d"),
+			DefaultLanguage: String("JavaScript"),
+		},
+	}
+
+	if !reflect.DeepEqual(platforms, want) {
+		t.Errorf("\nExpected %v\nGot %v", repr.Repr(want), repr.Repr(platforms))
+	}
+}
@@ -4,11 +4,21 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"net/url"
 	"time"
 )
 
+// ProjectRef identifies a project by its platform and name, for instance
+// when it is read from a manifest file rather than returned by the API.
+type ProjectRef struct {
+	Platform string `json:"platform"`
+	Name     string `json:"name"`
+}
+
 // Project represents a project on libraries.io
 type Project struct {
+	DependentReposCount      *int       `json:"dependent_repos_count,omitempty"`
+	DependentsCount          *int       `json:"dependents_count,omitempty"`
 	Description              *string    `json:"description,omitempty"`
 	Forks                    *int       `json:"forks,omitempty"`
 	Homepage                 *string    `json:"homepage,omitempty"`
@@ -26,7 +36,7 @@ type Project struct {
 	Status                   *string    `json:"status,omitempty"`
 	Versions                 []*Release `json:"versions,omitempty"`
 
-	// Dependencies are only populated for ProjectDeps
+	// Dependencies are only populated for Projects.Deps
 	Dependencies []*ProjectDependency `json:"dependencies,omitempty"`
 
 	// RepositoryURL is only populated for UserProjects
@@ -51,23 +61,49 @@ type ProjectDependency struct {
 	Requirements *string `json:"requirements,omitempty"`
 }
 
-// Project returns information about a project and it's versions.
+// SourceRank holds the breakdown of the SourceRank score of a project
+type SourceRank struct {
+	AllPrereleases          *int `json:"all_prereleases,omitempty"`
+	AnyOutdatedDependencies *int `json:"any_outdated_dependencies,omitempty"`
+	BasicInfoPresent        *int `json:"basic_info_present,omitempty"`
+	Contributors            *int `json:"contributors,omitempty"`
+	DependentProjects       *int `json:"dependent_projects,omitempty"`
+	DependentRepositories   *int `json:"dependent_repositories,omitempty"`
+	FollowsSemver           *int `json:"follows_semver,omitempty"`
+	IsDeprecated            *int `json:"is_deprecated,omitempty"`
+	IsRemoved               *int `json:"is_removed,omitempty"`
+	IsUnmaintained          *int `json:"is_unmaintained,omitempty"`
+	LicensePresent          *int `json:"license_present,omitempty"`
+	NotBrandNew             *int `json:"not_brand_new,omitempty"`
+	OnePointOh              *int `json:"one_point_oh,omitempty"`
+	ReadmePresent           *int `json:"readme_present,omitempty"`
+	RecentRelease           *int `json:"recent_release,omitempty"`
+	RepositoryPresent       *int `json:"repository_present,omitempty"`
+	Stars                   *int `json:"stars,omitempty"`
+	Subscribers             *int `json:"subscribers,omitempty"`
+	VersionsPresent         *int `json:"versions_present,omitempty"`
+}
+
+// ProjectsService handles the project endpoints of the API
+type ProjectsService service
+
+// Get returns information about a project and it's versions.
 //
 // GET https://libraries.io/api/:platform/:name
 //
 // plat is the platform/package manager of the project
 // name is the name of the project on the platform
-func (c *Client) Project(ctx context.Context, plat, name string) (*Project, *http.Response, error) {
-	urlStr := fmt.Sprintf("%v/%v", plat, name)
+func (s *ProjectsService) Get(ctx context.Context, plat, name string) (*Project, *http.Response, error) {
+	urlStr := fmt.Sprintf("%v/%v", plat, url.PathEscape(name))
 
-	request, err := c.NewRequest("GET", urlStr, nil)
+	request, err := s.client.NewRequest("GET", urlStr, nil)
 
 	if err != nil {
 		return nil, nil, err
 	}
 
 	project := new(Project)
-	response, err := c.Do(ctx, request, project)
+	response, err := s.client.Do(ctx, request, project)
 	if err != nil {
 		return nil, response, err
 	}
@@ -75,25 +111,25 @@ func (c *Client) Project(ctx context.Context, plat, name string) (*Project, *htt
 	return project, response, nil
 }
 
-// ProjectDeps returns information about a project and it's dependencies.
+// Deps returns information about a project and it's dependencies.
 //
 // GET https://libraries.io/api/:platform/:name/:version/dependencies
 //
 // plat is the platform/package manager of the project
 // name is the name of the project on the platform
 // ver is the version of the project - pass "latest" for current release
-func (c *Client) ProjectDeps(ctx context.Context, plat, name, ver string) (*Project, *http.Response, error) {
+func (s *ProjectsService) Deps(ctx context.Context, plat, name, ver string) (*Project, *http.Response, error) {
 
-	urlStr := fmt.Sprintf("%v/%v/%v/dependencies", plat, name, ver)
+	urlStr := fmt.Sprintf("%v/%v/%v/dependencies", plat, url.PathEscape(name), ver)
 
-	request, err := c.NewRequest("GET", urlStr, nil)
+	request, err := s.client.NewRequest("GET", urlStr, nil)
 	if err != nil {
 		return nil, nil, err
 	}
 
 	project := new(Project)
 
-	response, err := c.Do(ctx, request, project)
+	response, err := s.client.Do(ctx, request, project)
 	if err != nil {
 		return nil, response, err
 	}
@@ -101,26 +137,105 @@ func (c *Client) ProjectDeps(ctx context.Context, plat, name, ver string) (*Proj
 	return project, response, nil
 }
 
-// Search returns a slice of projects for the given search string
+// Dependents returns projects that depend on the given project
+//
+// GET https://libraries.io/api/:platform/:name/dependents
 //
-// GET https://libraries.io/api/search?q=amelia
-func (c *Client) Search(ctx context.Context, q string) ([]*Project, *http.Response, error) {
-	request, err := c.NewRequest("GET", "search", nil)
+// plat is the platform/package manager of the project
+// name is the name of the project on the platform
+func (s *ProjectsService) Dependents(ctx context.Context, plat, name string, opt *ListOptions) ([]*Project, *http.Response, error) {
+	urlStr := fmt.Sprintf("%v/%v/dependents", plat, url.PathEscape(name))
+
+	request, err := s.client.NewRequest("GET", urlStr, nil)
 	if err != nil {
 		return nil, nil, err
 	}
-
-	// Add query to request
-	query := request.URL.Query()
-	query.Set("q", q)
-	request.URL.RawQuery = query.Encode()
+	addListOptions(request, opt)
 
 	var projects []*Project
 
-	response, err := c.Do(ctx, request, &projects)
+	response, err := s.client.Do(ctx, request, &projects)
 	if err != nil {
 		return nil, response, err
 	}
 
 	return projects, response, nil
 }
+
+// Contributors returns the users who contributed to the repository of the
+// given project
+//
+// GET https://libraries.io/api/:platform/:name/contributors
+//
+// plat is the platform/package manager of the project
+// name is the name of the project on the platform
+func (s *ProjectsService) Contributors(ctx context.Context, plat, name string, opt *ListOptions) ([]*User, *http.Response, error) {
+	urlStr := fmt.Sprintf("%v/%v/contributors", plat, url.PathEscape(name))
+
+	request, err := s.client.NewRequest("GET", urlStr, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	addListOptions(request, opt)
+
+	var users []*User
+
+	response, err := s.client.Do(ctx, request, &users)
+	if err != nil {
+		return nil, response, err
+	}
+
+	return users, response, nil
+}
+
+// SourceRank returns the breakdown of the SourceRank score of a project
+//
+// GET https://libraries.io/api/:platform/:name/sourcerank
+//
+// plat is the platform/package manager of the project
+// name is the name of the project on the platform
+func (s *ProjectsService) SourceRank(ctx context.Context, plat, name string) (*SourceRank, *http.Response, error) {
+	urlStr := fmt.Sprintf("%v/%v/sourcerank", plat, url.PathEscape(name))
+
+	request, err := s.client.NewRequest("GET", urlStr, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rank := new(SourceRank)
+
+	response, err := s.client.Do(ctx, request, rank)
+	if err != nil {
+		return nil, response, err
+	}
+
+	return rank, response, nil
+}
+
+// Project returns information about a project and it's versions.
+//
+// Deprecated: Use Client.Projects.Get instead.
+func (c *Client) Project(ctx context.Context, plat, name string) (*Project, *http.Response, error) {
+	return c.Projects.Get(ctx, plat, name)
+}
+
+// ProjectDeps returns information about a project and it's dependencies.
+//
+// Deprecated: Use Client.Projects.Deps instead.
+func (c *Client) ProjectDeps(ctx context.Context, plat, name, ver string) (*Project, *http.Response, error) {
+	return c.Projects.Deps(ctx, plat, name, ver)
+}
+
+// Dependents returns projects that depend on the given project
+//
+// Deprecated: Use Client.Projects.Dependents instead.
+func (c *Client) Dependents(ctx context.Context, plat, name string, opt *ListOptions) ([]*Project, *http.Response, error) {
+	return c.Projects.Dependents(ctx, plat, name, opt)
+}
+
+// SourceRank returns the breakdown of the SourceRank score of a project
+//
+// Deprecated: Use Client.Projects.SourceRank instead.
+func (c *Client) SourceRank(ctx context.Context, plat, name string) (*SourceRank, *http.Response, error) {
+	return c.Projects.SourceRank(ctx, plat, name)
+}
@@ -37,6 +37,25 @@ func TestProject(t *testing.T) {
 	}
 }
 
+func TestProject_escapesName(t *testing.T) {
+	server, mux, url := startNewServer()
+	client := NewClient(APIKey)
+	client.BaseURL = url
+	defer server.Close()
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if uri := r.RequestURI; !strings.HasPrefix(uri, "/go/github.com%2Fhackebrot%2Fgo-repr?") {
+			t.Errorf("unexpected request URI, got %v", uri)
+		}
+		fmt.Fprintf(w, `{"name":"github.com/hackebrot/go-repr"}`)
+	})
+
+	_, _, err := client.Project(context.Background(), "go", "github.com/hackebrot/go-repr")
+	if err != nil {
+		t.Fatalf("Project returned unexpected error: %v", err)
+	}
+}
+
 func TestProjectDeps(t *testing.T) {
 	server, mux, url := startNewServer()
 	client := NewClient(APIKey)
@@ -88,58 +107,109 @@ func TestProjectDeps(t *testing.T) {
 	}
 }
 
-func TestSearch(t *testing.T) {
+func TestDependents(t *testing.T) {
 	server, mux, url := startNewServer()
 	client := NewClient(APIKey)
 	client.BaseURL = url
 	defer server.Close()
 
-	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+	mux.HandleFunc("/pypi/pytest/dependents", func(w http.ResponseWriter, r *http.Request) {
 		if method := "GET"; method != r.Method {
 			t.Errorf("expected HTTP %v request, got %v", method, r.Method)
 		}
+		if page := r.URL.Query().Get("page"); page != "3" {
+			t.Errorf("expected page 3, got %v", page)
+		}
+		fmt.Fprintf(w, `[{"name":"pytest-cookies"}]`)
+	})
 
-		if url := r.URL.String(); !strings.Contains(url, "/search") {
-			t.Errorf("unexpected URL, got %v", url)
+	projects, _, err := client.Dependents(context.Background(), "pypi", "pytest", &ListOptions{Page: 3})
+	if err != nil {
+		t.Fatalf("Dependents returned unexpected error: %v", err)
+	}
+
+	want := []*Project{{Name: String("pytest-cookies")}}
+
+	if !reflect.DeepEqual(projects, want) {
+		t.Errorf("\nExpected %v\nGot %v", repr.Repr(want), repr.Repr(projects))
+	}
+}
+
+func TestContributors(t *testing.T) {
+	server, mux, url := startNewServer()
+	client := NewClient(APIKey)
+	client.BaseURL = url
+	defer server.Close()
+
+	mux.HandleFunc("/pypi/cookiecutter/contributors", func(w http.ResponseWriter, r *http.Request) {
+		if method := "GET"; method != r.Method {
+			t.Errorf("expected HTTP %v request, got %v", method, r.Method)
 		}
+		if perPage := r.URL.Query().Get("per_page"); perPage != "10" {
+			t.Errorf("expected per_page 10, got %v", perPage)
+		}
+		fmt.Fprintf(w, `[{"login":"hackebrot"},{"login":"audreyr"}]`)
+	})
 
-		fmt.Fprintf(w, `[
-			{
-				"name":"pytest-cookies",
-				"keywords": ["testing", "python", "cookiecutter"]
-			},
-			{
-				"name":"pytest",
-				"keywords": ["testing", "python"]
-			}
-		]`)
+	users, _, err := client.Projects.Contributors(context.Background(), "pypi", "cookiecutter", &ListOptions{PerPage: 10})
+	if err != nil {
+		t.Fatalf("Contributors returned unexpected error: %v", err)
+	}
+
+	want := []*User{{Login: String("hackebrot")}, {Login: String("audreyr")}}
+
+	if !reflect.DeepEqual(users, want) {
+		t.Errorf("\nExpected %v\nGot %v", repr.Repr(want), repr.Repr(users))
+	}
+}
+
+func TestProjectsGet(t *testing.T) {
+	server, mux, url := startNewServer()
+	client := NewClient(APIKey)
+	client.BaseURL = url
+	defer server.Close()
+
+	mux.HandleFunc("/pypi/cookiecutter", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"name":"cookiecutter"}`)
 	})
 
-	projects, _, err := client.Search(context.Background(), "pytest")
+	project, _, err := client.Projects.Get(context.Background(), "pypi", "cookiecutter")
+	if err != nil {
+		t.Fatalf("Projects.Get returned unexpected error: %v", err)
+	}
+
+	want := &Project{Name: String("cookiecutter")}
+
+	if !reflect.DeepEqual(project, want) {
+		t.Errorf("\nExpected %v\nGot %v", repr.Repr(want), repr.Repr(project))
+	}
+}
+
+func TestSourceRank(t *testing.T) {
+	server, mux, url := startNewServer()
+	client := NewClient(APIKey)
+	client.BaseURL = url
+	defer server.Close()
 
+	mux.HandleFunc("/pypi/cookiecutter/sourcerank", func(w http.ResponseWriter, r *http.Request) {
+		if method := "GET"; method != r.Method {
+			t.Errorf("expected HTTP %v request, got %v", method, r.Method)
+		}
+		fmt.Fprintf(w, `{"basic_info_present":1,"stars":7,"is_deprecated":0}`)
+	})
+
+	rank, _, err := client.SourceRank(context.Background(), "pypi", "cookiecutter")
 	if err != nil {
-		t.Fatalf("Search returned unexpected error: %v", err)
+		t.Fatalf("SourceRank returned unexpected error: %v", err)
 	}
 
-	want := []*Project{
-		{
-			Name: String("pytest-cookies"),
-			Keywords: []*string{
-				String("testing"),
-				String("python"),
-				String("cookiecutter"),
-			},
-		},
-		{
-			Name: String("pytest"),
-			Keywords: []*string{
-				String("testing"),
-				String("python"),
-			},
-		},
+	want := &SourceRank{
+		BasicInfoPresent: Int(1),
+		Stars:            Int(7),
+		IsDeprecated:     Int(0),
 	}
 
-	if !reflect.DeepEqual(projects, want) {
-		t.Errorf("\nExpected %v\nGot %v", repr.Repr(want), repr.Repr(projects))
+	if !reflect.DeepEqual(rank, want) {
+		t.Errorf("\nExpected %v\nGot %v", repr.Repr(want), repr.Repr(rank))
 	}
 }
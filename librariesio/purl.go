@@ -0,0 +1,257 @@
+package librariesio
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// purlTypes maps libraries.io platforms to package URL types
+var purlTypes = map[string]string{
+	"cargo":     "cargo",
+	"cocoapods": "cocoapods",
+	"conda":     "conda",
+	"cran":      "cran",
+	"go":        "golang",
+	"hackage":   "hackage",
+	"hex":       "hex",
+	"maven":     "maven",
+	"npm":       "npm",
+	"nuget":     "nuget",
+	"packagist": "composer",
+	"pub":       "pub",
+	"pypi":      "pypi",
+	"rubygems":  "gem",
+	"swiftpm":   "swift",
+}
+
+// purlPlatforms maps package URL types to libraries.io platforms
+var purlPlatforms = make(map[string]string)
+
+func init() {
+	for plat, typ := range purlTypes {
+		purlPlatforms[typ] = plat
+	}
+}
+
+// PURL is a package URL as specified at https://github.com/package-url/purl-spec
+//
+// Namespace, Name and Version hold the decoded values, e.g. "@babel" as
+// namespace of pkg:npm/%40babel/core@7.0.0
+type PURL struct {
+	Type       string
+	Namespace  string
+	Name       string
+	Version    string
+	Qualifiers map[string]string
+	Subpath    string
+}
+
+// NewPURL returns the package URL for a project on libraries.io. The version
+// may be empty. An error is returned for platforms without a package URL
+// type.
+func NewPURL(plat, name, version string) (*PURL, error) {
+	typ, ok := purlTypes[strings.ToLower(plat)]
+	if !ok {
+		return nil, fmt.Errorf("no package URL type for platform %q", plat)
+	}
+	if name == "" {
+		return nil, fmt.Errorf("missing project name")
+	}
+
+	p := &PURL{Type: typ, Name: name, Version: version}
+
+	switch typ {
+	case "maven":
+		// libraries.io names Maven artifacts "groupId:artifactId"
+		if i := strings.Index(name, ":"); i >= 0 {
+			p.Namespace, p.Name = name[:i], name[i+1:]
+		}
+	default:
+		// Scoped npm packages, Go modules, Packagist vendors and so on
+		if i := strings.LastIndex(name, "/"); i >= 0 {
+			p.Namespace, p.Name = name[:i], name[i+1:]
+		}
+	}
+
+	p.normalize()
+	return p, nil
+}
+
+// ParsePURL parses a package URL such as pkg:npm/%40babel/core@7.0.0
+func ParsePURL(s string) (*PURL, error) {
+	rest := s
+	p := &PURL{}
+
+	if i := strings.LastIndex(rest, "#"); i >= 0 {
+		p.Subpath = strings.Trim(rest[i+1:], "/")
+		rest = rest[:i]
+	}
+
+	if i := strings.LastIndex(rest, "?"); i >= 0 {
+		query, err := url.ParseQuery(rest[i+1:])
+		if err != nil {
+			return nil, fmt.Errorf("invalid package URL %q: %v", s, err)
+		}
+		p.Qualifiers = make(map[string]string)
+		for key, values := range query {
+			p.Qualifiers[strings.ToLower(key)] = values[0]
+		}
+		rest = rest[:i]
+	}
+
+	if !strings.HasPrefix(rest, "pkg:") {
+		return nil, fmt.Errorf("invalid package URL %q: missing pkg scheme", s)
+	}
+	rest = strings.TrimLeft(rest[len("pkg:"):], "/")
+
+	i := strings.Index(rest, "/")
+	if i <= 0 {
+		return nil, fmt.Errorf("invalid package URL %q: missing type", s)
+	}
+	p.Type, rest = strings.ToLower(rest[:i]), strings.Trim(rest[i+1:], "/")
+
+	// The version follows the last @ of the name, but scoped npm packages
+	// may use an unencoded @ in the namespace
+	if i := strings.LastIndex(rest, "@"); i > strings.LastIndex(rest, "/") {
+		version, err := url.PathUnescape(rest[i+1:])
+		if err != nil {
+			return nil, fmt.Errorf("invalid package URL %q: %v", s, err)
+		}
+		p.Version, rest = version, rest[:i]
+	}
+
+	segments := strings.Split(rest, "/")
+	for i, segment := range segments {
+		decoded, err := url.PathUnescape(segment)
+		if err != nil {
+			return nil, fmt.Errorf("invalid package URL %q: %v", s, err)
+		}
+		segments[i] = decoded
+	}
+
+	p.Name = segments[len(segments)-1]
+	p.Namespace = strings.Join(segments[:len(segments)-1], "/")
+	if p.Name == "" {
+		return nil, fmt.Errorf("invalid package URL %q: missing name", s)
+	}
+
+	p.normalize()
+	return p, nil
+}
+
+// normalize applies the type specific rules of the purl spec
+func (p *PURL) normalize() {
+	if p.Type == "pypi" {
+		p.Name = strings.Replace(strings.ToLower(p.Name), "_", "-", -1)
+	}
+}
+
+// String returns the canonical form of the package URL
+func (p *PURL) String() string {
+	s := "pkg:" + p.Type + "/"
+
+	if p.Namespace != "" {
+		segments := strings.Split(p.Namespace, "/")
+		for i, segment := range segments {
+			segments[i] = escapePURL(segment)
+		}
+		s += strings.Join(segments, "/") + "/"
+	}
+	s += escapePURL(p.Name)
+
+	if p.Version != "" {
+		s += "@" + escapePURL(p.Version)
+	}
+
+	if len(p.Qualifiers) > 0 {
+		keys := make([]string, 0, len(p.Qualifiers))
+		for key := range p.Qualifiers {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		pairs := make([]string, len(keys))
+		for i, key := range keys {
+			pairs[i] = key + "=" + escapePURL(p.Qualifiers[key])
+		}
+		s += "?" + strings.Join(pairs, "&")
+	}
+
+	if p.Subpath != "" {
+		s += "#" + p.Subpath
+	}
+	return s
+}
+
+// ProjectRef returns the libraries.io platform and name of the package.
+// An error is returned for types which are not available on libraries.io.
+func (p *PURL) ProjectRef() (ProjectRef, error) {
+	plat, ok := purlPlatforms[p.Type]
+	if !ok {
+		return ProjectRef{}, fmt.Errorf("no libraries.io platform for package URL type %q", p.Type)
+	}
+
+	name := p.Name
+	if p.Namespace != "" {
+		sep := "/"
+		if p.Type == "maven" {
+			sep = ":"
+		}
+		name = p.Namespace + sep + name
+	}
+
+	return ProjectRef{Platform: plat, Name: name}, nil
+}
+
+// escapePURL percent-encodes a segment of a package URL
+func escapePURL(s string) string {
+	return strings.Replace(url.PathEscape(s), "@", "%40", -1)
+}
+
+// PURL returns the package URL for the project. The version of the package
+// URL is empty, set PURL.Version to refer to a specific release.
+func (p *Project) PURL() (*PURL, error) {
+	if p.Platform == nil || p.Name == nil {
+		return nil, fmt.Errorf("project is missing platform or name")
+	}
+	return NewPURL(*p.Platform, *p.Name, "")
+}
+
+// PURL returns the package URL for the dependency. The version of the
+// package URL is empty, as dependencies only hold requirements.
+func (d *ProjectDependency) PURL() (*PURL, error) {
+	if d.Platform == nil || d.Name == nil {
+		return nil, fmt.Errorf("dependency is missing platform or name")
+	}
+	return NewPURL(*d.Platform, *d.Name, "")
+}
+
+// GetByPURL returns information about the project for the given package
+// URL. The version of the package URL is ignored.
+//
+// GET https://libraries.io/api/:platform/:name
+func (s *ProjectsService) GetByPURL(ctx context.Context, purl string) (*Project, *http.Response, error) {
+	p, err := ParsePURL(purl)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ref, err := p.ProjectRef()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return s.Get(ctx, ref.Platform, ref.Name)
+}
+
+// ProjectByPURL returns information about the project for the given
+// package URL.
+//
+// Deprecated: Use Client.Projects.GetByPURL instead.
+func (c *Client) ProjectByPURL(ctx context.Context, purl string) (*Project, *http.Response, error) {
+	return c.Projects.GetByPURL(ctx, purl)
+}
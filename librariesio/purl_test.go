@@ -0,0 +1,182 @@
+package librariesio
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hackebrot/go-repr/repr"
+)
+
+func TestNewPURL(t *testing.T) {
+	testCases := []struct {
+		plat, name, version string
+		want                string
+	}{
+		{"npm", "@babel/core", "7.0.0", "pkg:npm/%40babel/core@7.0.0"},
+		{"Pypi", "Django_Rest", "3.0", "pkg:pypi/django-rest@3.0"},
+		{"maven", "junit:junit", "4.12", "pkg:maven/junit/junit@4.12"},
+		{"Go", "github.com/hackebrot/go-repr", "v0.1.0", "pkg:golang/github.com/hackebrot/go-repr@v0.1.0"},
+		{"packagist", "monolog/monolog", "", "pkg:composer/monolog/monolog"},
+		{"rubygems", "rails", "7.0.0", "pkg:gem/rails@7.0.0"},
+	}
+
+	for _, testCase := range testCases {
+		p, err := NewPURL(testCase.plat, testCase.name, testCase.version)
+		if err != nil {
+			t.Fatalf("NewPURL returned unexpected error: %v", err)
+		}
+		if got := p.String(); got != testCase.want {
+			t.Errorf("NewPURL(%q, %q, %q) returned %q, want %q", testCase.plat, testCase.name, testCase.version, got, testCase.want)
+		}
+	}
+}
+
+func TestNewPURL_unknownPlatform(t *testing.T) {
+	if _, err := NewPURL("bower", "jquery", ""); err == nil {
+		t.Fatal("Expected error to be returned")
+	}
+}
+
+func TestParsePURL(t *testing.T) {
+	testCases := []struct {
+		purl string
+		want *PURL
+		ref  ProjectRef
+	}{
+		{
+			purl: "pkg:npm/%40babel/core@7.0.0",
+			want: &PURL{Type: "npm", Namespace: "@babel", Name: "core", Version: "7.0.0"},
+			ref:  ProjectRef{Platform: "npm", Name: "@babel/core"},
+		},
+		{
+			purl: "pkg:npm/@babel/core",
+			want: &PURL{Type: "npm", Namespace: "@babel", Name: "core"},
+			ref:  ProjectRef{Platform: "npm", Name: "@babel/core"},
+		},
+		{
+			purl: "pkg:maven/org.apache.commons/commons-lang3@3.12.0?type=jar",
+			want: &PURL{
+				Type:       "maven",
+				Namespace:  "org.apache.commons",
+				Name:       "commons-lang3",
+				Version:    "3.12.0",
+				Qualifiers: map[string]string{"type": "jar"},
+			},
+			ref: ProjectRef{Platform: "maven", Name: "org.apache.commons:commons-lang3"},
+		},
+		{
+			purl: "pkg:PyPI/Django_Rest@1.0#src/",
+			want: &PURL{Type: "pypi", Name: "django-rest", Version: "1.0", Subpath: "src"},
+			ref:  ProjectRef{Platform: "pypi", Name: "django-rest"},
+		},
+		{
+			purl: "pkg:golang/github.com/hackebrot/go-repr@v0.1.0",
+			want: &PURL{Type: "golang", Namespace: "github.com/hackebrot", Name: "go-repr", Version: "v0.1.0"},
+			ref:  ProjectRef{Platform: "go", Name: "github.com/hackebrot/go-repr"},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.purl, func(t *testing.T) {
+			p, err := ParsePURL(testCase.purl)
+			if err != nil {
+				t.Fatalf("ParsePURL returned unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(p, testCase.want) {
+				t.Errorf("\nExpected %v\nGot %v", repr.Repr(testCase.want), repr.Repr(p))
+			}
+
+			ref, err := p.ProjectRef()
+			if err != nil {
+				t.Fatalf("ProjectRef returned unexpected error: %v", err)
+			}
+			if ref != testCase.ref {
+				t.Errorf("ProjectRef returned %+v, want %+v", ref, testCase.ref)
+			}
+		})
+	}
+}
+
+func TestParsePURL_invalid(t *testing.T) {
+	for _, s := range []string{"npm/chalk", "pkg:chalk", "pkg:npm/", "pkg:npm/%zz"} {
+		if _, err := ParsePURL(s); err == nil {
+			t.Errorf("Expected error for %q", s)
+		}
+	}
+}
+
+func TestPURLString_roundTrip(t *testing.T) {
+	s := "pkg:maven/org.apache.commons/commons-lang3@3.12.0?classifier=sources&type=jar#src/main"
+
+	p, err := ParsePURL(s)
+	if err != nil {
+		t.Fatalf("ParsePURL returned unexpected error: %v", err)
+	}
+	if got := p.String(); got != s {
+		t.Errorf("String returned %q, want %q", got, s)
+	}
+}
+
+func TestProjectPURL(t *testing.T) {
+	project := &Project{Platform: String("NPM"), Name: String("@babel/core")}
+
+	p, err := project.PURL()
+	if err != nil {
+		t.Fatalf("PURL returned unexpected error: %v", err)
+	}
+	if got, want := p.String(), "pkg:npm/%40babel/core"; got != want {
+		t.Errorf("PURL returned %q, want %q", got, want)
+	}
+
+	if _, err := new(Project).PURL(); err == nil {
+		t.Error("Expected error for project without platform")
+	}
+}
+
+func TestProjectDependencyPURL(t *testing.T) {
+	dep := &ProjectDependency{Platform: String("Maven"), Name: String("junit:junit")}
+
+	p, err := dep.PURL()
+	if err != nil {
+		t.Fatalf("PURL returned unexpected error: %v", err)
+	}
+	if got, want := p.String(), "pkg:maven/junit/junit"; got != want {
+		t.Errorf("PURL returned %q, want %q", got, want)
+	}
+}
+
+func TestProjectByPURL(t *testing.T) {
+	server, mux, url := startNewServer()
+	client := NewClient(APIKey)
+	client.BaseURL = url
+	defer server.Close()
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if uri := r.RequestURI; !strings.HasPrefix(uri, "/npm/@babel%2Fcore?") {
+			t.Errorf("unexpected request URI, got %v", uri)
+		}
+		fmt.Fprintf(w, `{"name":"@babel/core"}`)
+	})
+
+	project, _, err := client.ProjectByPURL(context.Background(), "pkg:npm/%40babel/core@7.0.0")
+	if err != nil {
+		t.Fatalf("ProjectByPURL returned unexpected error: %v", err)
+	}
+
+	want := &Project{Name: String("@babel/core")}
+	if !reflect.DeepEqual(project, want) {
+		t.Errorf("\nExpected %v\nGot %v", repr.Repr(want), repr.Repr(project))
+	}
+}
+
+func TestProjectByPURL_unsupportedType(t *testing.T) {
+	client := NewClient(APIKey)
+
+	if _, _, err := client.ProjectByPURL(context.Background(), "pkg:docker/library/alpine"); err == nil {
+		t.Fatal("Expected error to be returned")
+	}
+}
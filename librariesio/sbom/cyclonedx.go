@@ -0,0 +1,257 @@
+/*
+Package sbom generates CycloneDX software bills of materials for dependency
+graphs resolved with the depgraph package.
+
+Every project of the graph becomes a component with its version, licenses,
+package URL and links to the homepage and package manager. The dependency
+relationships of the graph are preserved and, if Options.Name is set, the
+root dependencies are attached to a metadata component describing the
+application itself.
+*/
+package sbom
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/hackebrot/go-librariesio/librariesio"
+	"github.com/hackebrot/go-librariesio/librariesio/depgraph"
+	"github.com/hackebrot/go-librariesio/librariesio/internal/uuid"
+	"github.com/hackebrot/go-librariesio/librariesio/license"
+)
+
+const (
+	specVersion = "1.5"
+	toolName    = "go-librariesio"
+	toolVersion = "1"
+)
+
+// BOM is a CycloneDX 1.5 bill of materials
+type BOM struct {
+	BOMFormat    string        `json:"bomFormat"`
+	SpecVersion  string        `json:"specVersion"`
+	SerialNumber string        `json:"serialNumber"`
+	Version      int           `json:"version"`
+	Metadata     *Metadata     `json:"metadata"`
+	Components   []*Component  `json:"components"`
+	Dependencies []*Dependency `json:"dependencies"`
+}
+
+// Metadata describes the BOM itself
+type Metadata struct {
+	Timestamp time.Time  `json:"timestamp"`
+	Tools     *Tools     `json:"tools"`
+	Component *Component `json:"component,omitempty"`
+}
+
+// Tools lists the tools used to create the BOM
+type Tools struct {
+	Components []*Component `json:"components"`
+}
+
+// Component is a software component, i.e. a project of the graph
+type Component struct {
+	Type               string               `json:"type"`
+	BOMRef             string               `json:"bom-ref,omitempty"`
+	Group              string               `json:"group,omitempty"`
+	Name               string               `json:"name"`
+	Version            string               `json:"version,omitempty"`
+	Description        string               `json:"description,omitempty"`
+	Scope              string               `json:"scope,omitempty"`
+	Licenses           []*LicenseChoice     `json:"licenses,omitempty"`
+	PURL               string               `json:"purl,omitempty"`
+	ExternalReferences []*ExternalReference `json:"externalReferences,omitempty"`
+}
+
+// LicenseChoice holds either a single license or a SPDX expression
+type LicenseChoice struct {
+	License    *License `json:"license,omitempty"`
+	Expression string   `json:"expression,omitempty"`
+}
+
+// License is a license identified by its SPDX id or, for licenses which are
+// not on the SPDX license list, by its name
+type License struct {
+	ID   string `json:"id,omitempty"`
+	Name string `json:"name,omitempty"`
+}
+
+// ExternalReference links to resources of a component
+type ExternalReference struct {
+	Type string `json:"type"`
+	URL  string `json:"url"`
+}
+
+// Dependency lists the components a component depends on
+type Dependency struct {
+	Ref       string   `json:"ref"`
+	DependsOn []string `json:"dependsOn"`
+}
+
+// Options configure the generated BOM
+type Options struct {
+	// Name and Version of the application described by the BOM. If Name is
+	// empty, no metadata component is added.
+	Name    string
+	Version string
+
+	// SerialNumber defaults to a random urn:uuid
+	SerialNumber string
+
+	// Timestamp defaults to the current time
+	Timestamp time.Time
+}
+
+// New returns the BOM for the given dependency graph
+func New(g *depgraph.Graph, opt *Options) (*BOM, error) {
+	if opt == nil {
+		opt = &Options{}
+	}
+
+	bom := &BOM{
+		BOMFormat:    "CycloneDX",
+		SpecVersion:  specVersion,
+		SerialNumber: opt.SerialNumber,
+		Version:      1,
+		Metadata: &Metadata{
+			Timestamp: opt.Timestamp,
+			Tools: &Tools{
+				Components: []*Component{
+					{Type: "application", Name: toolName, Version: toolVersion},
+				},
+			},
+		},
+		Components:   []*Component{},
+		Dependencies: []*Dependency{},
+	}
+
+	if bom.SerialNumber == "" {
+		id, err := uuid.New()
+		if err != nil {
+			return nil, err
+		}
+		bom.SerialNumber = "urn:uuid:" + id
+	}
+	if bom.Metadata.Timestamp.IsZero() {
+		bom.Metadata.Timestamp = time.Now().UTC().Truncate(time.Second)
+	}
+
+	refs := make(map[*depgraph.Node]string)
+	for _, node := range g.Nodes() {
+		component := newComponent(node)
+		refs[node] = component.BOMRef
+		bom.Components = append(bom.Components, component)
+	}
+
+	if opt.Name != "" {
+		app := &Component{
+			Type:    "application",
+			BOMRef:  opt.Name,
+			Name:    opt.Name,
+			Version: opt.Version,
+		}
+		bom.Metadata.Component = app
+
+		dep := &Dependency{Ref: app.BOMRef, DependsOn: []string{}}
+		for _, root := range g.Roots {
+			dep.DependsOn = append(dep.DependsOn, refs[root])
+		}
+		bom.Dependencies = append(bom.Dependencies, dep)
+	}
+
+	for _, node := range g.Nodes() {
+		dep := &Dependency{Ref: refs[node], DependsOn: []string{}}
+		for _, edge := range node.Deps {
+			dep.DependsOn = append(dep.DependsOn, refs[edge.Node])
+		}
+		bom.Dependencies = append(bom.Dependencies, dep)
+	}
+
+	return bom, nil
+}
+
+// newComponent returns the Component for a node of the graph
+func newComponent(node *depgraph.Node) *Component {
+	version := node.ResolvedVersion()
+
+	group, name := splitGroup(node.Platform, node.Name)
+
+	component := &Component{
+		Type:    "library",
+		Group:   group,
+		Name:    name,
+		Version: version,
+		Scope:   "required",
+	}
+
+	if purl, err := librariesio.NewPURL(node.Platform, node.Name, version); err == nil {
+		component.PURL = purl.String()
+		component.BOMRef = component.PURL
+	} else {
+		component.BOMRef = node.Platform + "/" + node.Name
+		if version != "" {
+			component.BOMRef += "@" + version
+		}
+	}
+
+	project := node.Project
+	if project == nil {
+		return component
+	}
+
+	if project.Description != nil {
+		component.Description = *project.Description
+	}
+
+	if expr, ok := license.ProjectExpression(project); ok {
+		if l, ok := expr.(*license.License); ok && !l.OrLater && l.Exception == "" {
+			if license.IsSPDX(l.ID) {
+				component.Licenses = []*LicenseChoice{{License: &License{ID: l.ID}}}
+			} else {
+				component.Licenses = []*LicenseChoice{{License: &License{Name: l.ID}}}
+			}
+		} else {
+			component.Licenses = []*LicenseChoice{{Expression: license.WithRefs(expr).String()}}
+		}
+	}
+
+	if project.Homepage != nil && *project.Homepage != "" {
+		component.ExternalReferences = append(component.ExternalReferences, &ExternalReference{
+			Type: "website",
+			URL:  *project.Homepage,
+		})
+	}
+	if project.PackageManagerURL != nil && *project.PackageManagerURL != "" {
+		component.ExternalReferences = append(component.ExternalReferences, &ExternalReference{
+			Type: "distribution",
+			URL:  *project.PackageManagerURL,
+		})
+	}
+
+	return component
+}
+
+// splitGroup splits the group from the name of Maven artifacts and scoped
+// npm packages
+func splitGroup(plat, name string) (string, string) {
+	switch strings.ToLower(plat) {
+	case "maven":
+		if i := strings.Index(name, ":"); i >= 0 {
+			return name[:i], name[i+1:]
+		}
+	case "npm":
+		if i := strings.Index(name, "/"); i >= 0 && strings.HasPrefix(name, "@") {
+			return name[:i], name[i+1:]
+		}
+	}
+	return "", name
+}
+
+// WriteJSON writes the BOM in the CycloneDX JSON format
+func (b *BOM) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(b)
+}
@@ -0,0 +1,200 @@
+package sbom
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hackebrot/go-librariesio/librariesio"
+	"github.com/hackebrot/go-librariesio/librariesio/depgraph"
+	"github.com/hackebrot/go-librariesio/librariesio/manifest"
+)
+
+var timestamp = time.Date(2017, time.June, 1, 12, 0, 0, 0, time.UTC)
+
+// resolveGraph returns the graph for npm/ava, which depends on
+// npm/@ava/babel-preset and npm/chalk
+func resolveGraph(t *testing.T) *depgraph.Graph {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client := librariesio.NewClient("1234")
+	client.BaseURL, _ = url.Parse(server.URL + "/")
+
+	mux.HandleFunc("/npm/ava/0.19.0/dependencies", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{
+			"name": "ava",
+			"description": "Futuristic test runner",
+			"homepage": "https://ava.li",
+			"package_manager_url": "https://www.npmjs.com/package/ava",
+			"normalized_licenses": ["MIT"],
+			"dependencies": [{"name": "@ava/babel-preset"}, {"name": "chalk"}]
+		}`)
+	})
+	// ServeMux matches escaped slashes differently depending on the Go
+	// version, so the scoped package is matched on the escaped path
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.EscapedPath() != "/npm/@ava%2Fbabel-preset/latest/dependencies" {
+			http.Error(w, `{"error":"Not Found"}`, http.StatusNotFound)
+			return
+		}
+		fmt.Fprint(w, `{
+			"name": "@ava/babel-preset",
+			"latest_release_number": "1.2.0",
+			"normalized_licenses": ["MIT", "Apache-2.0"]
+		}`)
+	})
+
+	roots := []manifest.Dependency{{
+		ProjectRef:  librariesio.ProjectRef{Platform: "npm", Name: "ava"},
+		Requirement: "0.19.0",
+	}}
+	g, err := depgraph.Resolve(context.Background(), client.Projects, roots, &depgraph.Options{MaxDepth: -1})
+	if err != nil {
+		t.Fatalf("Resolve returned unexpected error: %v", err)
+	}
+	return g
+}
+
+func TestNew(t *testing.T) {
+	g := resolveGraph(t)
+
+	bom, err := New(g, &Options{Name: "example", Version: "1.0.0", Timestamp: timestamp})
+	if err != nil {
+		t.Fatalf("New returned unexpected error: %v", err)
+	}
+
+	if !strings.HasPrefix(bom.SerialNumber, "urn:uuid:") || len(bom.SerialNumber) != 45 {
+		t.Errorf("unexpected serial number %q", bom.SerialNumber)
+	}
+
+	wantComponents := []*Component{
+		{
+			Type:        "library",
+			BOMRef:      "pkg:npm/ava@0.19.0",
+			Name:        "ava",
+			Version:     "0.19.0",
+			Description: "Futuristic test runner",
+			Scope:       "required",
+			Licenses:    []*LicenseChoice{{License: &License{ID: "MIT"}}},
+			PURL:        "pkg:npm/ava@0.19.0",
+			ExternalReferences: []*ExternalReference{
+				{Type: "website", URL: "https://ava.li"},
+				{Type: "distribution", URL: "https://www.npmjs.com/package/ava"},
+			},
+		},
+		{
+			Type:     "library",
+			BOMRef:   "pkg:npm/%40ava/babel-preset@1.2.0",
+			Group:    "@ava",
+			Name:     "babel-preset",
+			Version:  "1.2.0",
+			Scope:    "required",
+			Licenses: []*LicenseChoice{{Expression: "MIT AND Apache-2.0"}},
+			PURL:     "pkg:npm/%40ava/babel-preset@1.2.0",
+		},
+		{
+			Type:   "library",
+			BOMRef: "pkg:npm/chalk",
+			Name:   "chalk",
+			Scope:  "required",
+			PURL:   "pkg:npm/chalk",
+		},
+	}
+
+	for i, want := range wantComponents {
+		if got := bom.Components[i]; !reflect.DeepEqual(got, want) {
+			t.Errorf("\nExpected %+v\nGot %+v", want, got)
+		}
+	}
+
+	wantDependencies := []*Dependency{
+		{Ref: "example", DependsOn: []string{"pkg:npm/ava@0.19.0"}},
+		{Ref: "pkg:npm/ava@0.19.0", DependsOn: []string{"pkg:npm/%40ava/babel-preset@1.2.0", "pkg:npm/chalk"}},
+		{Ref: "pkg:npm/%40ava/babel-preset@1.2.0", DependsOn: []string{}},
+		{Ref: "pkg:npm/chalk", DependsOn: []string{}},
+	}
+	if !reflect.DeepEqual(bom.Dependencies, wantDependencies) {
+		t.Errorf("\nExpected %+v\nGot %+v", wantDependencies, bom.Dependencies)
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	bom, err := New(resolveGraph(t), &Options{SerialNumber: "urn:uuid:1", Timestamp: timestamp})
+	if err != nil {
+		t.Fatalf("New returned unexpected error: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := bom.WriteJSON(&buf); err != nil {
+		t.Fatalf("WriteJSON returned unexpected error: %v", err)
+	}
+
+	var doc map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for key, want := range map[string]interface{}{
+		"bomFormat":    "CycloneDX",
+		"specVersion":  "1.5",
+		"serialNumber": "urn:uuid:1",
+		"version":      float64(1),
+	} {
+		if got := doc[key]; got != want {
+			t.Errorf("%v is %v, want %v", key, got, want)
+		}
+	}
+
+	metadata := doc["metadata"].(map[string]interface{})
+	if got, want := metadata["timestamp"], "2017-06-01T12:00:00Z"; got != want {
+		t.Errorf("timestamp is %v, want %v", got, want)
+	}
+	if _, ok := metadata["component"]; ok {
+		t.Errorf("unexpected metadata component without Options.Name")
+	}
+}
+
+func TestNewComponent_licenses(t *testing.T) {
+	testCases := []struct {
+		licenses []string
+		want     []*LicenseChoice
+	}{
+		{
+			licenses: []string{"MIT"},
+			want:     []*LicenseChoice{{License: &License{ID: "MIT"}}},
+		},
+		{
+			licenses: []string{"Other"},
+			want:     []*LicenseChoice{{License: &License{Name: "Other"}}},
+		},
+		{
+			licenses: []string{"MIT", "Other"},
+			want:     []*LicenseChoice{{Expression: "MIT AND LicenseRef-Other"}},
+		},
+	}
+
+	for _, testCase := range testCases {
+		project := &librariesio.Project{}
+		for _, id := range testCase.licenses {
+			project.NormalizedLicenses = append(project.NormalizedLicenses, librariesio.String(id))
+		}
+		node := &depgraph.Node{
+			ProjectRef: librariesio.ProjectRef{Platform: "npm", Name: "chalk"},
+			Project:    project,
+		}
+
+		if got := newComponent(node).Licenses; !reflect.DeepEqual(got, testCase.want) {
+			t.Errorf("\nExpected %+v\nGot %+v", testCase.want, got)
+		}
+	}
+}
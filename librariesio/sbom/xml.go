@@ -0,0 +1,136 @@
+package sbom
+
+import (
+	"encoding/xml"
+	"io"
+	"time"
+)
+
+const xmlNamespace = "http://cyclonedx.org/schema/bom/1.5"
+
+type xmlBOM struct {
+	XMLName      xml.Name         `xml:"bom"`
+	XMLNS        string           `xml:"xmlns,attr"`
+	SerialNumber string           `xml:"serialNumber,attr"`
+	Version      int              `xml:"version,attr"`
+	Metadata     xmlMetadata      `xml:"metadata"`
+	Components   []*xmlComponent  `xml:"components>component"`
+	Dependencies []*xmlDependency `xml:"dependencies>dependency"`
+}
+
+type xmlMetadata struct {
+	Timestamp string          `xml:"timestamp"`
+	Tools     []*xmlComponent `xml:"tools>components>component"`
+	Component *xmlComponent   `xml:"component,omitempty"`
+}
+
+type xmlComponent struct {
+	Type               string                  `xml:"type,attr"`
+	BOMRef             string                  `xml:"bom-ref,attr,omitempty"`
+	Group              string                  `xml:"group,omitempty"`
+	Name               string                  `xml:"name"`
+	Version            string                  `xml:"version,omitempty"`
+	Description        string                  `xml:"description,omitempty"`
+	Scope              string                  `xml:"scope,omitempty"`
+	Licenses           *xmlLicenses            `xml:"licenses,omitempty"`
+	PURL               string                  `xml:"purl,omitempty"`
+	ExternalReferences []*xmlExternalReference `xml:"externalReferences>reference,omitempty"`
+}
+
+type xmlLicenses struct {
+	Licenses   []*xmlLicense `xml:"license,omitempty"`
+	Expression string        `xml:"expression,omitempty"`
+}
+
+type xmlLicense struct {
+	ID   string `xml:"id,omitempty"`
+	Name string `xml:"name,omitempty"`
+}
+
+type xmlExternalReference struct {
+	Type string `xml:"type,attr"`
+	URL  string `xml:"url"`
+}
+
+type xmlDependency struct {
+	Ref       string           `xml:"ref,attr"`
+	DependsOn []*xmlDependency `xml:"dependency,omitempty"`
+}
+
+// WriteXML writes the BOM in the CycloneDX XML format
+func (b *BOM) WriteXML(w io.Writer) error {
+	doc := &xmlBOM{
+		XMLNS:        xmlNamespace,
+		SerialNumber: b.SerialNumber,
+		Version:      b.Version,
+	}
+
+	if b.Metadata != nil {
+		doc.Metadata.Timestamp = b.Metadata.Timestamp.Format(time.RFC3339)
+		if b.Metadata.Tools != nil {
+			for _, c := range b.Metadata.Tools.Components {
+				doc.Metadata.Tools = append(doc.Metadata.Tools, toXMLComponent(c))
+			}
+		}
+		if b.Metadata.Component != nil {
+			doc.Metadata.Component = toXMLComponent(b.Metadata.Component)
+		}
+	}
+
+	for _, c := range b.Components {
+		doc.Components = append(doc.Components, toXMLComponent(c))
+	}
+
+	for _, d := range b.Dependencies {
+		dep := &xmlDependency{Ref: d.Ref}
+		for _, ref := range d.DependsOn {
+			dep.DependsOn = append(dep.DependsOn, &xmlDependency{Ref: ref})
+		}
+		doc.Dependencies = append(doc.Dependencies, dep)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// toXMLComponent converts a Component to its XML representation
+func toXMLComponent(c *Component) *xmlComponent {
+	x := &xmlComponent{
+		Type:        c.Type,
+		BOMRef:      c.BOMRef,
+		Group:       c.Group,
+		Name:        c.Name,
+		Version:     c.Version,
+		Description: c.Description,
+		Scope:       c.Scope,
+		PURL:        c.PURL,
+	}
+
+	if len(c.Licenses) > 0 {
+		x.Licenses = &xmlLicenses{}
+		for _, choice := range c.Licenses {
+			if choice.License != nil {
+				x.Licenses.Licenses = append(x.Licenses.Licenses, &xmlLicense{ID: choice.License.ID, Name: choice.License.Name})
+			} else if choice.Expression != "" {
+				x.Licenses.Expression = choice.Expression
+			}
+		}
+	}
+
+	for _, ref := range c.ExternalReferences {
+		x.ExternalReferences = append(x.ExternalReferences, &xmlExternalReference{
+			Type: ref.Type,
+			URL:  ref.URL,
+		})
+	}
+	return x
+}
@@ -0,0 +1,63 @@
+package sbom
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+)
+
+func TestWriteXML(t *testing.T) {
+	bom, err := New(resolveGraph(t), &Options{
+		Name:         "example",
+		SerialNumber: "urn:uuid:1",
+		Timestamp:    timestamp,
+	})
+	if err != nil {
+		t.Fatalf("New returned unexpected error: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := bom.WriteXML(&buf); err != nil {
+		t.Fatalf("WriteXML returned unexpected error: %v", err)
+	}
+
+	out := buf.String()
+	for _, want := range []string{
+		`<?xml version="1.0" encoding="UTF-8"?>`,
+		`<bom xmlns="http://cyclonedx.org/schema/bom/1.5" serialNumber="urn:uuid:1" version="1">`,
+		`<timestamp>2017-06-01T12:00:00Z</timestamp>`,
+		`<component type="library" bom-ref="pkg:npm/ava@0.19.0">`,
+		`<license>`,
+		`<expression>MIT AND Apache-2.0</expression>`,
+		`<reference type="website">`,
+		`<dependency ref="example">`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("XML does not contain %q:\n%v", want, out)
+		}
+	}
+
+	var doc struct {
+		Components []struct {
+			Name string `xml:"name"`
+			PURL string `xml:"purl"`
+		} `xml:"components>component"`
+		Dependencies []struct {
+			Ref       string `xml:"ref,attr"`
+			DependsOn []struct {
+				Ref string `xml:"ref,attr"`
+			} `xml:"dependency"`
+		} `xml:"dependencies>dependency"`
+	}
+	if err := xml.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got, want := len(doc.Components), 3; got != want {
+		t.Errorf("Expected %d components, got %d", want, got)
+	}
+	if got, want := len(doc.Dependencies[1].DependsOn), 2; got != want {
+		t.Errorf("Expected %d dependencies of ava, got %d", want, got)
+	}
+}
@@ -0,0 +1,76 @@
+package librariesio
+
+import (
+	"context"
+	"net/http"
+)
+
+// SearchService handles the search endpoint of the API
+type SearchService service
+
+// SearchOptions specifies the optional parameters to the SearchService.Projects
+// method
+type SearchOptions struct {
+	// Sort is one of rank, stars, dependents_count, dependent_repos_count,
+	// latest_release_published_at, contributions_count or created_at
+	Sort string
+
+	// Filters as comma separated lists, e.g. "npm,pypi" for Platforms
+	Platforms string
+	Languages string
+	Licenses  string
+	Keywords  string
+
+	ListOptions
+}
+
+// Projects returns a slice of projects for the given search string
+//
+// GET https://libraries.io/api/search?q=amelia
+func (s *SearchService) Projects(ctx context.Context, q string, opt *SearchOptions) ([]*Project, *http.Response, error) {
+	request, err := s.client.NewRequest("GET", "search", nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Add query to request
+	query := request.URL.Query()
+	query.Set("q", q)
+
+	if opt != nil {
+		filters := map[string]string{
+			"sort":      opt.Sort,
+			"platforms": opt.Platforms,
+			"languages": opt.Languages,
+			"licenses":  opt.Licenses,
+			"keywords":  opt.Keywords,
+		}
+		for key, value := range filters {
+			if value != "" {
+				query.Set(key, value)
+			}
+		}
+	}
+	request.URL.RawQuery = query.Encode()
+
+	if opt != nil {
+		addListOptions(request, &opt.ListOptions)
+	}
+
+	var projects []*Project
+
+	response, err := s.client.Do(ctx, request, &projects)
+	if err != nil {
+		return nil, response, err
+	}
+
+	return projects, response, nil
+}
+
+// Search returns a slice of projects for the given search string
+//
+// Deprecated: Use Client.SearchService.Projects instead, which also supports
+// sorting, filters and pagination.
+func (c *Client) Search(ctx context.Context, q string) ([]*Project, *http.Response, error) {
+	return c.SearchService.Projects(ctx, q, nil)
+}
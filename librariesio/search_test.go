@@ -0,0 +1,106 @@
+package librariesio
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hackebrot/go-repr/repr"
+)
+
+func TestSearch(t *testing.T) {
+	server, mux, url := startNewServer()
+	client := NewClient(APIKey)
+	client.BaseURL = url
+	defer server.Close()
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if method := "GET"; method != r.Method {
+			t.Errorf("expected HTTP %v request, got %v", method, r.Method)
+		}
+
+		if url := r.URL.String(); !strings.Contains(url, "/search") {
+			t.Errorf("unexpected URL, got %v", url)
+		}
+
+		fmt.Fprintf(w, `[
+			{
+				"name":"pytest-cookies",
+				"keywords": ["testing", "python", "cookiecutter"]
+			},
+			{
+				"name":"pytest",
+				"keywords": ["testing", "python"]
+			}
+		]`)
+	})
+
+	projects, _, err := client.Search(context.Background(), "pytest")
+
+	if err != nil {
+		t.Fatalf("Search returned unexpected error: %v", err)
+	}
+
+	want := []*Project{
+		{
+			Name: String("pytest-cookies"),
+			Keywords: []*string{
+				String("testing"),
+				String("python"),
+				String("cookiecutter"),
+			},
+		},
+		{
+			Name: String("pytest"),
+			Keywords: []*string{
+				String("testing"),
+				String("python"),
+			},
+		},
+	}
+
+	if !reflect.DeepEqual(projects, want) {
+		t.Errorf("\nExpected %v\nGot %v", repr.Repr(want), repr.Repr(projects))
+	}
+}
+
+func TestSearch_options(t *testing.T) {
+	server, mux, url := startNewServer()
+	client := NewClient(APIKey)
+	client.BaseURL = url
+	defer server.Close()
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		want := map[string]string{
+			"q":         "pytest",
+			"platforms": "pypi",
+			"sort":      "stars",
+			"page":      "2",
+			"per_page":  "50",
+		}
+		for key, value := range want {
+			if got := query.Get(key); got != value {
+				t.Errorf("expected query parameter %v=%v, got %v", key, value, got)
+			}
+		}
+		if got := query.Get("languages"); got != "" {
+			t.Errorf("expected no languages query parameter, got %v", got)
+		}
+		fmt.Fprintf(w, `[]`)
+	})
+
+	opt := &SearchOptions{
+		Platforms:   "pypi",
+		Sort:        "stars",
+		ListOptions: ListOptions{Page: 2, PerPage: 50},
+	}
+
+	_, _, err := client.SearchService.Projects(context.Background(), "pytest", opt)
+	if err != nil {
+		t.Fatalf("Search returned unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,286 @@
+/*
+Package spdx exports dependency graphs resolved with the depgraph package as
+SPDX 2.3 documents in the tag-value and JSON formats.
+
+Every project of the graph becomes a package with its version, package URL,
+download location, homepage and the license declared by its normalized
+licenses on libraries.io. Dependencies are expressed as DEPENDS_ON relationships.
+*/
+package spdx
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/hackebrot/go-librariesio/librariesio"
+	"github.com/hackebrot/go-librariesio/librariesio/depgraph"
+	"github.com/hackebrot/go-librariesio/librariesio/internal/uuid"
+	"github.com/hackebrot/go-librariesio/librariesio/license"
+)
+
+const (
+	version     = "SPDX-2.3"
+	dataLicense = "CC0-1.0"
+	documentID  = "SPDXRef-DOCUMENT"
+	creator     = "Tool: go-librariesio-1"
+
+	// NoAssertion is used for values which are unknown
+	NoAssertion = "NOASSERTION"
+)
+
+// Document is a SPDX 2.3 document
+type Document struct {
+	SPDXVersion       string          `json:"spdxVersion"`
+	DataLicense       string          `json:"dataLicense"`
+	SPDXID            string          `json:"SPDXID"`
+	Name              string          `json:"name"`
+	DocumentNamespace string          `json:"documentNamespace"`
+	CreationInfo      *CreationInfo   `json:"creationInfo"`
+	Packages          []*Package      `json:"packages"`
+	Relationships     []*Relationship `json:"relationships"`
+
+	// HasExtractedLicensingInfos defines the LicenseRefs used for licenses
+	// which are not on the SPDX license list
+	HasExtractedLicensingInfos []*ExtractedLicensingInfo `json:"hasExtractedLicensingInfos,omitempty"`
+}
+
+// CreationInfo describes when and by whom the document was created
+type CreationInfo struct {
+	Created  time.Time `json:"created"`
+	Creators []string  `json:"creators"`
+}
+
+// Package is a project of the dependency graph
+type Package struct {
+	Name             string         `json:"name"`
+	SPDXID           string         `json:"SPDXID"`
+	VersionInfo      string         `json:"versionInfo,omitempty"`
+	DownloadLocation string         `json:"downloadLocation"`
+	FilesAnalyzed    bool           `json:"filesAnalyzed"`
+	Homepage         string         `json:"homepage,omitempty"`
+	LicenseConcluded string         `json:"licenseConcluded"`
+	LicenseDeclared  string         `json:"licenseDeclared"`
+	CopyrightText    string         `json:"copyrightText"`
+	Summary          string         `json:"summary,omitempty"`
+	ExternalRefs     []*ExternalRef `json:"externalRefs,omitempty"`
+}
+
+// ExternalRef refers to a package outside of the document, e.g. by its
+// package URL
+type ExternalRef struct {
+	ReferenceCategory string `json:"referenceCategory"`
+	ReferenceType     string `json:"referenceType"`
+	ReferenceLocator  string `json:"referenceLocator"`
+}
+
+// ExtractedLicensingInfo defines a LicenseRef. libraries.io only provides the
+// name of such licenses, which is used as their text.
+type ExtractedLicensingInfo struct {
+	LicenseID     string `json:"licenseId"`
+	ExtractedText string `json:"extractedText"`
+	Name          string `json:"name"`
+}
+
+// Relationship relates two elements of the document
+type Relationship struct {
+	SPDXElementID      string `json:"spdxElementId"`
+	RelationshipType   string `json:"relationshipType"`
+	RelatedSPDXElement string `json:"relatedSpdxElement"`
+}
+
+// Options configure the generated document
+type Options struct {
+	// Name of the document and of the application it describes. If Name is
+	// set, a package for the application is added which depends on the roots
+	// of the graph. Otherwise the document describes the roots directly.
+	Name    string
+	Version string
+
+	// Namespace is the unique URI of the document and defaults to a random
+	// URI below https://spdx.org/spdxdocs/
+	Namespace string
+
+	// Created defaults to the current time. It is written in UTC with a
+	// precision of seconds.
+	Created time.Time
+}
+
+var invalidIDChars = regexp.MustCompile(`[^A-Za-z0-9.\-]+`)
+
+// New returns the SPDX document for the dependency graph
+func New(g *depgraph.Graph, opt *Options) (*Document, error) {
+	if opt == nil {
+		opt = &Options{}
+	}
+
+	doc := &Document{
+		SPDXVersion:       version,
+		DataLicense:       dataLicense,
+		SPDXID:            documentID,
+		Name:              opt.Name,
+		DocumentNamespace: opt.Namespace,
+		CreationInfo: &CreationInfo{
+			Created:  opt.Created.UTC().Truncate(time.Second),
+			Creators: []string{creator},
+		},
+		Packages:      []*Package{},
+		Relationships: []*Relationship{},
+	}
+
+	if doc.Name == "" {
+		doc.Name = "go-librariesio"
+	}
+	if doc.DocumentNamespace == "" {
+		id, err := uuid.New()
+		if err != nil {
+			return nil, err
+		}
+		doc.DocumentNamespace = fmt.Sprintf("https://spdx.org/spdxdocs/%v-%v", invalidIDChars.ReplaceAllString(doc.Name, "-"), id)
+	}
+	if opt.Created.IsZero() {
+		doc.CreationInfo.Created = time.Now().UTC().Truncate(time.Second)
+	}
+
+	usedIDs := make(map[string]bool)
+	newID := func(parts ...string) string {
+		var nonEmpty []string
+		for _, part := range parts {
+			if part != "" {
+				nonEmpty = append(nonEmpty, part)
+			}
+		}
+		id := "SPDXRef-" + invalidIDChars.ReplaceAllString(strings.Join(nonEmpty, "-"), "-")
+		unique := id
+		for i := 2; usedIDs[unique]; i++ {
+			unique = fmt.Sprintf("%v-%d", id, i)
+		}
+		usedIDs[unique] = true
+		return unique
+	}
+
+	relate := func(a, typ, b string) {
+		doc.Relationships = append(doc.Relationships, &Relationship{
+			SPDXElementID:      a,
+			RelationshipType:   typ,
+			RelatedSPDXElement: b,
+		})
+	}
+
+	extracted := make(map[string]bool)
+	extract := func(project *librariesio.Project) {
+		expr, ok := license.ProjectExpression(project)
+		if !ok {
+			return
+		}
+		for _, l := range license.Licenses(expr) {
+			ref := l.ID
+			if license.IsSPDX(ref) {
+				continue
+			}
+			if !license.IsRef(ref) {
+				ref = license.Ref(ref)
+			}
+			if extracted[ref] {
+				continue
+			}
+			extracted[ref] = true
+			doc.HasExtractedLicensingInfos = append(doc.HasExtractedLicensingInfos, &ExtractedLicensingInfo{
+				LicenseID:     ref,
+				ExtractedText: l.ID,
+				Name:          l.ID,
+			})
+		}
+	}
+
+	ids := make(map[*depgraph.Node]string)
+	for _, node := range g.Nodes() {
+		pkg := newPackage(node)
+		pkg.SPDXID = newID("Package", node.Platform, node.Name, pkg.VersionInfo)
+		ids[node] = pkg.SPDXID
+		doc.Packages = append(doc.Packages, pkg)
+		if node.Project != nil {
+			extract(node.Project)
+		}
+	}
+
+	if opt.Name != "" {
+		app := &Package{
+			Name:             opt.Name,
+			SPDXID:           newID("Application", opt.Name),
+			VersionInfo:      opt.Version,
+			DownloadLocation: NoAssertion,
+			LicenseConcluded: NoAssertion,
+			LicenseDeclared:  NoAssertion,
+			CopyrightText:    NoAssertion,
+		}
+		doc.Packages = append([]*Package{app}, doc.Packages...)
+
+		relate(documentID, "DESCRIBES", app.SPDXID)
+		for _, root := range g.Roots {
+			relate(app.SPDXID, "DEPENDS_ON", ids[root])
+		}
+	} else {
+		for _, root := range g.Roots {
+			relate(documentID, "DESCRIBES", ids[root])
+		}
+	}
+
+	for _, node := range g.Nodes() {
+		for _, edge := range node.Deps {
+			relate(ids[node], "DEPENDS_ON", ids[edge.Node])
+		}
+	}
+
+	return doc, nil
+}
+
+// newPackage returns the Package for a node of the graph without its SPDXID
+func newPackage(node *depgraph.Node) *Package {
+	pkg := &Package{
+		Name:             node.Name,
+		VersionInfo:      node.ResolvedVersion(),
+		DownloadLocation: NoAssertion,
+		LicenseConcluded: NoAssertion,
+		LicenseDeclared:  NoAssertion,
+		CopyrightText:    NoAssertion,
+	}
+
+	if purl, err := librariesio.NewPURL(node.Platform, node.Name, pkg.VersionInfo); err == nil {
+		pkg.ExternalRefs = append(pkg.ExternalRefs, &ExternalRef{
+			ReferenceCategory: "PACKAGE-MANAGER",
+			ReferenceType:     "purl",
+			ReferenceLocator:  purl.String(),
+		})
+	}
+
+	project := node.Project
+	if project == nil {
+		return pkg
+	}
+
+	if project.PackageManagerURL != nil && *project.PackageManagerURL != "" {
+		pkg.DownloadLocation = *project.PackageManagerURL
+	}
+	if project.Homepage != nil && *project.Homepage != "" {
+		pkg.Homepage = *project.Homepage
+	}
+	if project.Description != nil {
+		pkg.Summary = *project.Description
+	}
+	if expr, ok := license.ProjectExpression(project); ok {
+		pkg.LicenseDeclared = license.WithRefs(expr).String()
+	}
+
+	return pkg
+}
+
+// WriteJSON writes the document in the SPDX JSON format
+func (d *Document) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(d)
+}
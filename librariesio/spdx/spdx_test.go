@@ -0,0 +1,205 @@
+package spdx
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hackebrot/go-librariesio/librariesio"
+	"github.com/hackebrot/go-librariesio/librariesio/depgraph"
+	"github.com/hackebrot/go-librariesio/librariesio/manifest"
+)
+
+var created = time.Date(2017, time.June, 1, 12, 0, 0, 0, time.UTC)
+
+// resolveGraph returns the graph for pypi/cookiecutter, which depends on
+// pypi/poyo and pypi/click. poyo declares a license which is not on the SPDX
+// license list.
+func resolveGraph(t *testing.T) *depgraph.Graph {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client := librariesio.NewClient("1234")
+	client.BaseURL, _ = url.Parse(server.URL + "/")
+
+	mux.HandleFunc("/pypi/cookiecutter/1.5.1/dependencies", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{
+			"name": "cookiecutter",
+			"description": "A command-line utility that creates projects from project templates",
+			"homepage": "https://github.com/audreyr/cookiecutter",
+			"package_manager_url": "https://pypi.python.org/pypi/cookiecutter",
+			"normalized_licenses": ["BSD-3-Clause"],
+			"dependencies": [{"name": "poyo"}, {"name": "click"}]
+		}`)
+	})
+	mux.HandleFunc("/pypi/poyo/latest/dependencies", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"name": "poyo", "latest_release_number": "0.4.1", "normalized_licenses": ["MIT", "Other"]}`)
+	})
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"error":"Not Found"}`, http.StatusNotFound)
+	})
+
+	roots := []manifest.Dependency{{
+		ProjectRef:  librariesio.ProjectRef{Platform: "pypi", Name: "cookiecutter"},
+		Requirement: "==1.5.1",
+	}}
+	g, err := depgraph.Resolve(context.Background(), client.Projects, roots, &depgraph.Options{MaxDepth: -1})
+	if err != nil {
+		t.Fatalf("Resolve returned unexpected error: %v", err)
+	}
+	return g
+}
+
+func TestNew(t *testing.T) {
+	doc, err := New(resolveGraph(t), &Options{Name: "my app", Version: "1.0.0", Created: created})
+	if err != nil {
+		t.Fatalf("New returned unexpected error: %v", err)
+	}
+
+	if !strings.HasPrefix(doc.DocumentNamespace, "https://spdx.org/spdxdocs/my-app-") {
+		t.Errorf("unexpected namespace %q", doc.DocumentNamespace)
+	}
+
+	wantPackages := []*Package{
+		{
+			Name:             "my app",
+			SPDXID:           "SPDXRef-Application-my-app",
+			VersionInfo:      "1.0.0",
+			DownloadLocation: NoAssertion,
+			LicenseConcluded: NoAssertion,
+			LicenseDeclared:  NoAssertion,
+			CopyrightText:    NoAssertion,
+		},
+		{
+			Name:             "cookiecutter",
+			SPDXID:           "SPDXRef-Package-pypi-cookiecutter-1.5.1",
+			VersionInfo:      "1.5.1",
+			DownloadLocation: "https://pypi.python.org/pypi/cookiecutter",
+			Homepage:         "https://github.com/audreyr/cookiecutter",
+			LicenseConcluded: NoAssertion,
+			LicenseDeclared:  "BSD-3-Clause",
+			CopyrightText:    NoAssertion,
+			Summary:          "A command-line utility that creates projects from project templates",
+			ExternalRefs: []*ExternalRef{
+				{"PACKAGE-MANAGER", "purl", "pkg:pypi/cookiecutter@1.5.1"},
+			},
+		},
+		{
+			Name:             "poyo",
+			SPDXID:           "SPDXRef-Package-pypi-poyo-0.4.1",
+			VersionInfo:      "0.4.1",
+			DownloadLocation: NoAssertion,
+			LicenseConcluded: NoAssertion,
+			LicenseDeclared:  "MIT AND LicenseRef-Other",
+			CopyrightText:    NoAssertion,
+			ExternalRefs: []*ExternalRef{
+				{"PACKAGE-MANAGER", "purl", "pkg:pypi/poyo@0.4.1"},
+			},
+		},
+		{
+			Name:             "click",
+			SPDXID:           "SPDXRef-Package-pypi-click",
+			DownloadLocation: NoAssertion,
+			LicenseConcluded: NoAssertion,
+			LicenseDeclared:  NoAssertion,
+			CopyrightText:    NoAssertion,
+			ExternalRefs: []*ExternalRef{
+				{"PACKAGE-MANAGER", "purl", "pkg:pypi/click"},
+			},
+		},
+	}
+	if !reflect.DeepEqual(doc.Packages, wantPackages) {
+		for i := range doc.Packages {
+			t.Logf("%+v", doc.Packages[i])
+		}
+		t.Errorf("unexpected packages")
+	}
+
+	wantRelationships := []*Relationship{
+		{"SPDXRef-DOCUMENT", "DESCRIBES", "SPDXRef-Application-my-app"},
+		{"SPDXRef-Application-my-app", "DEPENDS_ON", "SPDXRef-Package-pypi-cookiecutter-1.5.1"},
+		{"SPDXRef-Package-pypi-cookiecutter-1.5.1", "DEPENDS_ON", "SPDXRef-Package-pypi-poyo-0.4.1"},
+		{"SPDXRef-Package-pypi-cookiecutter-1.5.1", "DEPENDS_ON", "SPDXRef-Package-pypi-click"},
+	}
+	if !reflect.DeepEqual(doc.Relationships, wantRelationships) {
+		t.Errorf("\nExpected %+v\nGot %+v", wantRelationships, doc.Relationships)
+	}
+
+	wantExtracted := []*ExtractedLicensingInfo{
+		{LicenseID: "LicenseRef-Other", ExtractedText: "Other", Name: "Other"},
+	}
+	if !reflect.DeepEqual(doc.HasExtractedLicensingInfos, wantExtracted) {
+		t.Errorf("\nExpected %+v\nGot %+v", wantExtracted, doc.HasExtractedLicensingInfos)
+	}
+}
+
+func TestNew_created(t *testing.T) {
+	local := time.Date(2017, time.June, 1, 14, 0, 0, 500000000, time.FixedZone("CEST", 2*60*60))
+
+	doc, err := New(resolveGraph(t), &Options{Created: local})
+	if err != nil {
+		t.Fatalf("New returned unexpected error: %v", err)
+	}
+
+	if got := doc.CreationInfo.Created; got != created {
+		t.Errorf("\nExpected %v\nGot %v", created, got)
+	}
+}
+
+func TestNew_withoutName(t *testing.T) {
+	doc, err := New(resolveGraph(t), nil)
+	if err != nil {
+		t.Fatalf("New returned unexpected error: %v", err)
+	}
+
+	if got, want := len(doc.Packages), 3; got != want {
+		t.Errorf("Expected %d packages, got %d", want, got)
+	}
+
+	want := &Relationship{"SPDXRef-DOCUMENT", "DESCRIBES", "SPDXRef-Package-pypi-cookiecutter-1.5.1"}
+	if got := doc.Relationships[0]; !reflect.DeepEqual(got, want) {
+		t.Errorf("\nExpected %+v\nGot %+v", want, got)
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	doc, err := New(resolveGraph(t), &Options{Namespace: "https://example.com/spdx", Created: created})
+	if err != nil {
+		t.Fatalf("New returned unexpected error: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := doc.WriteJSON(&buf); err != nil {
+		t.Fatalf("WriteJSON returned unexpected error: %v", err)
+	}
+
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for key, want := range map[string]interface{}{
+		"spdxVersion":       "SPDX-2.3",
+		"dataLicense":       "CC0-1.0",
+		"SPDXID":            "SPDXRef-DOCUMENT",
+		"documentNamespace": "https://example.com/spdx",
+	} {
+		if got := decoded[key]; got != want {
+			t.Errorf("%v is %v, want %v", key, got, want)
+		}
+	}
+
+	info := decoded["creationInfo"].(map[string]interface{})
+	if got, want := info["created"], "2017-06-01T12:00:00Z"; got != want {
+		t.Errorf("created is %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,71 @@
+package spdx
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// WriteTagValue writes the document in the SPDX tag-value format
+func (d *Document) WriteTagValue(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+
+	tag := func(name, value string) {
+		if value == "" {
+			return
+		}
+		if strings.Contains(value, "\n") {
+			value = "<text>" + value + "</text>"
+		}
+		fmt.Fprintf(bw, "%v: %v\n", name, value)
+	}
+
+	tag("SPDXVersion", d.SPDXVersion)
+	tag("DataLicense", d.DataLicense)
+	tag("SPDXID", d.SPDXID)
+	tag("DocumentName", d.Name)
+	tag("DocumentNamespace", d.DocumentNamespace)
+	if d.CreationInfo != nil {
+		for _, c := range d.CreationInfo.Creators {
+			tag("Creator", c)
+		}
+		tag("Created", d.CreationInfo.Created.UTC().Format(time.RFC3339))
+	}
+
+	for _, pkg := range d.Packages {
+		fmt.Fprintln(bw)
+		tag("PackageName", pkg.Name)
+		tag("SPDXID", pkg.SPDXID)
+		tag("PackageVersion", pkg.VersionInfo)
+		tag("PackageDownloadLocation", pkg.DownloadLocation)
+		tag("FilesAnalyzed", fmt.Sprint(pkg.FilesAnalyzed))
+		tag("PackageHomePage", pkg.Homepage)
+		tag("PackageLicenseConcluded", pkg.LicenseConcluded)
+		tag("PackageLicenseDeclared", pkg.LicenseDeclared)
+		tag("PackageCopyrightText", pkg.CopyrightText)
+		if pkg.Summary != "" {
+			fmt.Fprintf(bw, "PackageSummary: <text>%v</text>\n", pkg.Summary)
+		}
+		for _, ref := range pkg.ExternalRefs {
+			fmt.Fprintf(bw, "ExternalRef: %v %v %v\n", ref.ReferenceCategory, ref.ReferenceType, ref.ReferenceLocator)
+		}
+	}
+
+	for _, info := range d.HasExtractedLicensingInfos {
+		fmt.Fprintln(bw)
+		tag("LicenseID", info.LicenseID)
+		fmt.Fprintf(bw, "ExtractedText: <text>%v</text>\n", info.ExtractedText)
+		tag("LicenseName", info.Name)
+	}
+
+	if len(d.Relationships) > 0 {
+		fmt.Fprintln(bw)
+	}
+	for _, r := range d.Relationships {
+		fmt.Fprintf(bw, "Relationship: %v %v %v\n", r.SPDXElementID, r.RelationshipType, r.RelatedSPDXElement)
+	}
+
+	return bw.Flush()
+}
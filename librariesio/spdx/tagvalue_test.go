@@ -0,0 +1,68 @@
+package spdx
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestWriteTagValue(t *testing.T) {
+	doc, err := New(resolveGraph(t), &Options{
+		Name:      "example",
+		Namespace: "https://example.com/spdx",
+		Created:   created,
+	})
+	if err != nil {
+		t.Fatalf("New returned unexpected error: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := doc.WriteTagValue(&buf); err != nil {
+		t.Fatalf("WriteTagValue returned unexpected error: %v", err)
+	}
+
+	want := `SPDXVersion: SPDX-2.3
+DataLicense: CC0-1.0
+SPDXID: SPDXRef-DOCUMENT
+DocumentName: example
+DocumentNamespace: https://example.com/spdx
+Creator: Tool: go-librariesio-1
+Created: 2017-06-01T12:00:00Z
+
+PackageName: example
+SPDXID: SPDXRef-Application-example
+PackageDownloadLocation: NOASSERTION
+FilesAnalyzed: false
+PackageLicenseConcluded: NOASSERTION
+PackageLicenseDeclared: NOASSERTION
+PackageCopyrightText: NOASSERTION
+
+PackageName: cookiecutter
+SPDXID: SPDXRef-Package-pypi-cookiecutter-1.5.1
+PackageVersion: 1.5.1
+PackageDownloadLocation: https://pypi.python.org/pypi/cookiecutter
+FilesAnalyzed: false
+PackageHomePage: https://github.com/audreyr/cookiecutter
+PackageLicenseConcluded: NOASSERTION
+PackageLicenseDeclared: BSD-3-Clause
+PackageCopyrightText: NOASSERTION
+PackageSummary: <text>A command-line utility that creates projects from project templates</text>
+ExternalRef: PACKAGE-MANAGER purl pkg:pypi/cookiecutter@1.5.1
+`
+	if got := buf.String(); !strings.HasPrefix(got, want) {
+		t.Errorf("\nExpected prefix\n%v\nGot\n%v", want, got)
+	}
+
+	extracted := `
+LicenseID: LicenseRef-Other
+ExtractedText: <text>Other</text>
+LicenseName: Other
+`
+	if got := buf.String(); !strings.Contains(got, extracted) {
+		t.Errorf("\nExpected\n%v\nGot\n%v", extracted, got)
+	}
+
+	if got := buf.String(); !strings.HasSuffix(got, "Relationship: SPDXRef-Package-pypi-cookiecutter-1.5.1 DEPENDS_ON SPDXRef-Package-pypi-click\n") {
+		t.Errorf("Expected relationships at the end, got\n%v", got)
+	}
+}
@@ -0,0 +1,413 @@
+/*
+Package store mirrors metadata from libraries.io in a local bbolt database,
+e.g. for build agents without network access.
+
+The Store keeps projects with their releases, the dependencies of releases
+and GitHub repositories with the time they were fetched. Sync refreshes the
+records which are older than a maximum age:
+
+	s, err := store.Open("librariesio.db")
+	defer s.Close()
+
+	result, err := store.Sync(ctx, client, s, &store.SyncOptions{
+		Add: []store.Key{store.ProjectKey("pypi", "cookiecutter")},
+	})
+
+A Transport serves the stored records to a librariesio.Client, so code using
+the client works offline:
+
+	client.SetTransport(&store.Transport{Store: s, Offline: true})
+*/
+package store
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hackebrot/go-librariesio/librariesio"
+	bolt "go.etcd.io/bbolt"
+)
+
+// ErrNotFound is returned for keys which are not in the store
+var ErrNotFound = errors.New("store: not found")
+
+// Kind is the type of API resource of a record
+type Kind string
+
+// Kinds of records
+const (
+	// KindProject records hold a librariesio.Project including its
+	// Versions
+	KindProject Kind = "project"
+
+	// KindDependencies records hold the librariesio.Project returned for the
+	// dependencies of a release
+	KindDependencies Kind = "dependencies"
+
+	// KindRepository records hold a librariesio.Repository
+	KindRepository Kind = "repository"
+
+	// KindRepositoryDependencies records hold the librariesio.Repository
+	// returned for its dependencies
+	KindRepositoryDependencies Kind = "repository_dependencies"
+)
+
+// value returns a pointer to a new value of the type of records of the kind
+func (k Kind) value() interface{} {
+	switch k {
+	case KindRepository, KindRepositoryDependencies:
+		return &librariesio.Repository{}
+	}
+	return &librariesio.Project{}
+}
+
+// kinds lists all kinds, each of which is stored in its own bucket
+var kinds = []Kind{KindProject, KindDependencies, KindRepository, KindRepositoryDependencies}
+
+// historyBucket holds the previous records of all kinds
+var historyBucket = []byte("history")
+
+// historyTimeFormat formats times in history keys so they sort in order
+const historyTimeFormat = "20060102T150405.000000000Z"
+
+// Key identifies a record
+type Key struct {
+	Kind Kind `json:"kind"`
+
+	// Platform and Name identify a project. Platform is lower case.
+	Platform string `json:"platform,omitempty"`
+
+	// Owner and Name identify a repository
+	Owner string `json:"owner,omitempty"`
+
+	Name string `json:"name"`
+
+	// Version is the release of dependencies records
+	Version string `json:"version,omitempty"`
+}
+
+// ProjectKey returns the key of a project
+func ProjectKey(platform, name string) Key {
+	return Key{Kind: KindProject, Platform: strings.ToLower(platform), Name: name}
+}
+
+// DependenciesKey returns the key of the dependencies of a release of a
+// project. Version may be "latest".
+func DependenciesKey(platform, name, version string) Key {
+	return Key{Kind: KindDependencies, Platform: strings.ToLower(platform), Name: name, Version: version}
+}
+
+// RepositoryKey returns the key of a GitHub repository
+func RepositoryKey(owner, name string) Key {
+	return Key{Kind: KindRepository, Owner: owner, Name: name}
+}
+
+// RepositoryDependenciesKey returns the key of the dependencies of a GitHub
+// repository
+func RepositoryDependenciesKey(owner, name string) Key {
+	return Key{Kind: KindRepositoryDependencies, Owner: owner, Name: name}
+}
+
+// String returns the key as used in the database, e.g. "pypi/cookiecutter"
+// or "pypi/cookiecutter@1.7.0" for dependencies
+func (k Key) String() string {
+	switch k.Kind {
+	case KindDependencies:
+		return k.Platform + "/" + k.Name + "@" + k.Version
+	case KindRepository, KindRepositoryDependencies:
+		return k.Owner + "/" + k.Name
+	}
+	return k.Platform + "/" + k.Name
+}
+
+// Entry describes a record in the store
+type Entry struct {
+	Key Key `json:"key"`
+
+	// FetchedAt is the last time the record was fetched and Since the first
+	// time the same data was fetched
+	FetchedAt time.Time `json:"fetched_at"`
+	Since     time.Time `json:"since"`
+}
+
+// record is the value stored in the database
+type record struct {
+	Entry
+	Data json.RawMessage `json:"data"`
+}
+
+// Store is a local database of API resources. It is safe for concurrent
+// use, but only one process can open the database at a time.
+//
+// When the data of a record changes, the previous record is kept in the
+// history of the key, see History and GetAt.
+type Store struct {
+	db  *bolt.DB
+	now func() time.Time
+}
+
+// Open opens the database at path, creating it if it doesn't exist
+func Open(path string) (*Store, error) {
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("error opening store %v: %v", path, err)
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, kind := range kinds {
+			if _, err := tx.CreateBucketIfNotExists([]byte(kind)); err != nil {
+				return err
+			}
+		}
+		_, err := tx.CreateBucketIfNotExists(historyBucket)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return &Store{db: db, now: time.Now}, nil
+}
+
+// Close closes the database
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// Put stores v as JSON under the key, fetched now. If the data differs from
+// the stored record, the stored record is moved to the history of the key.
+func (s *Store) Put(key Key, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	now := s.now()
+	return s.put(&record{Entry: Entry{Key: key, FetchedAt: now, Since: now}, Data: data})
+}
+
+func (s *Store) put(r *record) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(r.Key.Kind))
+		if b == nil {
+			return fmt.Errorf("store: unknown kind %q", r.Key.Kind)
+		}
+		k := []byte(r.Key.String())
+
+		if value := b.Get(k); value != nil {
+			old := &record{}
+			if err := json.Unmarshal(value, old); err != nil {
+				return err
+			}
+			if bytes.Equal(old.Data, r.Data) {
+				r.Since = old.Since
+			} else {
+				h := tx.Bucket(historyBucket)
+				if err := h.Put(historyKey(r.Key, old.Since), value); err != nil {
+					return err
+				}
+			}
+		}
+
+		value, err := json.Marshal(r)
+		if err != nil {
+			return err
+		}
+		return b.Put(k, value)
+	})
+}
+
+// historyPrefix returns the prefix of the history keys of the key
+func historyPrefix(key Key) []byte {
+	return []byte(string(key.Kind) + "/" + key.String() + "/")
+}
+
+// historyKey returns the key of a previous record first fetched at since
+func historyKey(key Key, since time.Time) []byte {
+	return append(historyPrefix(key), since.UTC().Format(historyTimeFormat)...)
+}
+
+// history calls fn for the previous records of the key, oldest first
+func history(tx *bolt.Tx, key Key, fn func(k, value []byte) error) error {
+	prefix := historyPrefix(key)
+	c := tx.Bucket(historyBucket).Cursor()
+	for k, value := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, value = c.Next() {
+		// Keys of projects whose name continues with a slash share the
+		// prefix, e.g. go/github.com/a and go/github.com/a/b
+		if bytes.IndexByte(k[len(prefix):], '/') >= 0 {
+			continue
+		}
+		if err := fn(k, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Get loads the record of the key into v and returns when it was fetched.
+// ErrNotFound is returned if the key is not in the store.
+func (s *Store) Get(key Key, v interface{}) (time.Time, error) {
+	r, err := s.get(key)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if err := json.Unmarshal(r.Data, v); err != nil {
+		return time.Time{}, fmt.Errorf("error decoding %v: %v", key, err)
+	}
+	return r.FetchedAt, nil
+}
+
+func (s *Store) get(key Key) (*record, error) {
+	r := &record{}
+	err := s.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(key.Kind))
+		if b == nil {
+			return ErrNotFound
+		}
+		value := b.Get([]byte(key.String()))
+		if value == nil {
+			return ErrNotFound
+		}
+		return json.Unmarshal(value, r)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// History returns the previous records of the key, oldest first
+func (s *Store) History(key Key) ([]*Entry, error) {
+	var entries []*Entry
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return history(tx, key, func(_, value []byte) error {
+			r := &record{}
+			if err := json.Unmarshal(value, r); err != nil {
+				return err
+			}
+			entries = append(entries, &r.Entry)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// GetAt loads the record of the key as it was at the given time into v,
+// which is the newest record first fetched before or at that time. It
+// returns the entry of the record or ErrNotFound if the key wasn't fetched
+// before that time.
+func (s *Store) GetAt(key Key, at time.Time, v interface{}) (*Entry, error) {
+	var found *record
+	err := s.db.View(func(tx *bolt.Tx) error {
+		candidates := []*record{}
+		err := history(tx, key, func(_, value []byte) error {
+			r := &record{}
+			if err := json.Unmarshal(value, r); err != nil {
+				return err
+			}
+			candidates = append(candidates, r)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		if b := tx.Bucket([]byte(key.Kind)); b != nil {
+			if value := b.Get([]byte(key.String())); value != nil {
+				r := &record{}
+				if err := json.Unmarshal(value, r); err != nil {
+					return err
+				}
+				candidates = append(candidates, r)
+			}
+		}
+
+		for _, r := range candidates {
+			if !r.Since.After(at) {
+				found = r
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if found == nil {
+		return nil, ErrNotFound
+	}
+
+	if err := json.Unmarshal(found.Data, v); err != nil {
+		return nil, fmt.Errorf("error decoding %v: %v", key, err)
+	}
+	return &found.Entry, nil
+}
+
+// Delete removes the record of the key and its history. Deleting a missing
+// key is not an error.
+func (s *Store) Delete(key Key) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(key.Kind))
+		if b == nil {
+			return nil
+		}
+		if err := b.Delete([]byte(key.String())); err != nil {
+			return err
+		}
+
+		var keys [][]byte
+		err := history(tx, key, func(k, _ []byte) error {
+			keys = append(keys, append([]byte(nil), k...))
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		h := tx.Bucket(historyBucket)
+		for _, k := range keys {
+			if err := h.Delete(k); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Entries lists the records of the given kinds, or of all kinds if none are
+// given, ordered by kind and key
+func (s *Store) Entries(kind ...Kind) ([]*Entry, error) {
+	if len(kind) == 0 {
+		kind = kinds
+	}
+
+	var entries []*Entry
+	err := s.db.View(func(tx *bolt.Tx) error {
+		for _, k := range kind {
+			b := tx.Bucket([]byte(k))
+			if b == nil {
+				continue
+			}
+			err := b.ForEach(func(_, value []byte) error {
+				r := &record{}
+				if err := json.Unmarshal(value, r); err != nil {
+					return err
+				}
+				entries = append(entries, &r.Entry)
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
@@ -0,0 +1,163 @@
+package store
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/hackebrot/go-librariesio/librariesio"
+)
+
+var now = time.Date(2017, time.June, 1, 0, 0, 0, 0, time.UTC)
+
+// openStore opens a store in a temporary directory which is closed at the end
+// of the test
+func openStore(t *testing.T) *Store {
+	s, err := Open(filepath.Join(t.TempDir(), "librariesio.db"))
+	if err != nil {
+		t.Fatalf("Open returned error: %v", err)
+	}
+	s.now = func() time.Time { return now }
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+func TestStore(t *testing.T) {
+	s := openStore(t)
+
+	project := &librariesio.Project{
+		Platform: librariesio.String("Pypi"),
+		Name:     librariesio.String("cookiecutter"),
+		Versions: []*librariesio.Release{{Number: librariesio.String("1.5.1")}},
+	}
+	if err := s.Put(ProjectKey("Pypi", "cookiecutter"), project); err != nil {
+		t.Fatalf("Put returned error: %v", err)
+	}
+
+	got := &librariesio.Project{}
+	fetchedAt, err := s.Get(ProjectKey("pypi", "cookiecutter"), got)
+	if err != nil {
+		t.Fatalf("Get returned error: %v", err)
+	}
+	if !fetchedAt.Equal(now) {
+		t.Errorf("\nExpected %v\nGot %v", now, fetchedAt)
+	}
+	if !reflect.DeepEqual(got, project) {
+		t.Errorf("\nExpected %v\nGot %v", project, got)
+	}
+
+	if _, err := s.Get(DependenciesKey("pypi", "cookiecutter", "latest"), got); err != ErrNotFound {
+		t.Errorf("\nExpected %v\nGot %v", ErrNotFound, err)
+	}
+
+	if err := s.Put(RepositoryKey("audreyr", "cookiecutter"), &librariesio.Repository{}); err != nil {
+		t.Fatalf("Put returned error: %v", err)
+	}
+
+	entries, err := s.Entries()
+	if err != nil {
+		t.Fatalf("Entries returned error: %v", err)
+	}
+	want := []*Entry{
+		{Key: ProjectKey("pypi", "cookiecutter"), FetchedAt: now, Since: now},
+		{Key: RepositoryKey("audreyr", "cookiecutter"), FetchedAt: now, Since: now},
+	}
+	if !reflect.DeepEqual(entries, want) {
+		t.Errorf("\nExpected %v\nGot %v", want, entries)
+	}
+
+	if err := s.Delete(ProjectKey("pypi", "cookiecutter")); err != nil {
+		t.Fatalf("Delete returned error: %v", err)
+	}
+	if entries, _ := s.Entries(KindProject); len(entries) != 0 {
+		t.Errorf("expected no projects, got %v", entries)
+	}
+}
+
+func TestStore_history(t *testing.T) {
+	s := openStore(t)
+	key := ProjectKey("go", "github.com/hackebrot/go-repr")
+	day := 24 * time.Hour
+
+	put := func(at time.Time, version string) {
+		s.now = func() time.Time { return at }
+		p := &librariesio.Project{LatestReleaseNumber: librariesio.String(version)}
+		if err := s.Put(key, p); err != nil {
+			t.Fatalf("Put returned error: %v", err)
+		}
+	}
+	put(now, "1.0.0")
+	put(now.Add(day), "1.0.0")
+	put(now.Add(2*day), "1.1.0")
+	put(now.Add(3*day), "1.2.0")
+
+	// the history of a project whose name continues the key is not part of
+	// its history
+	sub := ProjectKey("go", "github.com/hackebrot/go-repr/repr")
+	for _, version := range []string{"1.0.0", "1.1.0"} {
+		if err := s.Put(sub, &librariesio.Project{LatestReleaseNumber: librariesio.String(version)}); err != nil {
+			t.Fatalf("Put returned error: %v", err)
+		}
+	}
+
+	entries, err := s.History(key)
+	if err != nil {
+		t.Fatalf("History returned error: %v", err)
+	}
+	want := []*Entry{
+		{Key: key, FetchedAt: now.Add(day), Since: now},
+		{Key: key, FetchedAt: now.Add(2 * day), Since: now.Add(2 * day)},
+	}
+	if !reflect.DeepEqual(entries, want) {
+		t.Errorf("\nExpected %v\nGot %v", want, entries)
+	}
+
+	testCases := []struct {
+		at      time.Time
+		version string
+	}{
+		{now, "1.0.0"},
+		{now.Add(day + time.Hour), "1.0.0"},
+		{now.Add(2 * day), "1.1.0"},
+		{now.Add(10 * day), "1.2.0"},
+	}
+	for _, tc := range testCases {
+		p := &librariesio.Project{}
+		if _, err := s.GetAt(key, tc.at, p); err != nil {
+			t.Fatalf("GetAt returned error: %v", err)
+		}
+		if *p.LatestReleaseNumber != tc.version {
+			t.Errorf("at %v:\nExpected %v\nGot %v", tc.at, tc.version, *p.LatestReleaseNumber)
+		}
+	}
+
+	if _, err := s.GetAt(key, now.Add(-time.Hour), &librariesio.Project{}); err != ErrNotFound {
+		t.Errorf("\nExpected %v\nGot %v", ErrNotFound, err)
+	}
+
+	if err := s.Delete(key); err != nil {
+		t.Fatalf("Delete returned error: %v", err)
+	}
+	if entries, _ := s.History(key); len(entries) != 0 {
+		t.Errorf("expected the history to be deleted, got %v", entries)
+	}
+}
+
+func TestKey_String(t *testing.T) {
+	testCases := []struct {
+		key  Key
+		want string
+	}{
+		{ProjectKey("Pypi", "cookiecutter"), "pypi/cookiecutter"},
+		{DependenciesKey("pypi", "cookiecutter", "1.5.1"), "pypi/cookiecutter@1.5.1"},
+		{RepositoryKey("audreyr", "cookiecutter"), "audreyr/cookiecutter"},
+		{RepositoryDependenciesKey("audreyr", "cookiecutter"), "audreyr/cookiecutter"},
+	}
+
+	for _, tc := range testCases {
+		if got := tc.key.String(); got != tc.want {
+			t.Errorf("\nExpected %v\nGot %v", tc.want, got)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hackebrot/go-librariesio/librariesio"
+)
+
+// DefaultMaxAge is used if SyncOptions.MaxAge is zero
+const DefaultMaxAge = 24 * time.Hour
+
+// SyncOptions configure Sync
+type SyncOptions struct {
+	// MaxAge is the age after which records are fetched again. A negative
+	// MaxAge refreshes all records.
+	MaxAge time.Duration
+
+	// Add are fetched if they are not in the store yet
+	Add []Key
+}
+
+// SyncFailure is a record which could not be fetched
+type SyncFailure struct {
+	Key   Key    `json:"key"`
+	Error string `json:"error"`
+}
+
+// SyncResult lists the records handled by Sync
+type SyncResult struct {
+	// Fetched are the records which were added or refreshed
+	Fetched []Key `json:"fetched"`
+
+	// Fresh are the records which were not older than the maximum age
+	Fresh []Key `json:"fresh"`
+
+	// Failed are the records which could not be fetched. The stored records
+	// of refreshed keys are kept.
+	Failed []*SyncFailure `json:"failed"`
+}
+
+// Sync fetches the records of the store which are older than the maximum age
+// and the added keys which are not in the store yet. The requests skip caches
+// and don't fall back to the records of a Transport.
+//
+// Records which can't be fetched are reported in the result. An error is
+// returned if the store can't be read or written or the context is
+// cancelled.
+func Sync(ctx context.Context, c *librariesio.Client, s *Store, opt *SyncOptions) (*SyncResult, error) {
+	if opt == nil {
+		opt = &SyncOptions{}
+	}
+	maxAge := opt.MaxAge
+	if maxAge == 0 {
+		maxAge = DefaultMaxAge
+	}
+
+	entries, err := s.Entries()
+	if err != nil {
+		return nil, err
+	}
+
+	fetchedAt := make(map[Key]time.Time)
+	var keys []Key
+	for _, e := range entries {
+		fetchedAt[e.Key] = e.FetchedAt
+		keys = append(keys, e.Key)
+	}
+	for _, key := range opt.Add {
+		if _, ok := fetchedAt[key]; !ok {
+			fetchedAt[key] = time.Time{}
+			keys = append(keys, key)
+		}
+	}
+
+	ctx = librariesio.WithRequestOptions(ctx, librariesio.SkipCache())
+
+	result := &SyncResult{Fetched: []Key{}, Fresh: []Key{}, Failed: []*SyncFailure{}}
+	now := s.now()
+	for _, key := range keys {
+		if t := fetchedAt[key]; maxAge > 0 && !t.IsZero() && now.Sub(t) < maxAge {
+			result.Fresh = append(result.Fresh, key)
+			continue
+		}
+
+		v, err := fetch(ctx, c, key)
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if err != nil {
+			result.Failed = append(result.Failed, &SyncFailure{Key: key, Error: err.Error()})
+			continue
+		}
+		if err := s.Put(key, v); err != nil {
+			return nil, err
+		}
+		result.Fetched = append(result.Fetched, key)
+	}
+
+	return result, nil
+}
+
+// fetch requests the resource of the key from the API
+func fetch(ctx context.Context, c *librariesio.Client, key Key) (interface{}, error) {
+	var v interface{}
+	var err error
+
+	switch key.Kind {
+	case KindProject:
+		v, _, err = c.Projects.Get(ctx, key.Platform, key.Name)
+	case KindDependencies:
+		v, _, err = c.Projects.Deps(ctx, key.Platform, key.Name, key.Version)
+	case KindRepository:
+		v, _, err = c.Repositories.Get(ctx, key.Owner, key.Name)
+	case KindRepositoryDependencies:
+		v, _, err = c.Repositories.Deps(ctx, key.Owner, key.Name)
+	default:
+		return nil, fmt.Errorf("unknown kind %q", key.Kind)
+	}
+
+	return v, err
+}
@@ -0,0 +1,90 @@
+package store
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/hackebrot/go-librariesio/librariesio"
+	"github.com/hackebrot/go-librariesio/librariesio/librariesiotest"
+)
+
+func newTestServer() *librariesiotest.Server {
+	return librariesiotest.NewServer(&librariesiotest.Fixtures{
+		Projects: []*librariesiotest.ProjectFixture{
+			{
+				Project: &librariesio.Project{
+					Platform:            librariesio.String("Pypi"),
+					Name:                librariesio.String("cookiecutter"),
+					LatestReleaseNumber: librariesio.String("1.5.1"),
+					Versions:            []*librariesio.Release{{Number: librariesio.String("1.5.1")}},
+				},
+				VersionDependencies: map[string][]*librariesio.ProjectDependency{
+					"1.5.1": {{Name: librariesio.String("click"), Platform: librariesio.String("Pypi")}},
+				},
+			},
+		},
+		Users: []*librariesiotest.UserFixture{
+			{
+				User: &librariesio.User{Login: librariesio.String("audreyr")},
+				Repositories: []*librariesio.Repository{
+					{FullName: librariesio.String("audreyr/cookiecutter")},
+				},
+			},
+		},
+	})
+}
+
+func TestSync(t *testing.T) {
+	server := newTestServer()
+	defer server.Close()
+
+	s := openStore(t)
+	stale := ProjectKey("pypi", "cookiecutter")
+	if err := s.Put(stale, &librariesio.Project{}); err != nil {
+		t.Fatalf("Put returned error: %v", err)
+	}
+	fresh := RepositoryKey("audreyr", "other")
+	s.now = func() time.Time { return now.Add(DefaultMaxAge) }
+	if err := s.Put(fresh, &librariesio.Repository{}); err != nil {
+		t.Fatalf("Put returned error: %v", err)
+	}
+
+	opt := &SyncOptions{Add: []Key{
+		stale,
+		DependenciesKey("pypi", "cookiecutter", "latest"),
+		RepositoryKey("audreyr", "cookiecutter"),
+		ProjectKey("pypi", "missing"),
+	}}
+	result, err := Sync(context.Background(), server.NewClient(), s, opt)
+	if err != nil {
+		t.Fatalf("Sync returned error: %v", err)
+	}
+
+	if want := []Key{stale, DependenciesKey("pypi", "cookiecutter", "latest"), RepositoryKey("audreyr", "cookiecutter")}; !reflect.DeepEqual(result.Fetched, want) {
+		t.Errorf("\nExpected %v\nGot %v", want, result.Fetched)
+	}
+	if want := []Key{fresh}; !reflect.DeepEqual(result.Fresh, want) {
+		t.Errorf("\nExpected %v\nGot %v", want, result.Fresh)
+	}
+	if len(result.Failed) != 1 || result.Failed[0].Key != ProjectKey("pypi", "missing") {
+		t.Errorf("expected pypi/missing to fail, got %v", result.Failed)
+	}
+
+	project := &librariesio.Project{}
+	if _, err := s.Get(stale, project); err != nil {
+		t.Fatalf("Get returned error: %v", err)
+	}
+	if project.LatestReleaseNumber == nil || *project.LatestReleaseNumber != "1.5.1" {
+		t.Errorf("expected the project to be refreshed, got %v", project)
+	}
+
+	deps := &librariesio.Project{}
+	if _, err := s.Get(DependenciesKey("pypi", "cookiecutter", "latest"), deps); err != nil {
+		t.Fatalf("Get returned error: %v", err)
+	}
+	if len(deps.Dependencies) != 1 || *deps.Dependencies[0].Name != "click" {
+		t.Errorf("expected the dependencies to be stored, got %v", deps.Dependencies)
+	}
+}
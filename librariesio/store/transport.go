@@ -0,0 +1,159 @@
+package store
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/hackebrot/go-librariesio/librariesio/transport"
+)
+
+// defaultBaseURL is the base URL of the libraries.io API
+var defaultBaseURL = &url.URL{Scheme: "https", Host: "libraries.io", Path: "/api/"}
+
+// Transport is a http.RoundTripper which serves the endpoints for the kinds
+// of records from a Store. Responses served from the store have the
+// transport.FromCacheHeader set.
+//
+// If Offline is false, requests are sent with Transport and successful
+// responses update the store. The store is only used if sending a request
+// fails, e.g. because the network is down, unless the request has a
+// "Cache-Control: no-cache" header. If Offline is true, no requests are sent
+// and requests for records which are not stored fail.
+type Transport struct {
+	Store *Store
+
+	// BaseURL is the base URL of the client, the libraries.io API if nil
+	BaseURL *url.URL
+
+	Offline bool
+
+	// Transport sends requests if not Offline and defaults to
+	// http.DefaultTransport
+	Transport http.RoundTripper
+}
+
+func (t *Transport) transport() http.RoundTripper {
+	if t.Transport == nil {
+		return http.DefaultTransport
+	}
+	return t.Transport
+}
+
+// RoundTrip sends the request or serves it from the store
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	key, ok := t.key(req)
+
+	if !t.Offline {
+		resp, err := t.transport().RoundTrip(req)
+		if err != nil {
+			if ok && !strings.Contains(req.Header.Get("Cache-Control"), "no-cache") {
+				if stored, storeErr := t.serve(req, key); storeErr == nil {
+					return stored, nil
+				}
+			}
+			return nil, err
+		}
+		if ok && resp.StatusCode == http.StatusOK {
+			return t.update(resp, key)
+		}
+		return resp, nil
+	}
+
+	if !ok {
+		return nil, fmt.Errorf("store: %v %v is not available offline", req.Method, req.URL.Path)
+	}
+	resp, err := t.serve(req, key)
+	if err == ErrNotFound {
+		return nil, fmt.Errorf("store: %v is not stored", key)
+	}
+	return resp, err
+}
+
+// serve returns a response with the stored record of the key
+func (t *Transport) serve(req *http.Request, key Key) (*http.Response, error) {
+	r, err := t.Store.get(key)
+	if err != nil {
+		return nil, err
+	}
+
+	header := make(http.Header)
+	header.Set("Content-Type", "application/json")
+	header.Set(transport.FromCacheHeader, "1")
+
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          ioutil.NopCloser(bytes.NewReader(r.Data)),
+		ContentLength: int64(len(r.Data)),
+		Request:       req,
+	}, nil
+}
+
+// update stores the body of a successful response under the key
+func (t *Transport) update(resp *http.Response, key Key) (*http.Response, error) {
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	// Records are stored like those of Sync, so the history only changes
+	// when the data does. Bodies which can't be decoded are passed on but
+	// not stored, the client reports the error.
+	v := key.Kind.value()
+	if json.Unmarshal(body, v) == nil {
+		if err := t.Store.Put(key, v); err != nil {
+			return nil, err
+		}
+	}
+	return resp, nil
+}
+
+// key returns the key of the record for a GET request of an endpoint which
+// can be served from the store
+func (t *Transport) key(req *http.Request) (Key, bool) {
+	if req.Method != "GET" {
+		return Key{}, false
+	}
+
+	base := t.BaseURL
+	if base == nil {
+		base = defaultBaseURL
+	}
+	path := req.URL.EscapedPath()
+	if !strings.HasPrefix(path, base.EscapedPath()) {
+		return Key{}, false
+	}
+	segments := strings.Split(strings.Trim(strings.TrimPrefix(path, base.EscapedPath()), "/"), "/")
+	for i, s := range segments {
+		if unescaped, err := url.PathUnescape(s); err == nil {
+			segments[i] = unescaped
+		}
+	}
+
+	switch n := len(segments); {
+	case segments[0] == "github":
+		switch {
+		case n == 3 && segments[2] != "projects" && segments[2] != "repositories":
+			return RepositoryKey(segments[1], segments[2]), true
+		case n == 4 && segments[3] == "dependencies":
+			return RepositoryDependenciesKey(segments[1], segments[2]), true
+		}
+	case segments[0] == "subscriptions":
+	case n == 2:
+		return ProjectKey(segments[0], segments[1]), true
+	case n == 4 && segments[3] == "dependencies":
+		return DependenciesKey(segments[0], segments[1], segments[2]), true
+	}
+	return Key{}, false
+}
@@ -0,0 +1,118 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/hackebrot/go-librariesio/librariesio"
+	"github.com/hackebrot/go-librariesio/librariesio/transport"
+)
+
+// failingTransport fails all requests like a missing network connection
+type failingTransport struct{}
+
+func (failingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return nil, errors.New("network is unreachable")
+}
+
+func TestTransport(t *testing.T) {
+	server := newTestServer()
+	defer server.Close()
+
+	s := openStore(t)
+	client := server.NewClient()
+	client.SetTransport(&Transport{Store: s, BaseURL: client.BaseURL})
+
+	if _, _, err := client.Projects.Get(context.Background(), "pypi", "cookiecutter"); err != nil {
+		t.Fatalf("Projects.Get returned error: %v", err)
+	}
+	if _, err := s.Get(ProjectKey("pypi", "cookiecutter"), &librariesio.Project{}); err != nil {
+		t.Fatalf("expected the response to be stored, got %v", err)
+	}
+
+	client.SetTransport(&Transport{Store: s, BaseURL: client.BaseURL, Transport: failingTransport{}})
+	project, resp, err := client.Projects.Get(context.Background(), "pypi", "cookiecutter")
+	if err != nil {
+		t.Fatalf("Projects.Get returned error: %v", err)
+	}
+	if *project.Name != "cookiecutter" {
+		t.Errorf("\nExpected %v\nGot %v", "cookiecutter", *project.Name)
+	}
+	if resp.Header.Get(transport.FromCacheHeader) != "1" {
+		t.Errorf("expected the response to be served from the store")
+	}
+}
+
+func TestTransport_offline(t *testing.T) {
+	s := openStore(t)
+	if err := s.Put(DependenciesKey("pypi", "cookiecutter", "latest"), &librariesio.Project{Name: librariesio.String("cookiecutter")}); err != nil {
+		t.Fatalf("Put returned error: %v", err)
+	}
+	if err := s.Put(RepositoryKey("audreyr", "cookiecutter"), &librariesio.Repository{FullName: librariesio.String("audreyr/cookiecutter")}); err != nil {
+		t.Fatalf("Put returned error: %v", err)
+	}
+
+	client := librariesio.NewClient("1234")
+	client.SetTransport(&Transport{Store: s, Offline: true, Transport: failingTransport{}})
+
+	if _, _, err := client.Projects.Deps(context.Background(), "pypi", "cookiecutter", "latest"); err != nil {
+		t.Errorf("Projects.Deps returned error: %v", err)
+	}
+	if _, _, err := client.Repositories.Get(context.Background(), "audreyr", "cookiecutter"); err != nil {
+		t.Errorf("Repositories.Get returned error: %v", err)
+	}
+	if _, _, err := client.Projects.Get(context.Background(), "pypi", "cookiecutter"); err == nil {
+		t.Error("expected an error for a project which is not stored")
+	}
+	if _, _, err := client.SearchService.Projects(context.Background(), "cookiecutter", nil); err == nil {
+		t.Error("expected an error for an endpoint which is not stored")
+	}
+}
+
+func TestTransport_key(t *testing.T) {
+	base, _ := url.Parse("http://localhost/api/")
+	tr := &Transport{BaseURL: base}
+
+	testCases := []struct {
+		url  string
+		want Key
+		ok   bool
+	}{
+		{"http://localhost/api/pypi/cookiecutter", ProjectKey("pypi", "cookiecutter"), true},
+		{"http://localhost/api/npm/%40babel%2Fcore", ProjectKey("npm", "@babel/core"), true},
+		{"http://localhost/api/pypi/cookiecutter/1.5.1/dependencies", DependenciesKey("pypi", "cookiecutter", "1.5.1"), true},
+		{"http://localhost/api/github/audreyr/cookiecutter", RepositoryKey("audreyr", "cookiecutter"), true},
+		{"http://localhost/api/github/audreyr/cookiecutter/dependencies", RepositoryDependenciesKey("audreyr", "cookiecutter"), true},
+		{"http://localhost/api/github/audreyr", Key{}, false},
+		{"http://localhost/api/github/audreyr/projects", Key{}, false},
+		{"http://localhost/api/pypi/cookiecutter/dependents", Key{}, false},
+		{"http://localhost/api/subscriptions/pypi/cookiecutter", Key{}, false},
+		{"http://localhost/other/pypi/cookiecutter", Key{}, false},
+	}
+
+	for _, tc := range testCases {
+		req, _ := http.NewRequest("GET", tc.url, nil)
+		got, ok := tr.key(req)
+		if got != tc.want || ok != tc.ok {
+			t.Errorf("%v:\nExpected %v %v\nGot %v %v", tc.url, tc.want, tc.ok, got, ok)
+		}
+	}
+}
+
+func TestTransport_noCache(t *testing.T) {
+	s := openStore(t)
+	if err := s.Put(ProjectKey("pypi", "cookiecutter"), &librariesio.Project{}); err != nil {
+		t.Fatalf("Put returned error: %v", err)
+	}
+
+	client := librariesio.NewClient("1234")
+	client.SetTransport(&Transport{Store: s, Transport: failingTransport{}})
+
+	ctx := librariesio.WithRequestOptions(context.Background(), librariesio.SkipCache())
+	if _, _, err := client.Projects.Get(ctx, "pypi", "cookiecutter"); err == nil {
+		t.Error("expected no-cache requests not to fall back to the store")
+	}
+}
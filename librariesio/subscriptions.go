@@ -0,0 +1,46 @@
+package librariesio
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// Subscription represents a subscription of the authenticated user to the
+// releases of a project
+type Subscription struct {
+	CreatedAt         *time.Time `json:"created_at,omitempty"`
+	IncludePrerelease *bool      `json:"include_prerelease,omitempty"`
+	Project           *Project   `json:"project,omitempty"`
+	UpdatedAt         *time.Time `json:"updated_at,omitempty"`
+}
+
+// SubscriptionsService handles the subscription endpoints of the API
+type SubscriptionsService service
+
+// List returns the projects the authenticated user is subscribed to
+//
+// GET https://libraries.io/api/subscriptions
+func (s *SubscriptionsService) List(ctx context.Context, opt *ListOptions) ([]*Subscription, *http.Response, error) {
+	request, err := s.client.NewRequest("GET", "subscriptions", nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	addListOptions(request, opt)
+
+	var subscriptions []*Subscription
+
+	response, err := s.client.Do(ctx, request, &subscriptions)
+	if err != nil {
+		return nil, response, err
+	}
+
+	return subscriptions, response, nil
+}
+
+// Subscriptions returns the projects the authenticated user is subscribed to
+//
+// Deprecated: Use Client.SubscriptionsService.List instead.
+func (c *Client) Subscriptions(ctx context.Context, opt *ListOptions) ([]*Subscription, *http.Response, error) {
+	return c.SubscriptionsService.List(ctx, opt)
+}
@@ -0,0 +1,61 @@
+package librariesio
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/hackebrot/go-repr/repr"
+)
+
+func TestSubscriptions(t *testing.T) {
+	server, mux, url := startNewServer()
+	client := NewClient(APIKey)
+	client.BaseURL = url
+	defer server.Close()
+
+	mux.HandleFunc("/subscriptions", func(w http.ResponseWriter, r *http.Request) {
+		if method := "GET"; method != r.Method {
+			t.Errorf("expected HTTP %v request, got %v", method, r.Method)
+		}
+		if perPage := r.URL.Query().Get("per_page"); perPage != "100" {
+			t.Errorf("expected per_page 100, got %v", perPage)
+		}
+		fmt.Fprintf(w, `[
+			{
+				"include_prerelease": true,
+				"project": {"name": "cookiecutter", "platform": "Pypi"}
+			}
+		]`)
+	})
+
+	subscriptions, _, err := client.SubscriptionsService.List(context.Background(), &ListOptions{PerPage: 100})
+	if err != nil {
+		t.Fatalf("Subscriptions returned unexpected error: %v", err)
+	}
+
+	want := []*Subscription{
+		{
+			IncludePrerelease: Bool(true),
+			Project: &Project{
+				Name:     String("cookiecutter"),
+				Platform: String("Pypi"),
+			},
+		},
+	}
+
+	if !reflect.DeepEqual(subscriptions, want) {
+		t.Errorf("\nExpected %v\nGot %v", repr.Repr(want), repr.Repr(subscriptions))
+	}
+
+	// the deprecated method of the client calls the service
+	subscriptions, _, err = client.Subscriptions(context.Background(), &ListOptions{PerPage: 100})
+	if err != nil {
+		t.Fatalf("Subscriptions returned unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(subscriptions, want) {
+		t.Errorf("\nExpected %v\nGot %v", repr.Repr(want), repr.Repr(subscriptions))
+	}
+}
@@ -0,0 +1,211 @@
+/*
+Package transport provides http.RoundTrippers to customize how a
+librariesio.Client talks to the API, see Client.SetTransport.
+
+Cache keeps API responses on disk, so repeated lookups don't count against
+the rate limit, and RateLimit spaces out requests to stay within it.
+
+Recorder and Replayer capture real API interactions in a cassette file and
+play them back without network access, for deterministic tests:
+
+	client.SetTransport(transport.NewRecorder("testdata/cassette.json", nil))
+
+	replayer, err := transport.NewReplayer("testdata/cassette.json")
+	client.SetTransport(replayer)
+
+Offline serves responses from a directory of JSON files mirroring the API
+paths, e.g. to run tools without a network connection:
+
+	client.SetTransport(&transport.Offline{Dir: "fixtures"})
+*/
+package transport
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/hackebrot/go-librariesio/librariesio"
+)
+
+// FromCacheHeader is set to "1" on responses served from a Cache
+const FromCacheHeader = "X-From-Cache"
+
+// DefaultCacheMaxAge is used if Cache.MaxAge is zero
+const DefaultCacheMaxAge = time.Hour
+
+// Cache is a http.RoundTripper which stores successful GET responses in Dir
+// and serves them until they are older than MaxAge.
+//
+// Requests with a "Cache-Control: no-cache" header bypass the cache, but
+// still update it. Responses are cached per API key, as some depend on the
+// user, e.g. the subscriptions. The key is only part of the hashed file name
+// and not written to disk.
+type Cache struct {
+	Dir    string
+	MaxAge time.Duration
+
+	// AuthHeader is the header carrying the API key if it is not sent as
+	// api_key query param, see librariesio.HeaderAuth. It defaults to
+	// librariesio.DefaultAuthHeader.
+	AuthHeader string
+
+	// Transport sends requests on a cache miss and defaults to
+	// http.DefaultTransport
+	Transport http.RoundTripper
+}
+
+// cacheEntry is a response stored on disk
+type cacheEntry struct {
+	StoredAt   time.Time   `json:"stored_at"`
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header"`
+	Body       []byte      `json:"body"`
+}
+
+func (c *Cache) transport() http.RoundTripper {
+	if c.Transport == nil {
+		return http.DefaultTransport
+	}
+	return c.Transport
+}
+
+func (c *Cache) authHeader() string {
+	if c.AuthHeader == "" {
+		return librariesio.DefaultAuthHeader
+	}
+	return c.AuthHeader
+}
+
+func (c *Cache) maxAge() time.Duration {
+	if c.MaxAge == 0 {
+		return DefaultCacheMaxAge
+	}
+	return c.MaxAge
+}
+
+// RoundTrip serves the request from the cache or sends it and stores the
+// response
+func (c *Cache) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != "GET" {
+		return c.transport().RoundTrip(req)
+	}
+
+	path := c.path(req)
+
+	if !strings.Contains(req.Header.Get("Cache-Control"), "no-cache") {
+		if entry, ok := c.load(path); ok && time.Since(entry.StoredAt) < c.maxAge() {
+			return entry.response(req), nil
+		}
+	}
+
+	resp, err := c.transport().RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		return resp, err
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	// A cache which can't be written must not break the client
+	c.store(path, &cacheEntry{
+		StoredAt:   time.Now(),
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header,
+		Body:       body,
+	})
+
+	return resp, nil
+}
+
+// path returns the file of the cache entry for the request, which depends
+// on the API key sent as api_key query param or in the auth headers
+func (c *Cache) path(req *http.Request) string {
+	u := *req.URL
+	q := u.Query()
+	credentials := []string{
+		q.Get("api_key"),
+		req.Header.Get(c.authHeader()),
+		req.Header.Get("Authorization"),
+	}
+	q.Del("api_key")
+	u.RawQuery = q.Encode()
+
+	key := req.Method + " " + u.String() + "\n" + strings.Join(credentials, "\n")
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(c.Dir, hex.EncodeToString(sum[:])+".json")
+}
+
+// load reads the cache entry at path
+func (c *Cache) load(path string) (*cacheEntry, bool) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+
+	entry := new(cacheEntry)
+	if err := json.Unmarshal(data, entry); err != nil {
+		return nil, false
+	}
+	return entry, true
+}
+
+// store writes the cache entry to path, replacing it atomically
+func (c *Cache) store(path string, entry *cacheEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(c.Dir, 0700); err != nil {
+		return err
+	}
+
+	tmp, err := ioutil.TempFile(c.Dir, ".tmp-")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// response returns the cached response for the request
+func (e *cacheEntry) response(req *http.Request) *http.Response {
+	header := make(http.Header)
+	for key, values := range e.Header {
+		header[key] = values
+	}
+	header.Set(FromCacheHeader, "1")
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", e.StatusCode, http.StatusText(e.StatusCode)),
+		StatusCode:    e.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          ioutil.NopCloser(bytes.NewReader(e.Body)),
+		ContentLength: int64(len(e.Body)),
+		Request:       req,
+	}
+}
@@ -0,0 +1,147 @@
+package transport
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+)
+
+func get(t *testing.T, rt http.RoundTripper, url string, header http.Header) *http.Response {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	resp, err := rt.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("RoundTrip returned unexpected error: %v", err)
+	}
+	return resp
+}
+
+func body(t *testing.T, resp *http.Response) string {
+	defer resp.Body.Close()
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestCache(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Path == "/missing" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprintf(w, `{"request":%d}`, requests)
+	}))
+	defer server.Close()
+
+	dir, err := ioutil.TempDir("", "cache")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	cache := &Cache{Dir: dir}
+
+	first := get(t, cache, server.URL+"/pypi/cookiecutter?api_key=1234", nil)
+	if got := body(t, first); got != `{"request":1}` {
+		t.Errorf("unexpected body %v", got)
+	}
+	if first.Header.Get(FromCacheHeader) != "" {
+		t.Errorf("expected first response not to be cached")
+	}
+
+	second := get(t, cache, server.URL+"/pypi/cookiecutter?api_key=1234", nil)
+	if got := body(t, second); got != `{"request":1}` {
+		t.Errorf("expected cached body, got %v", got)
+	}
+	if second.Header.Get(FromCacheHeader) != "1" {
+		t.Errorf("expected %v header on cached response", FromCacheHeader)
+	}
+	if second.Status != first.Status {
+		t.Errorf("\nExpected status %v\nGot %v", first.Status, second.Status)
+	}
+
+	noCache := get(t, cache, server.URL+"/pypi/cookiecutter?api_key=1234", http.Header{"Cache-Control": {"no-cache"}})
+	if got := body(t, noCache); got != `{"request":2}` {
+		t.Errorf("expected no-cache request to bypass the cache, got %v", got)
+	}
+	if got := body(t, get(t, cache, server.URL+"/pypi/cookiecutter?api_key=1234", nil)); got != `{"request":2}` {
+		t.Errorf("expected no-cache request to update the cache, got %v", got)
+	}
+
+	get(t, cache, server.URL+"/missing", nil)
+	if resp := get(t, cache, server.URL+"/missing", nil); resp.StatusCode != http.StatusNotFound || resp.Header.Get(FromCacheHeader) != "" {
+		t.Errorf("expected errors not to be cached")
+	}
+	if requests != 4 {
+		t.Errorf("expected 4 requests to the server, got %d", requests)
+	}
+}
+
+func TestCache_perAPIKey(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		fmt.Fprintf(w, `{"request":%d}`, requests)
+	}))
+	defer server.Close()
+
+	cache := &Cache{Dir: t.TempDir()}
+
+	testCases := []struct {
+		query  string
+		header http.Header
+		want   string
+	}{
+		{"?api_key=1234", nil, `{"request":1}`},
+		{"?api_key=5678", nil, `{"request":2}`},
+		{"", http.Header{"X-Api-Key": {"1234"}}, `{"request":3}`},
+		{"", http.Header{"X-Api-Key": {"5678"}}, `{"request":4}`},
+		{"", http.Header{"Authorization": {"Bearer 1234"}}, `{"request":5}`},
+		{"?api_key=1234", nil, `{"request":1}`},
+		{"", http.Header{"X-Api-Key": {"5678"}}, `{"request":4}`},
+	}
+	for _, tc := range testCases {
+		resp := get(t, cache, server.URL+"/subscriptions"+tc.query, tc.header)
+		if got := body(t, resp); got != tc.want {
+			t.Errorf("%v %v:\nExpected %v\nGot %v", tc.query, tc.header, tc.want, got)
+		}
+	}
+}
+
+func TestCache_maxAge(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		fmt.Fprint(w, `{}`)
+	}))
+	defer server.Close()
+
+	dir, err := ioutil.TempDir("", "cache")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	cache := &Cache{Dir: dir, MaxAge: time.Nanosecond}
+
+	body(t, get(t, cache, server.URL+"/platforms", nil))
+	time.Sleep(time.Millisecond)
+	body(t, get(t, cache, server.URL+"/platforms", nil))
+
+	if requests != 2 {
+		t.Errorf("expected expired entry to be refreshed, got %d requests", requests)
+	}
+}
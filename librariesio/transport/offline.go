@@ -0,0 +1,109 @@
+package transport
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Offline is a http.RoundTripper which serves responses from JSON files in
+// a directory tree mirroring the API paths, without any network access. The
+// response for
+//
+//	GET https://libraries.io/api/pypi/cookiecutter/latest/dependencies
+//
+// is read from pypi/cookiecutter/latest/dependencies.json in Dir. Escaped
+// path segments are unescaped, so npm/@babel/core.json serves the project
+// @babel/core. The query of the URL is ignored.
+//
+// Requests for missing files are answered with 404 Not Found like unknown
+// resources of the API, other methods than GET with 405 Method Not Allowed.
+// Responses have the FromCacheHeader set.
+type Offline struct {
+	Dir string
+
+	// BaseURL is the base URL of the client, the libraries.io API if nil
+	BaseURL *url.URL
+}
+
+// defaultBaseURL is the base URL of the libraries.io API
+var defaultBaseURL = &url.URL{Scheme: "https", Host: "libraries.io", Path: "/api/"}
+
+// RoundTrip serves the file for the request
+func (o *Offline) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != "GET" {
+		return offlineResponse(req, http.StatusMethodNotAllowed, errorBody("Method Not Allowed")), nil
+	}
+
+	path, err := o.path(req.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return offlineResponse(req, http.StatusNotFound, errorBody("Not Found")), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return offlineResponse(req, http.StatusOK, body), nil
+}
+
+// path returns the path of the file for the URL
+func (o *Offline) path(u *url.URL) (string, error) {
+	base := o.BaseURL
+	if base == nil {
+		base = defaultBaseURL
+	}
+
+	escaped := u.EscapedPath()
+	if !strings.HasPrefix(escaped, base.EscapedPath()) {
+		return "", fmt.Errorf("%v is not below the base URL %v", u.Path, base)
+	}
+
+	segments := strings.Split(strings.Trim(strings.TrimPrefix(escaped, base.EscapedPath()), "/"), "/")
+	for i, s := range segments {
+		unescaped, err := url.PathUnescape(s)
+		if err != nil {
+			return "", fmt.Errorf("invalid path %v: %v", u.Path, err)
+		}
+		for _, part := range strings.Split(unescaped, "/") {
+			if part == "" || part == "." || part == ".." {
+				return "", fmt.Errorf("invalid path %v", u.Path)
+			}
+		}
+		segments[i] = unescaped
+	}
+
+	return filepath.Join(o.Dir, filepath.FromSlash(strings.Join(segments, "/"))+".json"), nil
+}
+
+// errorBody returns an error response body as returned by the API
+func errorBody(message string) []byte {
+	return []byte(fmt.Sprintf(`{"error":%q}`, message))
+}
+
+// offlineResponse returns a JSON response to the request
+func offlineResponse(req *http.Request, status int, body []byte) *http.Response {
+	header := make(http.Header)
+	header.Set("Content-Type", "application/json")
+	header.Set(FromCacheHeader, "1")
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %v", status, http.StatusText(status)),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          ioutil.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
@@ -0,0 +1,79 @@
+package transport
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hackebrot/go-librariesio/librariesio"
+)
+
+// writeFixture writes a fixture file below dir
+func writeFixture(t *testing.T, dir, path, content string) {
+	path = filepath.Join(dir, filepath.FromSlash(path))
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestOffline(t *testing.T) {
+	dir := t.TempDir()
+	writeFixture(t, dir, "pypi/cookiecutter.json", `{"name":"cookiecutter","platform":"Pypi"}`)
+	writeFixture(t, dir, "pypi/cookiecutter/latest/dependencies.json", `{"name":"cookiecutter","dependencies":[{"name":"click"}]}`)
+	writeFixture(t, dir, "npm/@babel/core.json", `{"name":"@babel/core"}`)
+
+	client := librariesio.NewClient("1234")
+	client.SetTransport(&Offline{Dir: dir})
+	ctx := context.Background()
+
+	project, resp, err := client.Projects.Get(ctx, "pypi", "cookiecutter")
+	if err != nil {
+		t.Fatalf("Projects.Get returned error: %v", err)
+	}
+	if *project.Name != "cookiecutter" {
+		t.Errorf("\nExpected %v\nGot %v", "cookiecutter", *project.Name)
+	}
+	if got := resp.Header.Get(FromCacheHeader); got != "1" {
+		t.Errorf("expected %v header, got %q", FromCacheHeader, got)
+	}
+
+	deps, _, err := client.Projects.Deps(ctx, "pypi", "cookiecutter", "latest")
+	if err != nil {
+		t.Fatalf("Projects.Deps returned error: %v", err)
+	}
+	if len(deps.Dependencies) != 1 || *deps.Dependencies[0].Name != "click" {
+		t.Errorf("unexpected dependencies %v", deps.Dependencies)
+	}
+
+	if _, _, err := client.Projects.Get(ctx, "npm", "@babel/core"); err != nil {
+		t.Errorf("Projects.Get returned error for a scoped package: %v", err)
+	}
+
+	_, resp, err = client.Projects.Get(ctx, "pypi", "missing")
+	if err == nil {
+		t.Fatal("Expected error to be returned")
+	}
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("\nExpected %v\nGot %v", http.StatusNotFound, resp.StatusCode)
+	}
+}
+
+func TestOffline_invalidPath(t *testing.T) {
+	o := &Offline{Dir: t.TempDir()}
+
+	for _, u := range []string{
+		"https://libraries.io/api/pypi/..%2F..%2Fsecret",
+		"https://libraries.io/other/pypi/cookiecutter",
+	} {
+		req, _ := http.NewRequest("GET", u, nil)
+		if _, err := o.RoundTrip(req); err == nil {
+			t.Errorf("expected an error for %v", u)
+		}
+	}
+}
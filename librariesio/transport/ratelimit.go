@@ -0,0 +1,58 @@
+package transport
+
+import (
+	"net/http"
+	"sync"
+	"time"
+)
+
+// RateLimit is a http.RoundTripper which delays requests, so they are sent
+// at most once per interval. Waiting is aborted if the context of the
+// request is cancelled.
+type RateLimit struct {
+	interval  time.Duration
+	transport http.RoundTripper
+
+	mu   sync.Mutex
+	next time.Time
+}
+
+// NewRateLimit returns a RateLimit allowing the given number of requests per
+// minute. The requests are sent with transport, or http.DefaultTransport if
+// it is nil.
+func NewRateLimit(perMinute int, transport http.RoundTripper) *RateLimit {
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	r := &RateLimit{transport: transport}
+	if perMinute > 0 {
+		r.interval = time.Minute / time.Duration(perMinute)
+	}
+	return r
+}
+
+// RoundTrip waits for the next free slot and sends the request
+func (r *RateLimit) RoundTrip(req *http.Request) (*http.Response, error) {
+	r.mu.Lock()
+	now := time.Now()
+	slot := r.next
+	if slot.Before(now) {
+		slot = now
+	}
+	r.next = slot.Add(r.interval)
+	r.mu.Unlock()
+
+	if wait := slot.Sub(now); wait > 0 {
+		timer := time.NewTimer(wait)
+		defer timer.Stop()
+
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+	}
+
+	return r.transport.RoundTrip(req)
+}
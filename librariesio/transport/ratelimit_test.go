@@ -0,0 +1,39 @@
+package transport
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRateLimit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	// 1200 requests per minute are one request every 50ms
+	limit := NewRateLimit(1200, nil)
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		body(t, get(t, limit, server.URL, nil))
+	}
+
+	if elapsed := time.Since(start); elapsed < 100*time.Millisecond {
+		t.Errorf("expected requests to be spaced out, took %v", elapsed)
+	}
+}
+
+func TestRateLimit_cancelled(t *testing.T) {
+	limit := NewRateLimit(1, nil)
+	limit.next = time.Now().Add(time.Hour)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	req, _ := http.NewRequest("GET", "http://localhost/", nil)
+	if _, err := limit.RoundTrip(req.WithContext(ctx)); err != context.DeadlineExceeded {
+		t.Errorf("expected %v, got %v", context.DeadlineExceeded, err)
+	}
+}
@@ -0,0 +1,214 @@
+package transport
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sync"
+
+	"github.com/hackebrot/go-librariesio/librariesio"
+)
+
+// Cassette holds recorded API interactions
+type Cassette struct {
+	Interactions []*Interaction `json:"interactions"`
+}
+
+// Interaction is a recorded request and its response
+type Interaction struct {
+	Request  *RecordedRequest  `json:"request"`
+	Response *RecordedResponse `json:"response"`
+}
+
+// RecordedRequest is a request with the api_key redacted from its URL
+type RecordedRequest struct {
+	Method string `json:"method"`
+	URL    string `json:"url"`
+}
+
+// RecordedResponse is a response of the API
+type RecordedResponse struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body"`
+}
+
+// LoadCassette reads a cassette from a JSON file
+func LoadCassette(path string) (*Cassette, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Cassette{}
+	if err := json.Unmarshal(data, c); err != nil {
+		return nil, fmt.Errorf("error parsing cassette %v: %v", path, err)
+	}
+	return c, nil
+}
+
+// Save writes the cassette to a JSON file
+func (c *Cassette) Save(path string) error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, append(data, '\n'), 0644)
+}
+
+// matchKey identifies requests by method, path and all query params except
+// the api_key
+func matchKey(method string, u *url.URL) string {
+	q := u.Query()
+	q.Del("api_key")
+	return method + " " + u.EscapedPath() + "?" + q.Encode()
+}
+
+// Recorder is a http.RoundTripper which sends requests with its transport
+// and records them to a cassette file, which is written after every request.
+// The api_key is redacted from the recorded URLs.
+type Recorder struct {
+	path      string
+	transport http.RoundTripper
+
+	mu       sync.Mutex
+	cassette *Cassette
+}
+
+// NewRecorder returns a Recorder writing to the cassette at path. Requests
+// are sent with transport, or http.DefaultTransport if it is nil.
+func NewRecorder(path string, transport http.RoundTripper) *Recorder {
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	return &Recorder{path: path, transport: transport, cassette: &Cassette{}}
+}
+
+// RoundTrip sends the request and records the interaction
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := r.transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.cassette.Interactions = append(r.cassette.Interactions, &Interaction{
+		Request: &RecordedRequest{
+			Method: req.Method,
+			URL:    librariesio.RedactAPIKey(req.URL).String(),
+		},
+		Response: &RecordedResponse{
+			StatusCode: resp.StatusCode,
+			Header:     resp.Header,
+			Body:       string(body),
+		},
+	})
+
+	if err := r.cassette.Save(r.path); err != nil {
+		return nil, fmt.Errorf("error writing cassette: %v", err)
+	}
+	return resp, nil
+}
+
+// Replayer is a http.RoundTripper which answers requests with the responses
+// of a cassette, without any network access. Requests are matched by
+// method, path and query params other than the api_key. Identical requests
+// are answered in the recorded order, repeating the last response once
+// they are used up.
+//
+// Requests without a recorded interaction fail with an error naming the
+// request, so missing recordings are noticed.
+type Replayer struct {
+	mu           sync.Mutex
+	interactions map[string][]*Interaction
+	served       map[*Interaction]bool
+	all          []*Interaction
+}
+
+// NewReplayer returns a Replayer for the cassette at path
+func NewReplayer(path string) (*Replayer, error) {
+	c, err := LoadCassette(path)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &Replayer{
+		interactions: make(map[string][]*Interaction),
+		served:       make(map[*Interaction]bool),
+		all:          c.Interactions,
+	}
+
+	for _, i := range c.Interactions {
+		u, err := url.Parse(i.Request.URL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid URL in cassette %v: %v", path, err)
+		}
+		key := matchKey(i.Request.Method, u)
+		r.interactions[key] = append(r.interactions[key], i)
+	}
+
+	return r, nil
+}
+
+// RoundTrip returns the recorded response for the request
+func (r *Replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	key := matchKey(req.Method, req.URL)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	recorded := r.interactions[key]
+	if len(recorded) == 0 {
+		return nil, fmt.Errorf("no recorded interaction for %v %v", req.Method, librariesio.RedactAPIKey(req.URL))
+	}
+
+	i := recorded[0]
+	if len(recorded) > 1 {
+		r.interactions[key] = recorded[1:]
+	}
+	r.served[i] = true
+
+	header := make(http.Header)
+	for key, values := range i.Response.Header {
+		header[key] = values
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", i.Response.StatusCode, http.StatusText(i.Response.StatusCode)),
+		StatusCode:    i.Response.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          ioutil.NopCloser(bytes.NewReader([]byte(i.Response.Body))),
+		ContentLength: int64(len(i.Response.Body)),
+		Request:       req,
+	}, nil
+}
+
+// Unused returns the recorded interactions which were never replayed, e.g.
+// to detect stale cassettes
+func (r *Replayer) Unused() []*Interaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var unused []*Interaction
+	for _, i := range r.all {
+		if !r.served[i] {
+			unused = append(unused, i)
+		}
+	}
+	return unused
+}
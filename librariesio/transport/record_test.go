@@ -0,0 +1,119 @@
+package transport
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hackebrot/go-librariesio/librariesio"
+	"github.com/hackebrot/go-librariesio/librariesio/librariesiotest"
+)
+
+func TestRecorderAndReplayer(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cassette")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "cassette.json")
+
+	server := librariesiotest.NewServer(&librariesiotest.Fixtures{
+		Projects: []*librariesiotest.ProjectFixture{
+			{Project: &librariesio.Project{
+				Platform: librariesio.String("Pypi"),
+				Name:     librariesio.String("cookiecutter"),
+				Stars:    librariesio.Int(6000),
+			}},
+		},
+	})
+
+	client := server.NewClient()
+	client.SetTransport(NewRecorder(path, nil))
+
+	if _, _, err := client.Project(context.Background(), "pypi", "cookiecutter"); err != nil {
+		t.Fatalf("Project returned unexpected error: %v", err)
+	}
+	if _, _, err := client.SearchService.Projects(context.Background(), "cookie", &librariesio.SearchOptions{Sort: "stars"}); err != nil {
+		t.Fatalf("Search returned unexpected error: %v", err)
+	}
+	server.Close()
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("cassette was not written: %v", err)
+	}
+	if strings.Contains(string(data), librariesiotest.APIKey) {
+		t.Errorf("cassette contains the API key:\n%s", data)
+	}
+
+	replayer, err := NewReplayer(path)
+	if err != nil {
+		t.Fatalf("NewReplayer returned unexpected error: %v", err)
+	}
+
+	// Replay with a different key and without the server
+	replay := librariesio.NewClient("other-key")
+	replay.BaseURL = client.BaseURL
+	replay.SetTransport(replayer)
+
+	project, _, err := replay.Project(context.Background(), "pypi", "cookiecutter")
+	if err != nil {
+		t.Fatalf("Project returned unexpected error on replay: %v", err)
+	}
+	if *project.Stars != 6000 {
+		t.Errorf("unexpected replayed project %v", project)
+	}
+
+	if unused := replayer.Unused(); len(unused) != 1 {
+		t.Errorf("expected the search to be unused, got %d unused interactions", len(unused))
+	}
+
+	// The query params other than api_key must match
+	_, _, err = replay.SearchService.Projects(context.Background(), "cookie", &librariesio.SearchOptions{Sort: "rank"})
+	if err == nil || !strings.Contains(err.Error(), "no recorded interaction for GET") {
+		t.Errorf("expected error for unmatched request, got %v", err)
+	}
+	if strings.Contains(fmt.Sprint(err), "other-key") {
+		t.Errorf("error contains the API key: %v", err)
+	}
+}
+
+func TestReplayer_order(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cassette")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "cassette.json")
+
+	cassette := &Cassette{}
+	for _, body := range []string{`{"n":1}`, `{"n":2}`} {
+		cassette.Interactions = append(cassette.Interactions, &Interaction{
+			Request:  &RecordedRequest{Method: "GET", URL: "https://libraries.io/api/platforms?api_key=REDACTED"},
+			Response: &RecordedResponse{StatusCode: http.StatusOK, Body: body},
+		})
+	}
+	if err := cassette.Save(path); err != nil {
+		t.Fatal(err)
+	}
+
+	replayer, err := NewReplayer(path)
+	if err != nil {
+		t.Fatalf("NewReplayer returned unexpected error: %v", err)
+	}
+
+	for _, want := range []string{`{"n":1}`, `{"n":2}`, `{"n":2}`} {
+		resp := get(t, replayer, "https://libraries.io/api/platforms?api_key=1234", nil)
+		if resp.Status != "200 OK" {
+			t.Errorf("\nExpected status %v\nGot %v", "200 OK", resp.Status)
+		}
+		if got := body(t, resp); got != want {
+			t.Errorf("expected %v, got %v", want, got)
+		}
+	}
+}
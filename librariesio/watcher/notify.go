@@ -0,0 +1,146 @@
+package watcher
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Writer is a Notifier writing one line per event to W, e.g.
+//
+//	new release pypi/cookiecutter 1.6.0 https://pypi.org/project/cookiecutter/
+//
+// or one JSON object per line if JSON is set
+type Writer struct {
+	W    io.Writer
+	JSON bool
+}
+
+// Notify writes the events
+func (w *Writer) Notify(ctx context.Context, events []*Event) error {
+	for _, e := range events {
+		var err error
+		if w.JSON {
+			err = json.NewEncoder(w.W).Encode(e)
+		} else {
+			_, err = fmt.Fprintln(w.W, strings.TrimSpace("new release "+e.String()+" "+e.URL))
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WebhookPayload is the body posted by a Webhook
+type WebhookPayload struct {
+	Events []*Event `json:"events"`
+}
+
+// Webhook is a Notifier posting the events of a poll as JSON WebhookPayload
+// to URL
+type Webhook struct {
+	URL string
+
+	// Header is added to the requests, e.g. for authentication
+	Header http.Header
+
+	// Client sends the requests and defaults to a client with a timeout of
+	// 30 seconds
+	Client *http.Client
+}
+
+// Notify posts the events
+func (w *Webhook) Notify(ctx context.Context, events []*Event) error {
+	return post(ctx, w.Client, w.URL, w.Header, &WebhookPayload{Events: events})
+}
+
+// SlackPayload is the body posted by Slack, which is understood by Slack
+// incoming webhooks and compatible chat services
+type SlackPayload struct {
+	Text string `json:"text"`
+}
+
+// Slack is a Notifier posting the events of a poll as a single message to a
+// Slack incoming webhook URL
+type Slack struct {
+	URL string
+
+	// Client sends the requests and defaults to a client with a timeout of
+	// 30 seconds
+	Client *http.Client
+}
+
+// Notify posts the events
+func (s *Slack) Notify(ctx context.Context, events []*Event) error {
+	return post(ctx, s.Client, s.URL, nil, &SlackPayload{Text: slackText(events)})
+}
+
+// slackText formats the events as a Slack message, linking the versions to
+// the pages of the projects
+func slackText(events []*Event) string {
+	lines := []string{"New releases on libraries.io:"}
+	for _, e := range events {
+		name := e.Platform + "/" + e.Name
+		if e.URL != "" {
+			name = "<" + e.URL + "|" + name + ">"
+		}
+		lines = append(lines, fmt.Sprintf("• %v %v", name, e.Version))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// defaultClient is used by notifiers without a Client
+var defaultClient = &http.Client{Timeout: 30 * time.Second}
+
+// post sends payload as JSON to the URL and fails on non-2xx responses.
+// Errors only include the scheme and host of the URL.
+func post(ctx context.Context, client *http.Client, rawURL string, header http.Header, payload interface{}) error {
+	if client == nil {
+		client = defaultClient
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("POST", rawURL, bytes.NewReader(body))
+	if err != nil {
+		// The parse error includes the URL with its secret
+		return fmt.Errorf("invalid webhook URL")
+	}
+	req = req.WithContext(ctx)
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		if urlErr, ok := err.(*url.Error); ok {
+			urlErr.URL = redactURL(req.URL)
+		}
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(ioutil.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("error posting to %v: %v", redactURL(req.URL), resp.Status)
+	}
+	return nil
+}
+
+// redactURL returns only the scheme and host of a webhook URL, as webhooks
+// such as those of Slack carry their secret in the path
+func redactURL(u *url.URL) string {
+	return u.Scheme + "://" + u.Host
+}
@@ -0,0 +1,116 @@
+package watcher
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hackebrot/go-librariesio/librariesio"
+)
+
+var testEvents = []*Event{
+	{
+		ProjectRef: librariesio.ProjectRef{Platform: "pypi", Name: "cookiecutter"},
+		Version:    "1.6.0",
+		URL:        "https://pypi.org/project/cookiecutter/",
+	},
+}
+
+func TestWriter(t *testing.T) {
+	var buf bytes.Buffer
+	if err := (&Writer{W: &buf}).Notify(context.Background(), testEvents); err != nil {
+		t.Fatalf("Notify returned error: %v", err)
+	}
+	want := "new release pypi/cookiecutter 1.6.0 https://pypi.org/project/cookiecutter/\n"
+	if got := buf.String(); got != want {
+		t.Errorf("\nExpected %q\nGot %q", want, got)
+	}
+
+	buf.Reset()
+	if err := (&Writer{W: &buf, JSON: true}).Notify(context.Background(), testEvents); err != nil {
+		t.Fatalf("Notify returned error: %v", err)
+	}
+	want = `{"platform":"pypi","name":"cookiecutter","version":"1.6.0","url":"https://pypi.org/project/cookiecutter/"}` + "\n"
+	if got := buf.String(); got != want {
+		t.Errorf("\nExpected %q\nGot %q", want, got)
+	}
+}
+
+// receive starts a server responding with status, which decodes the posted
+// body into v and copies the request header into header
+func receive(t *testing.T, status int, v interface{}, header http.Header) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" {
+			t.Errorf("\nExpected POST\nGot %v", r.Method)
+		}
+		if got := r.Header.Get("Content-Type"); got != "application/json" {
+			t.Errorf("\nExpected application/json\nGot %v", got)
+		}
+		for key, values := range r.Header {
+			header[key] = values
+		}
+		if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+			t.Errorf("error decoding body: %v", err)
+		}
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestWebhook(t *testing.T) {
+	payload := &WebhookPayload{}
+	header := http.Header{}
+	server := receive(t, http.StatusNoContent, payload, header)
+
+	webhook := &Webhook{URL: server.URL, Header: http.Header{"Authorization": {"Bearer token"}}}
+	if err := webhook.Notify(context.Background(), testEvents); err != nil {
+		t.Fatalf("Notify returned error: %v", err)
+	}
+	if !reflect.DeepEqual(payload.Events, testEvents) {
+		t.Errorf("\nExpected %v\nGot %v", testEvents, payload.Events)
+	}
+	if got := header.Get("Authorization"); got != "Bearer token" {
+		t.Errorf("\nExpected Bearer token\nGot %v", got)
+	}
+}
+
+func TestSlack(t *testing.T) {
+	payload := &SlackPayload{}
+	server := receive(t, http.StatusOK, payload, http.Header{})
+
+	if err := (&Slack{URL: server.URL}).Notify(context.Background(), testEvents); err != nil {
+		t.Fatalf("Notify returned error: %v", err)
+	}
+	want := "New releases on libraries.io:\n• <https://pypi.org/project/cookiecutter/|pypi/cookiecutter> 1.6.0"
+	if payload.Text != want {
+		t.Errorf("\nExpected %q\nGot %q", want, payload.Text)
+	}
+}
+
+func TestSlack_error(t *testing.T) {
+	server := receive(t, http.StatusNotFound, &SlackPayload{}, http.Header{})
+	secret := "/services/T0000/B0000/secret"
+
+	err := (&Slack{URL: server.URL + secret}).Notify(context.Background(), testEvents)
+	if err == nil {
+		t.Fatalf("expected an error for a 404 response")
+	}
+	if strings.Contains(err.Error(), "secret") {
+		t.Errorf("expected the path of the URL to be redacted, got %v", err)
+	}
+
+	server.Close()
+	err = (&Slack{URL: server.URL + secret}).Notify(context.Background(), testEvents)
+	if err == nil {
+		t.Fatalf("expected an error for a closed server")
+	}
+	if strings.Contains(err.Error(), "secret") {
+		t.Errorf("expected the path of the URL to be redacted, got %v", err)
+	}
+}
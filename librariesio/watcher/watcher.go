@@ -0,0 +1,331 @@
+/*
+Package watcher notifies about new releases of projects on libraries.io,
+without relying on the emails of subscriptions.
+
+A Watcher polls the projects, compares their versions with those seen by the
+previous poll and sends an Event for every new version to its notifiers. The
+versions seen are persisted in a state file, so releases published while the
+watcher was not running are reported by the next poll:
+
+	w := watcher.New(client.Projects, refs, &watcher.Options{
+		StatePath: "watch.json",
+		Notifiers: []watcher.Notifier{
+			&watcher.Writer{W: os.Stdout},
+			&watcher.Slack{URL: slackWebhookURL},
+		},
+	})
+	err := w.Run(ctx)
+
+The first poll of a project records its versions without sending events.
+*/
+package watcher
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/hackebrot/go-librariesio/librariesio"
+)
+
+// DefaultInterval is the default time between two polls
+const DefaultInterval = time.Hour
+
+// DefaultMaxPending is the default number of events kept for a notifier
+// which failed to deliver them
+const DefaultMaxPending = 1000
+
+// Event is a new release of a watched project
+type Event struct {
+	librariesio.ProjectRef
+
+	Version     string     `json:"version"`
+	PublishedAt *time.Time `json:"published_at,omitempty"`
+
+	// URL is the page of the project on its package manager
+	URL string `json:"url,omitempty"`
+}
+
+// String describes the event, e.g. "pypi/cookiecutter 1.6.0"
+func (e *Event) String() string {
+	return e.Platform + "/" + e.Name + " " + e.Version
+}
+
+// Notifier delivers events
+type Notifier interface {
+	Notify(ctx context.Context, events []*Event) error
+}
+
+// Options configure a Watcher
+type Options struct {
+	// Interval is the time between two polls, DefaultInterval if zero
+	Interval time.Duration
+
+	// StatePath is the file the versions seen are persisted in. The state
+	// is only kept in memory if it is empty.
+	StatePath string
+
+	Notifiers []Notifier
+
+	// MaxPending is the number of events kept for a notifier which failed to
+	// deliver them, DefaultMaxPending if zero. The oldest events are dropped
+	// when a notifier fails for longer.
+	MaxPending int
+
+	// Logger reports events which are dropped, slog.Default() if nil
+	Logger *slog.Logger
+
+	// OnError is called by Run with the errors of a poll
+	OnError func(err error)
+}
+
+// State holds the versions seen of each project, keyed by platform/name with
+// a lower case platform
+type State struct {
+	Projects map[string][]string `json:"projects"`
+}
+
+// LoadState reads the state file at path. A missing file is an empty state.
+func LoadState(path string) (*State, error) {
+	s := &State{Projects: make(map[string][]string)}
+
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, fmt.Errorf("error parsing %v: %v", path, err)
+	}
+	if s.Projects == nil {
+		s.Projects = make(map[string][]string)
+	}
+	return s, nil
+}
+
+// Save writes the state to the file at path. The file is replaced at once,
+// so an interrupted watcher doesn't leave a partial state behind.
+func (s *State) Save(path string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// stateKey returns the key of the project in the state
+func stateKey(ref librariesio.ProjectRef) string {
+	return strings.ToLower(ref.Platform) + "/" + ref.Name
+}
+
+// Watcher polls projects for new releases
+type Watcher struct {
+	client   librariesio.ProjectsAPI
+	projects []librariesio.ProjectRef
+	opt      Options
+
+	state *State
+
+	// pending holds the events each notifier failed to deliver, by the
+	// index of the notifier
+	pending [][]*Event
+}
+
+// New returns a Watcher for the projects. Call Run to start polling.
+// Projects listed more than once are only polled once.
+func New(c librariesio.ProjectsAPI, projects []librariesio.ProjectRef, opt *Options) *Watcher {
+	var unique []librariesio.ProjectRef
+	seen := make(map[librariesio.ProjectRef]bool)
+	for _, ref := range projects {
+		if !seen[ref] {
+			seen[ref] = true
+			unique = append(unique, ref)
+		}
+	}
+
+	w := &Watcher{
+		client:   c,
+		projects: unique,
+	}
+	if opt != nil {
+		w.opt = *opt
+	}
+	if w.opt.Interval <= 0 {
+		w.opt.Interval = DefaultInterval
+	}
+	if w.opt.MaxPending <= 0 {
+		w.opt.MaxPending = DefaultMaxPending
+	}
+	if w.opt.Logger == nil {
+		w.opt.Logger = slog.Default()
+	}
+	w.pending = make([][]*Event, len(w.opt.Notifiers))
+	return w
+}
+
+// Run polls the projects until the context is cancelled. Errors of a poll
+// don't stop the watcher, they are retried by the next poll. Only an
+// unreadable state file is returned as an error.
+func (w *Watcher) Run(ctx context.Context) error {
+	for {
+		if _, err := w.Poll(ctx); err != nil {
+			if w.state == nil {
+				return err
+			}
+			if w.opt.OnError != nil && ctx.Err() == nil {
+				w.opt.OnError(err)
+			}
+		}
+
+		timer := time.NewTimer(w.opt.Interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil
+		case <-timer.C:
+		}
+	}
+}
+
+// Poll fetches the projects once, sends the events for new versions to the
+// notifiers and saves the state. It returns the events and the errors of
+// projects which could not be fetched or of notifiers, joined with
+// errors.Join.
+//
+// Events are delivered to each notifier separately. If a notifier fails,
+// its events are sent to it again with those of the next poll of the
+// Watcher, while the other notifiers only get them once. At most
+// Options.MaxPending events are kept for a notifier. Events which are still
+// pending when the Watcher stops are lost.
+func (w *Watcher) Poll(ctx context.Context) ([]*Event, error) {
+	if w.state == nil {
+		state := &State{Projects: make(map[string][]string)}
+		if w.opt.StatePath != "" {
+			var err error
+			if state, err = LoadState(w.opt.StatePath); err != nil {
+				return nil, err
+			}
+		}
+		w.state = state
+	}
+
+	var errs []error
+	events := []*Event{}
+	seen := make(map[string][]string)
+
+	for _, ref := range w.projects {
+		project, _, err := w.client.Get(ctx, ref.Platform, ref.Name)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("error fetching %v/%v: %v", ref.Platform, ref.Name, err))
+			continue
+		}
+
+		key := stateKey(ref)
+		known, polled := w.state.Projects[key]
+		versions := versions(project)
+		seen[key] = versions
+		if !polled {
+			continue
+		}
+
+		old := make(map[string]bool)
+		for _, v := range known {
+			old[v] = true
+		}
+		for _, r := range project.Versions {
+			if r != nil && r.Number != nil && !old[*r.Number] {
+				events = append(events, newEvent(ref, project, *r.Number, r.PublishedAt))
+				old[*r.Number] = true
+			}
+		}
+		if latest := project.LatestReleaseNumber; latest != nil && !old[*latest] {
+			events = append(events, newEvent(ref, project, *latest, project.LatestReleasePublishedAt))
+		}
+	}
+
+	for i, n := range w.opt.Notifiers {
+		batch := append(w.pending[i], events...)
+		if len(batch) == 0 {
+			continue
+		}
+		if err := n.Notify(ctx, batch); err != nil {
+			errs = append(errs, err)
+			if dropped := len(batch) - w.opt.MaxPending; dropped > 0 {
+				w.opt.Logger.Warn("dropping pending events of notifier",
+					"notifier", fmt.Sprintf("%T", n), "dropped", dropped, "oldest", batch[0].String())
+				batch = batch[dropped:]
+			}
+			w.pending[i] = batch
+			continue
+		}
+		w.pending[i] = nil
+	}
+
+	for key, versions := range seen {
+		w.state.Projects[key] = versions
+	}
+	if w.opt.StatePath != "" {
+		if err := w.state.Save(w.opt.StatePath); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return events, errors.Join(errs...)
+}
+
+// versions returns the version numbers of the project including the latest
+// release, which may not be listed in Versions
+func versions(p *librariesio.Project) []string {
+	var numbers []string
+	for _, r := range p.Versions {
+		if r != nil && r.Number != nil {
+			numbers = append(numbers, *r.Number)
+		}
+	}
+	if latest := p.LatestReleaseNumber; latest != nil {
+		found := false
+		for _, n := range numbers {
+			found = found || n == *latest
+		}
+		if !found {
+			numbers = append(numbers, *latest)
+		}
+	}
+	return numbers
+}
+
+// newEvent returns the event for a new version of the project
+func newEvent(ref librariesio.ProjectRef, p *librariesio.Project, version string, publishedAt *time.Time) *Event {
+	e := &Event{ProjectRef: ref, Version: version, PublishedAt: publishedAt}
+	if p.PackageManagerURL != nil {
+		e.URL = *p.PackageManagerURL
+	}
+	return e
+}
@@ -0,0 +1,218 @@
+package watcher
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hackebrot/go-librariesio/librariesio"
+	"github.com/hackebrot/go-librariesio/librariesio/librariesiotest"
+)
+
+// recorder is a Notifier recording the events
+type recorder struct {
+	events []*Event
+	err    error
+}
+
+func (r *recorder) Notify(ctx context.Context, events []*Event) error {
+	if r.err != nil {
+		return r.err
+	}
+	r.events = append(r.events, events...)
+	return nil
+}
+
+func cookiecutter(versions ...string) *librariesiotest.ProjectFixture {
+	p := &librariesio.Project{
+		Platform:            librariesio.String("Pypi"),
+		Name:                librariesio.String("cookiecutter"),
+		PackageManagerURL:   librariesio.String("https://pypi.org/project/cookiecutter/"),
+		LatestReleaseNumber: librariesio.String(versions[len(versions)-1]),
+	}
+	for _, v := range versions {
+		p.Versions = append(p.Versions, &librariesio.Release{Number: librariesio.String(v)})
+	}
+	return &librariesiotest.ProjectFixture{Project: p}
+}
+
+func TestWatcher_Poll(t *testing.T) {
+	server := librariesiotest.NewServer(&librariesiotest.Fixtures{
+		Projects: []*librariesiotest.ProjectFixture{cookiecutter("1.5.0", "1.5.1")},
+	})
+	defer server.Close()
+
+	ref := librariesio.ProjectRef{Platform: "pypi", Name: "cookiecutter"}
+	statePath := filepath.Join(t.TempDir(), "state", "watch.json")
+	r := &recorder{}
+	w := New(server.NewClient().Projects, []librariesio.ProjectRef{ref}, &Options{
+		StatePath: statePath,
+		Notifiers: []Notifier{r},
+	})
+
+	events, err := w.Poll(context.Background())
+	if err != nil {
+		t.Fatalf("Poll returned error: %v", err)
+	}
+	if len(events) != 0 {
+		t.Errorf("expected no events for the first poll, got %v", events)
+	}
+
+	server.AddProject(cookiecutter("1.5.0", "1.5.1", "1.6.0"))
+
+	// a new watcher continues from the state file
+	w = New(server.NewClient().Projects, []librariesio.ProjectRef{ref}, &Options{
+		StatePath: statePath,
+		Notifiers: []Notifier{r},
+	})
+	events, err = w.Poll(context.Background())
+	if err != nil {
+		t.Fatalf("Poll returned error: %v", err)
+	}
+	want := []*Event{{ProjectRef: ref, Version: "1.6.0", URL: "https://pypi.org/project/cookiecutter/"}}
+	if !reflect.DeepEqual(events, want) {
+		t.Errorf("\nExpected %v\nGot %v", want, events)
+	}
+	if !reflect.DeepEqual(r.events, want) {
+		t.Errorf("\nExpected %v\nGot %v", want, r.events)
+	}
+
+	if events, _ := w.Poll(context.Background()); len(events) != 0 {
+		t.Errorf("expected no events without new versions, got %v", events)
+	}
+
+	state, err := LoadState(statePath)
+	if err != nil {
+		t.Fatalf("LoadState returned error: %v", err)
+	}
+	if want := []string{"1.5.0", "1.5.1", "1.6.0"}; !reflect.DeepEqual(state.Projects["pypi/cookiecutter"], want) {
+		t.Errorf("\nExpected %v\nGot %v", want, state.Projects["pypi/cookiecutter"])
+	}
+}
+
+func TestWatcher_Poll_notifierFails(t *testing.T) {
+	server := librariesiotest.NewServer(&librariesiotest.Fixtures{
+		Projects: []*librariesiotest.ProjectFixture{cookiecutter("1.5.1")},
+	})
+	defer server.Close()
+
+	refs := []librariesio.ProjectRef{
+		{Platform: "pypi", Name: "cookiecutter"},
+		{Platform: "pypi", Name: "missing"},
+	}
+	failing, working := &recorder{}, &recorder{}
+	w := New(server.NewClient().Projects, refs, &Options{Notifiers: []Notifier{failing, working}})
+
+	if _, err := w.Poll(context.Background()); err == nil {
+		t.Errorf("expected an error for the missing project")
+	}
+
+	server.AddProject(cookiecutter("1.5.1", "1.6.0"))
+	failing.err = errors.New("unavailable")
+	if _, err := w.Poll(context.Background()); !errors.Is(err, failing.err) {
+		t.Errorf("\nExpected %v\nGot %v", failing.err, err)
+	}
+
+	// the events are only sent again to the notifier which failed
+	server.AddProject(cookiecutter("1.5.1", "1.6.0", "1.7.0"))
+	failing.err = nil
+	events, _ := w.Poll(context.Background())
+	if len(events) != 1 || events[0].Version != "1.7.0" {
+		t.Errorf("expected an event for 1.7.0, got %v", events)
+	}
+
+	versions := func(events []*Event) []string {
+		var v []string
+		for _, e := range events {
+			v = append(v, e.Version)
+		}
+		return v
+	}
+	if got, want := versions(failing.events), []string{"1.6.0", "1.7.0"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("\nExpected %v\nGot %v", want, got)
+	}
+	if got, want := versions(working.events), []string{"1.6.0", "1.7.0"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("\nExpected %v\nGot %v", want, got)
+	}
+
+	if _, err := w.Poll(context.Background()); len(failing.events) != 2 || len(working.events) != 2 {
+		t.Errorf("expected no events to be sent again, got %v %v (%v)", failing.events, working.events, err)
+	}
+}
+
+func TestWatcher_Poll_duplicates(t *testing.T) {
+	server := librariesiotest.NewServer(&librariesiotest.Fixtures{
+		Projects: []*librariesiotest.ProjectFixture{cookiecutter("1.5.1")},
+	})
+	defer server.Close()
+
+	ref := librariesio.ProjectRef{Platform: "pypi", Name: "cookiecutter"}
+	w := New(server.NewClient().Projects, []librariesio.ProjectRef{ref, ref}, nil)
+	if _, err := w.Poll(context.Background()); err != nil {
+		t.Fatalf("Poll returned error: %v", err)
+	}
+
+	server.AddProject(cookiecutter("1.5.1", "1.6.0"))
+	events, err := w.Poll(context.Background())
+	if err != nil {
+		t.Fatalf("Poll returned error: %v", err)
+	}
+	want := []*Event{{ProjectRef: ref, Version: "1.6.0", URL: "https://pypi.org/project/cookiecutter/"}}
+	if !reflect.DeepEqual(events, want) {
+		t.Errorf("\nExpected %v\nGot %v", want, events)
+	}
+}
+
+func TestWatcher_Poll_maxPending(t *testing.T) {
+	server := librariesiotest.NewServer(&librariesiotest.Fixtures{
+		Projects: []*librariesiotest.ProjectFixture{cookiecutter("1.0.0")},
+	})
+	defer server.Close()
+
+	var log bytes.Buffer
+	failing := &recorder{err: errors.New("unavailable")}
+	w := New(server.NewClient().Projects, []librariesio.ProjectRef{{Platform: "pypi", Name: "cookiecutter"}}, &Options{
+		Notifiers:  []Notifier{failing},
+		MaxPending: 2,
+		Logger:     slog.New(slog.NewTextHandler(&log, nil)),
+	})
+	if _, err := w.Poll(context.Background()); err != nil {
+		t.Fatalf("Poll returned error: %v", err)
+	}
+
+	versions := []string{"1.0.0"}
+	for _, v := range []string{"1.1.0", "1.2.0", "1.3.0"} {
+		versions = append(versions, v)
+		server.AddProject(cookiecutter(versions...))
+		w.Poll(context.Background())
+	}
+
+	failing.err = nil
+	w.Poll(context.Background())
+
+	var got []string
+	for _, e := range failing.events {
+		got = append(got, e.Version)
+	}
+	if want := []string{"1.2.0", "1.3.0"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("\nExpected %v\nGot %v", want, got)
+	}
+	if !strings.Contains(log.String(), "dropping pending events") || !strings.Contains(log.String(), "oldest=\"pypi/cookiecutter 1.1.0\"") {
+		t.Errorf("expected the dropped events to be logged, got %q", log.String())
+	}
+}
+
+func TestVersions_null(t *testing.T) {
+	p := &librariesio.Project{
+		Versions:            []*librariesio.Release{nil, {Number: librariesio.String("1.5.1")}, {}},
+		LatestReleaseNumber: librariesio.String("1.6.0"),
+	}
+	if got, want := versions(p), []string{"1.5.1", "1.6.0"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("\nExpected %v\nGot %v", want, got)
+	}
+}